@@ -0,0 +1,377 @@
+// commands.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"signalshield/modules"
+)
+
+// Command is one entry in the agent's command registry. ProcessTask dispatches
+// through the registry and /commands lists it, so the two can't drift apart.
+type Command struct {
+	Name        string                                                   `json:"name"`
+	Aliases     []string                                                 `json:"aliases,omitempty"`
+	Description string                                                   `json:"description"`
+	Usage       string                                                   `json:"usage"`
+	Handler     func(ctx context.Context, args []string) (string, error) `json:"-"`
+	// DryRun validates args and describes what Handler would do without side
+	// effects. Nil means the command is read-only and Handler runs as usual.
+	DryRun func(ctx context.Context, args []string) (string, error) `json:"-"`
+	// Needs lists the external services the reply depends on, for the mode banner.
+	Needs modules.Dependency `json:"-"`
+}
+
+const defaultCommandTimeout = 30 * time.Second
+
+// commandTimeout is the per-command deadline (COMMAND_TIMEOUT, default 30s).
+func commandTimeout() time.Duration {
+	if d, err := time.ParseDuration(strings.TrimSpace(os.Getenv("COMMAND_TIMEOUT"))); err == nil && d > 0 {
+		return d
+	}
+	return defaultCommandTimeout
+}
+
+// errCommandTimeout is returned by runWithTimeout when a handler overruns COMMAND_TIMEOUT.
+var errCommandTimeout = errors.New("command timed out")
+
+// CommandError is a real failure of a command (upstream API down, timeout),
+// as opposed to unknown-command and usage problems, which are ordinary
+// replies with a nil error.
+type CommandError struct {
+	Command string
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	return e.Command + ": " + e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// commandErrorReply is the user-facing reply for a failed task.
+func commandErrorReply(err error) string {
+	var ce *CommandError
+	if errors.As(err, &ce) && errors.Is(err, errCommandTimeout) {
+		return fmt.Sprintf("'%s' didn't finish within %s and was canceled. Please try again shortly.", ce.Command, commandTimeout())
+	}
+	return modules.UserFacingError(err)
+}
+
+// monitors backs the monitor command; set in main before the agent starts.
+var monitors *modules.MonitorManager
+
+// alerts backs the alert command; set in main before the agent starts.
+var alerts *modules.AlertManager
+
+// commands is the registry, in the order shown to users.
+var commands = []Command{
+	{
+		Name:        "scan",
+		Description: "Scan a token for hype, sentiment, KOL mentions and risk",
+		Usage:       "scan [token]",
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunScan(ctx, args)
+		},
+	},
+	{
+		Name:        "monitor",
+		Description: "Track a token and alert on price/hype threshold crossings",
+		Usage:       "monitor [token] [interval_sec] [above=price] [below=price] [hype=0..1] [ttl=24h] | monitor list | monitor stop [token] | monitor renew [token] [ttl]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunMonitor(ctx, monitors, args)
+		},
+		DryRun: func(ctx context.Context, args []string) (string, error) {
+			return modules.DryRunMonitor(ctx, monitors, args)
+		},
+	},
+	{
+		Name:        "riskcheck",
+		Description: "Risk score and red flags for a token",
+		Usage:       "riskcheck [token]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunRiskCheck(ctx, args)
+		},
+	},
+	{
+		Name:        "hype",
+		Description: "Hype score from price momentum and volume",
+		Usage:       "hype [token]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunHype(ctx, args)
+		},
+	},
+	{
+		Name:        "balance",
+		Description: "Risk-hype balancer: combined verdict weighted by risk aversion",
+		Usage:       "balance [token] [risk_aversion]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunBalance(ctx, args)
+		},
+	},
+	{
+		Name:        "signal",
+		Description: "Aggregated signals corroborated across KOLs and sources",
+		Usage:       "signal [token]",
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunSignal(ctx, args)
+		},
+	},
+	{
+		Name:        "dumpalert",
+		Description: "Check tokens for dumps, with thresholds scaled by market cap",
+		Usage:       "dumpalert [token...]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunDumpAlert(ctx, args)
+		},
+	},
+	{
+		Name:        "topcalls",
+		Description: "Latest KOL early calls",
+		Usage:       "topcalls",
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunTopCalls()
+		},
+	},
+	{
+		Name:        "sentiment",
+		Description: "Positive/negative sentiment split for a token, or the ranked watchlist",
+		Usage:       "sentiment [token|all]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunSentiment(ctx, args)
+		},
+	},
+	{
+		Name:        "watch",
+		Description: "Watch a KOL for significant activity",
+		Usage:       "watch [KOL]",
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunWatch(args)
+		},
+	},
+	{
+		Name:        "summary",
+		Description: "Daily summary of signals and trending coins",
+		Usage:       "summary",
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunSummary()
+		},
+	},
+	{
+		Name:        "digest",
+		Description: "Digest of detections over a period: top tokens, KOLs, movers and alerts",
+		Usage:       "digest [period, e.g. 24h or 7d]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunDigest(ctx, args)
+		},
+	},
+	{
+		Name:        "marketcap",
+		Description: "Market cap in USD",
+		Usage:       "marketcap [token]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			if len(args) == 0 {
+				return "Usage: marketcap [token]", nil
+			}
+			return modules.GetMarketCapContext(ctx, strings.Join(args, ""))
+		},
+	},
+	{
+		Name:        "volume",
+		Description: "24h trading volume in USD",
+		Usage:       "volume [token]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			if len(args) == 0 {
+				return "Usage: volume [token]", nil
+			}
+			return modules.GetVolumeContext(ctx, strings.Join(args, ""))
+		},
+	},
+	{
+		Name:        "price",
+		Description: "Current price in USD",
+		Usage:       "price [token]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			if len(args) == 0 {
+				return "Usage: price [token]", nil
+			}
+			return modules.GetCoinPriceContext(ctx, strings.Join(args, ""))
+		},
+	},
+	{
+		Name:        "gecko",
+		Aliases:     []string{"geckosnapshot"},
+		Description: "CoinGecko snapshot: price, 24h change, volume, market cap (--extended adds 7d/30d and a sparkline)",
+		Usage:       "gecko [id_or_symbol] [--extended]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			args, extended := splitFlag(args, "--extended")
+			if len(args) == 0 {
+				return "Usage: gecko [id_or_symbol] [--extended]", nil
+			}
+			if extended {
+				res, err := modules.GetCoinGeckoExtendedContext(ctx, strings.Join(args, ""))
+				if err != nil {
+					return "", err
+				}
+				return modules.FormatCoinGeckoExtended(res), nil
+			}
+			res, err := modules.GetCoinGeckoFullContext(ctx, strings.Join(args, ""))
+			if err != nil {
+				return "", err
+			}
+			// FormatCoinGeckoSummary returns string -> must return (string, nil)
+			return modules.FormatCoinGeckoSummary(res), nil
+		},
+	},
+	{
+		Name:        "trend",
+		Description: "Short trend description from the 24h move",
+		Usage:       "trend [token]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			if len(args) == 0 {
+				return "Usage: trend [token]", nil
+			}
+			// GetTrendSnapshot returns (string, error) so just forward it
+			return modules.GetTrendSnapshotContext(ctx, strings.Join(args, ""))
+		},
+	},
+	{
+		Name:        "alert",
+		Description: "Create a price/condition alert that re-arms only after a reset margin",
+		Usage:       "alert [token] [condition] [reset=2%] | alert list | alert remove [id]",
+		Needs:       modules.NeedsMarket,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return modules.RunAlert(ctx, alerts, args)
+		},
+		DryRun: func(ctx context.Context, args []string) (string, error) {
+			return modules.DryRunAlert(ctx, alerts, args)
+		},
+	},
+	{
+		Name:        "subscribe",
+		Description: "Subscribe to alerts",
+		Usage:       "subscribe",
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return "Subscribe (mock): done", nil
+		},
+		DryRun: func(ctx context.Context, args []string) (string, error) {
+			return "Dry run: would subscribe you to alerts. Nothing was changed.", nil
+		},
+	},
+	{
+		Name:        "unsubscribe",
+		Description: "Unsubscribe from alerts",
+		Usage:       "unsubscribe",
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			return "Unsubscribe (mock): done", nil
+		},
+		DryRun: func(ctx context.Context, args []string) (string, error) {
+			return "Dry run: would unsubscribe you from alerts. Nothing was changed.", nil
+		},
+	},
+	{
+		Name:        "ai",
+		Description: "Ask the AI backend (Gemini or OpenAI) a free-form question",
+		Usage:       "ai [--no-cache] [instruction]",
+		Needs:       modules.NeedsAI,
+		Handler: func(ctx context.Context, args []string) (string, error) {
+			// forward natural language instruction to GPT module
+			args, noCache := splitFlag(args, "--no-cache")
+			if len(args) == 0 {
+				return "Usage: ai [--no-cache] [instruction]", nil
+			}
+			instr := strings.Join(args, " ")
+			// IMPORTANT: ForwardToOpenAI in modules now prioritizes GOOGLE_API_KEY (if set)
+			if !modules.CurrentConfig().AIConfigured() {
+				return "AI backend not configured. Set GOOGLE_API_KEY or OPENAI_API_KEY in .env", nil
+			}
+			opts := modules.ChatAIOptions()
+			opts.NoCache = noCache
+			resp, err := modules.ForwardWithOptionsContext(ctx, instr, opts)
+			if err != nil {
+				return "", err
+			}
+			return resp, nil
+		},
+		DryRun: func(ctx context.Context, args []string) (string, error) {
+			args, _ = splitFlag(args, "--no-cache")
+			if len(args) == 0 {
+				return "Usage: ai [--no-cache] [instruction]", nil
+			}
+			if !modules.CurrentConfig().AIConfigured() {
+				return "AI backend not configured. Set GOOGLE_API_KEY or OPENAI_API_KEY in .env", nil
+			}
+			opts := modules.ChatAIOptions()
+			return fmt.Sprintf("Dry run: would send a %d-character prompt to the AI backend (max %d tokens). Nothing was sent.", len(strings.Join(args, " ")), opts.MaxTokens), nil
+		},
+	},
+}
+
+// splitFlag removes flag (e.g. "--no-cache") from args and reports whether it was present.
+func splitFlag(args []string, flag string) ([]string, bool) {
+	out := make([]string, 0, len(args))
+	found := false
+	for _, a := range args {
+		if a == flag {
+			found = true
+			continue
+		}
+		out = append(out, a)
+	}
+	return out, found
+}
+
+// findCommand looks up a command by name or alias.
+func findCommand(name string) (Command, bool) {
+	for _, c := range commands {
+		if c.Name == name {
+			return c, true
+		}
+		for _, alias := range c.Aliases {
+			if alias == name {
+				return c, true
+			}
+		}
+	}
+	return Command{}, false
+}
+
+// commandNames returns the comma separated list used in help replies.
+func commandNames() string {
+	names := make([]string, 0, len(commands))
+	for _, c := range commands {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// commandsHandler serves GET /commands with the registry as JSON.
+func commandsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(commands)
+}
@@ -0,0 +1,189 @@
+// debug.go
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"signalshield/modules"
+)
+
+// requireDebugAuth guards sensitive routes. When DEBUG_TOKEN is set the request
+// must carry "Authorization: Bearer <token>"; when unset the route is open.
+func requireDebugAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimSpace(os.Getenv("DEBUG_TOKEN"))
+		if token != "" {
+			got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// cacheDebugHandler serves GET /debug/cache: market-data cache counters and entries.
+func cacheDebugHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"stats":   modules.GetCacheStats(),
+		"entries": modules.DumpCache(),
+	})
+}
+
+// aiCacheClearHandler serves POST /debug/ai-cache/clear: drops all cached AI replies.
+func aiCacheClearHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := modules.ClearAICache(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"cleared": true})
+}
+
+// selfTestHandler serves GET /selftest: every integration check as JSON.
+// Responds 503 when any check fails so it can back a readiness probe. It is
+// deliberately not a chat command: the checks spend AI quota and a CoinGecko
+// call and report BACKEND_URL, so they stay behind requireDebugAuth.
+func selfTestHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	results := modules.SelfTest(r.Context())
+	status := http.StatusOK
+	for _, res := range results {
+		if res.Status == "fail" {
+			status = http.StatusServiceUnavailable
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(results)
+}
+
+// Page size bounds for GET /detections.
+const (
+	defaultDetectionsLimit = 50
+	maxDetectionsLimit     = 500
+)
+
+// detectionsHandler serves GET /detections: stored detections filtered by
+// token, kol, signal, since (24h or RFC 3339) and min_confidence, sorted by
+// sort=timestamp|confidence with order=desc (default) or asc, paged with
+// offset and limit.
+func detectionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	opts, err := parseDetectionFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dets, err := modules.LoadDetections(modules.DetectionLogPath(), opts.Since)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page, total := modules.FilterDetections(dets, opts)
+	if page == nil {
+		page = []modules.Detection{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"total":      total,
+		"offset":     opts.Offset,
+		"limit":      opts.Limit,
+		"detections": page,
+	})
+}
+
+func parseDetectionFilter(q url.Values) (modules.FilterOpts, error) {
+	opts := modules.FilterOpts{
+		Token:  q.Get("token"),
+		KOL:    q.Get("kol"),
+		Signal: q.Get("signal"),
+		SortBy: q.Get("sort"),
+		Limit:  defaultDetectionsLimit,
+	}
+	switch q.Get("order") {
+	case "", "desc":
+	case "asc":
+		opts.Ascending = true
+	default:
+		return opts, fmt.Errorf("order must be asc or desc")
+	}
+	if s := q.Get("since"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil {
+			opts.Since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, s); err == nil {
+			opts.Since = t
+		} else {
+			return opts, fmt.Errorf("since %q must be a duration like 24h or an RFC 3339 time", s)
+		}
+	}
+	if s := q.Get("min_confidence"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return opts, fmt.Errorf("min_confidence %q must be a number", s)
+		}
+		opts.MinConfidence = v
+	}
+	for name, dst := range map[string]*int{"offset": &opts.Offset, "limit": &opts.Limit} {
+		if s := q.Get(name); s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return opts, fmt.Errorf("%s %q must be an integer", name, s)
+			}
+			*dst = v
+		}
+	}
+	if opts.Limit == 0 || opts.Limit > maxDetectionsLimit {
+		opts.Limit = maxDetectionsLimit
+	}
+	return opts, opts.Validate()
+}
+
+// logsHandler serves GET /logs: the last LOG_BUFFER_SIZE log lines as a JSON
+// array, newest first (?limit=N for fewer). 404 when the buffer is off.
+func logsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	limit := 0
+	if s := r.URL.Query().Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		limit = v
+	}
+	lines, ok := modules.RecentLogs(limit)
+	if !ok {
+		http.Error(w, "log buffer disabled (set LOG_BUFFER_SIZE)", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lines)
+}
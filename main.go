@@ -12,106 +12,164 @@ import (
 	"time"
 
 	"signalshield/modules"
+	"signalshield/pkg/agent"
+	"signalshield/pkg/health"
+	"signalshield/pkg/network"
 
-	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/agent"
 	"github.com/joho/godotenv"
 )
 
-type SignalshieldAnalystAgent struct{}
+type SignalshieldAnalystAgent struct {
+	replyMaxChars int // REPLY_MAX_CHARS, 0 = no truncation
+}
 
 func (a *SignalshieldAnalystAgent) ProcessTask(ctx context.Context, task string) (string, error) {
+	start := time.Now()
+	reply, err := a.handleTask(ctx, task)
+	auditTask(ctx, task, start, err)
+	if err != nil {
+		// keep the details in the log, give the user something actionable
+		log.Printf("Task %q failed: %v", task, err)
+		reply = commandErrorReply(err)
+	}
+	return modules.TruncateReply(reply, a.replyMaxChars), nil
+}
+
+func (a *SignalshieldAnalystAgent) handleTask(ctx context.Context, task string) (string, error) {
 	log.Printf("Processing task: %s", task)
 
+	cmd, args, dryRun := parseTask(task)
+	if cmd == "" {
+		return "No command provided. Available commands: " + commandNames(), nil
+	}
+	c, ok := findCommand(cmd)
+	if !ok {
+		recordCommand(unknownCommandLabel, nil)
+		return fmt.Sprintf("Unknown command '%s'. Available commands: %s", cmd, commandNames()), nil
+	}
+	run := c.Handler
+	if dryRun && c.DryRun != nil {
+		run = c.DryRun
+	}
+	reply, err := runWithTimeout(ctx, c.Name, run, args)
+	recordCommand(c.Name, err)
+	if err != nil {
+		return "", &CommandError{Command: c.Name, Err: err}
+	}
+	return modules.WithModeBanner(c.Needs, reply), nil
+}
+
+// parseTask splits a task into its lower-cased command name and arguments. A
+// leading "?" or a --dry-run flag validates the command without side effects.
+func parseTask(task string) (cmd string, args []string, dryRun bool) {
 	task = strings.TrimSpace(task)
 	task = strings.TrimPrefix(task, "/")
+	dryRun = strings.HasPrefix(task, "?")
+	if dryRun {
+		task = strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(task, "?")), "/")
+	}
 	parts := strings.Fields(task)
 	if len(parts) == 0 {
-		return "No command provided. Available commands: scan, monitor, riskcheck, hype, signal, dumpalert, topcalls, sentiment, watch, summary, marketcap, volume, price, gecko, trend, alert, subscribe, unsubscribe, ai", nil
-	}
-	cmd := strings.ToLower(parts[0])
-	args := parts[1:]
-
-	switch cmd {
-	case "scan":
-		return modules.RunScan(args)
-	case "monitor":
-		return "Monitor command (mock): started (use /monitor <keyword>)", nil
-	case "riskcheck":
-		return modules.RunRiskCheck(args)
-	case "hype":
-		return modules.RunHype(args)
-	case "signal":
-		return "Latest signals: 3 new early calls, 1 dump alert (mock).", nil
-	case "dumpalert":
-		return "Dump alert check: no immediate dump signals detected (mock).", nil
-	case "topcalls":
-		return modules.RunTopCalls()
-	case "sentiment":
-		return modules.RunSentiment(args)
-	case "watch":
-		return modules.RunWatch(args)
-	case "summary":
-		return modules.RunSummary()
-	case "marketcap":
-		if len(args) == 0 {
-			return "Usage: marketcap [token]", nil
-		}
-		return modules.GetMarketCap(strings.Join(args, ""))
-	case "volume":
-		if len(args) == 0 {
-			return "Usage: volume [token]", nil
-		}
-		return modules.GetVolume(strings.Join(args, ""))
-	case "price":
-		if len(args) == 0 {
-			return "Usage: price [token]", nil
-		}
-		return modules.GetCoinPrice(strings.Join(args, ""))
-	case "gecko", "geckosnapshot":
-		if len(args) == 0 {
-			return "Usage: gecko [id_or_symbol]", nil
-		}
-		res, err := modules.GetCoinGeckoFull(strings.Join(args, ""))
-		if err != nil {
-			return "", err
-		}
-		// FormatCoinGeckoSummary returns string -> must return (string, nil)
-		return modules.FormatCoinGeckoSummary(res), nil
-	case "trend":
-		if len(args) == 0 {
-			return "Usage: trend [token]", nil
-		}
-		// GetTrendSnapshot returns (string, error) so just forward it
-		return modules.GetTrendSnapshot(strings.Join(args, ""))
-	case "alert":
-		return "Alert command (mock): created (use alert [token] [condition])", nil
-	case "subscribe":
-		return "Subscribe (mock): done", nil
-	case "unsubscribe":
-		return "Unsubscribe (mock): done", nil
-	case "ai":
-		// forward natural language instruction to GPT module
-		if len(args) == 0 {
-			return "Usage: ai [instruction]", nil
-		}
-		instr := strings.Join(args, " ")
-		// IMPORTANT: ForwardToOpenAI in modules now prioritizes GOOGLE_API_KEY (if set)
-		if os.Getenv("GOOGLE_API_KEY") == "" && os.Getenv("OPENAI_API_KEY") == "" {
-			return "AI backend not configured. Set GOOGLE_API_KEY or OPENAI_API_KEY in .env", nil
+		return "", nil, dryRun
+	}
+	args = make([]string, 0, len(parts)-1)
+	for _, a := range parts[1:] {
+		if a == "--dry-run" {
+			dryRun = true
+			continue
 		}
-		resp, err := modules.ForwardToOpenAI(instr)
-		if err != nil {
-			return "", err
+		args = append(args, a)
+	}
+	return strings.ToLower(parts[0]), args, dryRun
+}
+
+// auditTask appends the outcome of one task to AUDIT_LOG, when set. Arguments
+// are redacted; a failed write is logged and never fails the task.
+func auditTask(ctx context.Context, task string, start time.Time, err error) {
+	path := modules.AuditLogPath()
+	if path == "" {
+		return
+	}
+	cmd, args, dryRun := parseTask(task)
+	rec := modules.AuditRecord{
+		Time:       start.UTC(),
+		Command:    cmd,
+		Args:       modules.RedactArgs(args),
+		Caller:     modules.AuditCaller(ctx),
+		DryRun:     dryRun,
+		Success:    err == nil,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if c, ok := findCommand(cmd); ok {
+		rec.Command = c.Name
+	} else if err == nil {
+		rec.Success = false
+		rec.Error = "unknown command"
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	if werr := modules.AppendAudit(path, rec); werr != nil {
+		log.Printf("audit log: %v", werr)
+	}
+}
+
+// enrichBatchMax caps how many queued detections share one enrichment fetch.
+const enrichBatchMax = 50
+
+// drainDetections appends detections already waiting on ch to batch, without
+// blocking, until batch holds max.
+func drainDetections(batch []modules.Detection, ch <-chan modules.Detection, max int) []modules.Detection {
+	for len(batch) < max {
+		select {
+		case d, ok := <-ch:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, d)
+		default:
+			return batch
 		}
-		return resp, nil
-	default:
-		return fmt.Sprintf("Unknown command '%s'. Available commands: scan, monitor, riskcheck, hype, signal, dumpalert, topcalls, sentiment, watch, summary, marketcap, volume, price, gecko, trend, alert, subscribe, unsubscribe, ai", cmd), nil
+	}
+	return batch
+}
+
+type commandResult struct {
+	reply string
+	err   error
+}
+
+// runWithTimeout runs a command handler under COMMAND_TIMEOUT. Handlers get the
+// deadline through ctx; one that ignores it is abandoned with errCommandTimeout,
+// so a slow API can't hold up the task queue.
+func runWithTimeout(ctx context.Context, name string, run func(context.Context, []string) (string, error), args []string) (string, error) {
+	timeout := commandTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan commandResult, 1)
+	go func() {
+		reply, err := run(ctx, args)
+		done <- commandResult{reply, err}
+	}()
+	select {
+	case r := <-done:
+		return r.reply, r.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("%w after %s", errCommandTimeout, timeout)
 	}
 }
 
 func main() {
 	// Load .env if available
 	_ = godotenv.Load()
+	modules.SetConfig(modules.LoadConfig())
+	modules.InstallLogBuffer(modules.CurrentConfig().LogBufferSize)
+
+	// "replay [file]" re-scores stored detections offline and exits
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplay(os.Args[2:]))
+	}
 
 	// Basic config & env
 	rateLimitStr := os.Getenv("RATE_LIMIT_PER_MINUTE")
@@ -122,20 +180,13 @@ func main() {
 		}
 	}
 
-	pollInterval := 30
-	if s := os.Getenv("X_POLL_INTERVAL"); s != "" {
+	scannerCfg := loadScannerConfig()
+	pollInterval, kols := scannerCfg.IntervalSec, scannerCfg.KOLs
+	applyCacheTTL()
+	replyMaxChars := 0
+	if s := os.Getenv("REPLY_MAX_CHARS"); s != "" {
 		if v, err := strconv.Atoi(s); err == nil {
-			pollInterval = v
-		}
-	}
-	kols := []string{"Ansem", "GCR", "TheMoonCarl"} // default
-	if s := os.Getenv("KOL_LIST"); s != "" {
-		parts := strings.Split(s, ",")
-		for i := range parts {
-			parts[i] = strings.TrimSpace(parts[i])
-		}
-		if len(parts) > 0 {
-			kols = parts
+			replyMaxChars = v
 		}
 	}
 	xBearer := os.Getenv("X_BEARER_TOKEN")
@@ -156,18 +207,21 @@ func main() {
 	config.OwnerAddress = os.Getenv("OWNER_ADDRESS")
 	config.RateLimitPerMinute = rateLimit
 
+	if errs := ValidateConfig(config); len(errs) > 0 {
+		for _, err := range errs {
+			log.Println("config error:", err)
+		}
+		log.Fatalf("invalid configuration (%d problem(s)); fix the settings above and restart", len(errs))
+	}
+
 	enhancedAgent, err := agent.NewEnhancedAgent(&agent.EnhancedAgentConfig{
 		Config:       config,
-		AgentHandler: &SignalshieldAnalystAgent{},
+		AgentHandler: &SignalshieldAnalystAgent{replyMaxChars: replyMaxChars},
 	})
 	if err != nil {
 		log.Fatal("agent.NewEnhancedAgent:", err)
 	}
 
-	log.Println("Starting SignalShield Analyst...")
-	// run agent in goroutine so we can also start scanner & detection loop
-	go enhancedAgent.Run()
-
 	// Create context for scanner & detector
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -175,46 +229,135 @@ func main() {
 	// detection channel
 	detectCh := make(chan modules.Detection, 16)
 
-	// start scanner (xscanner)
-	go modules.StartXScanner(ctx, pollInterval, kols, xBearer, source, mock, detectCh)
+	// token monitors (persisted, restored on restart) run under the supervisor
+	monitors = modules.NewMonitorManager(modules.MonitorStatePath(), detectCh)
+	supervisor := network.NewGoroutineSupervisor(ctx)
+	if err := supervisor.Register("token-monitors", "Token monitors", monitors.Run, network.DefaultRestartPolicy()); err != nil {
+		log.Fatal("supervisor.Register:", err)
+	}
+	alerts = modules.NewAlertManager(modules.AlertStatePath(), detectCh)
+	if err := supervisor.Register("price-alerts", "Price alerts", alerts.Run, network.DefaultRestartPolicy()); err != nil {
+		log.Fatal("supervisor.Register:", err)
+	}
+	if err := supervisor.Register("detection-log-rotation", "Detection log rotation", modules.RunDetectionLogRotation, network.DefaultRestartPolicy()); err != nil {
+		log.Fatal("supervisor.Register:", err)
+	}
+	if period, sink, ok := modules.DigestSchedule(); ok {
+		if err := supervisor.Register("digest", "Scheduled digest", modules.DigestRunner(period, sink), network.DefaultRestartPolicy()); err != nil {
+			log.Fatal("supervisor.Register:", err)
+		}
+	}
+	if err := supervisor.Start(); err != nil {
+		log.Fatal("supervisor.Start:", err)
+	}
+	defer supervisor.Stop()
+
+	log.Println("Starting SignalShield Analyst...")
+	// run agent in goroutine so we can also start scanner & detection loop
+	go enhancedAgent.Run()
 
-	// goroutine to handle detections
+	// start scanner (xscanner); SIGHUP hot-reloads KOLs / interval / cache TTL
+	scannerReload := make(chan modules.ScannerConfig, 1)
+	go modules.StartXScanner(ctx, pollInterval, kols, xBearer, source, mock, detectCh, scannerReload)
+	go watchReload(ctx, scannerReload)
+
+	// fan detections out so a slow summarizer never holds up logging
+	broker = modules.NewDetectionBroker(detectCh)
+	logged, _ := broker.Subscribe("logger", 64, modules.BlockWhenFull)
+	toSummarize, _ := broker.Subscribe("summarizer", 16, modules.DropWhenFull)
+	go broker.Run(ctx)
+
+	// goroutine to persist detections
 	go func() {
-		for d := range detectCh {
-			// Save detection to file (modules.SaveDetection expects Detection)
-			if err := modules.SaveDetection("alerts.log", d); err != nil {
-				log.Println("Warning: SaveDetection failed:", err)
+		for d := range logged {
+			batch := []modules.Detection{d}
+			if modules.DetectionEnrichEnabled() {
+				// enrich whatever is queued with one batch market fetch
+				batch = modules.EnrichDetections(drainDetections(batch, logged, enrichBatchMax))
 			}
-			// optional: forward text to model pipeline for short summary (non-blocking)
-			go func(det modules.Detection) {
-				// prefer GOOGLE_API_KEY if set, otherwise OPENAI_API_KEY
-				if os.Getenv("GOOGLE_API_KEY") == "" && os.Getenv("OPENAI_API_KEY") == "" {
-					return
+			for _, d := range batch {
+				// Save detection to file (modules.SaveDetection expects Detection)
+				if err := modules.SaveDetection("alerts.log", d); err != nil {
+					log.Println("Warning: SaveDetection failed:", err)
 				}
-				res, err := modules.ForwardToOpenAI(det.Text)
-				if err != nil {
-					log.Println("ForwardToOpenAI err:", err)
-					return
+				// keep history for velocity / top calls
+				if err := modules.AppendDetection(modules.DetectionLogPath(), d); err != nil {
+					log.Println("Warning: AppendDetection failed:", err)
 				}
-				log.Println("[xscanner] GPT summary:", res)
-			}(d)
+			}
+		}
+	}()
+
+	// goroutine to forward detections to the model pipeline for a short summary
+	go func() {
+		for det := range toSummarize {
+			// prefer GOOGLE_API_KEY if set, otherwise OPENAI_API_KEY
+			if !modules.CurrentConfig().AIConfigured() {
+				continue
+			}
+			res, err := modules.SummarizeDetectionStructured(det)
+			if err != nil && modules.IsRetryable(err) {
+				// rate limit / transient failure: retry once after a short pause
+				time.Sleep(5 * time.Second)
+				res, err = modules.SummarizeDetectionStructured(det)
+			}
+			if err != nil {
+				log.Println("SummarizeDetectionStructured err:", err)
+				continue
+			}
+			if res.Blocked {
+				log.Printf("[xscanner] GPT summary for %s withheld by the provider content filter", det.ID)
+				continue
+			}
+			log.Printf("[xscanner] GPT summary: sentiment=%s risk=%v confidence=%.2f structured=%v: %s",
+				res.Sentiment, res.RiskFlag, res.Confidence, res.Structured, res.Summary)
+			if err := modules.AttachSummary(modules.DetectionLogPath(), det, res.Summary); err != nil {
+				log.Println("Warning: AttachSummary failed:", err)
+			}
 		}
 	}()
 
 	// health server (simple)
-	httpPort := "8080"
+	httpPort := 8080
 	if p := os.Getenv("HEALTH_PORT"); p != "" {
-		httpPort = p
+		if v, err := strconv.Atoi(p); err == nil {
+			httpPort = v
+		}
+	}
+	// bind up front: an occupied port is fatal unless HEALTH_PORT_FALLBACK allows another
+	fallback, _ := strconv.Atoi(os.Getenv("HEALTH_PORT_FALLBACK"))
+	ln, httpPort, err := health.ListenWithFallback(httpPort, fallback)
+	if err != nil {
+		log.Fatalf("HTTP server: %v (set HEALTH_PORT to a free port or HEALTH_PORT_FALLBACK to try the next ones)", err)
 	}
 	// Provide a very small health endpoint (so curl http://localhost:8080/health works)
 	go func() {
-		ln := ":" + httpPort
-		log.Printf("HTTP server listening on :%s", httpPort)
+		log.Printf("HTTP server listening on :%d", httpPort)
+		http.HandleFunc("/commands", commandsHandler)
+		http.HandleFunc("/debug/cache", requireDebugAuth(cacheDebugHandler))
+		http.HandleFunc("/debug/ai-cache/clear", requireDebugAuth(aiCacheClearHandler))
+		http.HandleFunc("/selftest", requireDebugAuth(selfTestHandler))
+		http.HandleFunc("/detections", requireDebugAuth(detectionsHandler))
+		http.HandleFunc("/logs", requireDebugAuth(logsHandler))
+		http.HandleFunc("/metrics", requireDebugAuth(metricsHandler))
 		http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+			// what the scanner runs with now, so a SIGHUP reload shows up here
+			sc := modules.ActiveScannerConfig()
+			if sc.IntervalSec == 0 {
+				sc = scannerCfg // scanner goroutine not started yet
+			}
 			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte(fmt.Sprintf(`{"agent":"%s","status":"healthy","timestamp":"%s","kols":%q,"mock":%v,"pollSec":%d}`, config.Name, time.Now().UTC().Format(time.RFC3339), kols, mock, pollInterval)))
+			w.Write([]byte(fmt.Sprintf(`{"agent":"%s","status":"healthy","timestamp":"%s","kols":%q,"mock":%v,"pollSec":%d}`, config.Name, time.Now().UTC().Format(time.RFC3339), sc.KOLs, mock, sc.IntervalSec)))
 		})
-		if err := http.ListenAndServe(ln, nil); err != nil {
+		// optional TLS so /health and /debug routes aren't served in the clear
+		certFile, keyFile := os.Getenv("HEALTH_TLS_CERT"), os.Getenv("HEALTH_TLS_KEY")
+		var err error
+		if certFile != "" && keyFile != "" {
+			err = http.ServeTLS(ln, nil, certFile, keyFile)
+		} else {
+			err = http.Serve(ln, nil)
+		}
+		if err != nil {
 			log.Println("health server error:", err)
 		}
 	}()
@@ -0,0 +1,129 @@
+// metrics.go
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"signalshield/modules"
+)
+
+// broker fans out detections; set in main, nil until then.
+var broker *modules.DetectionBroker
+
+// unknownCommandLabel groups unknown commands so user input can't create
+// unbounded metric labels.
+const unknownCommandLabel = "_unknown"
+
+// commandMetrics counts dispatched commands and their failures.
+var commandMetrics = struct {
+	mu       sync.Mutex
+	total    map[string]int64 // by command
+	errors   map[string]int64 // by command
+	byReason map[string]int64 // by errorReason
+}{total: map[string]int64{}, errors: map[string]int64{}, byReason: map[string]int64{}}
+
+// recordCommand counts one dispatch of command and, when err is non-nil, its failure.
+func recordCommand(command string, err error) {
+	commandMetrics.mu.Lock()
+	defer commandMetrics.mu.Unlock()
+	commandMetrics.total[command]++
+	if err != nil {
+		commandMetrics.errors[command]++
+		commandMetrics.byReason[errorReason(err)]++
+	}
+}
+
+// errorReason classifies a command failure for the error-rate metrics.
+func errorReason(err error) string {
+	var re *modules.RetryableError
+	var ae *modules.AIError
+	switch {
+	case errors.Is(err, errCommandTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &re) && re.StatusCode == http.StatusTooManyRequests:
+		return "rate_limited"
+	case errors.As(err, &ae), errors.Is(err, modules.ErrNoAIKey), errors.Is(err, modules.ErrContentBlocked):
+		return "ai"
+	case errors.As(err, &re):
+		return "upstream"
+	case errors.Is(err, modules.ErrNotFound):
+		return "not_found"
+	}
+	return "other"
+}
+
+// snapshotCommandMetrics copies the counters for rendering.
+func snapshotCommandMetrics() (total, errs, byReason map[string]int64) {
+	commandMetrics.mu.Lock()
+	defer commandMetrics.mu.Unlock()
+	total, errs, byReason = map[string]int64{}, map[string]int64{}, map[string]int64{}
+	for k, v := range commandMetrics.total {
+		total[k] = v
+	}
+	for k, v := range commandMetrics.errors {
+		errs[k] = v
+	}
+	for k, v := range commandMetrics.byReason {
+		byReason[k] = v
+	}
+	return total, errs, byReason
+}
+
+// metricsHandler serves GET /metrics in the Prometheus text format.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m := modules.GetScannerMetrics()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	writeCounter(w, "signalshield_scanner_ticks_total", "Scanner ticks processed.", m.Ticks)
+	writeCounter(w, "signalshield_scanner_detections_total", "Detections emitted by the scanner.", m.Emitted)
+	writeLabeledCounter(w, "signalshield_scanner_dropped_total", "Detections dropped by the scanner.", "reason", m.Dropped)
+	writeLabeledCounter(w, "signalshield_scanner_detections_by_kol_total", "Detections emitted per KOL.", "kol", m.ByKOL)
+	writeLabeledCounter(w, "signalshield_scanner_detections_by_token_total", "Detections emitted per token.", "token", m.ByToken)
+	writeLabeledCounter(w, "signalshield_scanner_detections_by_source_total", "Detections emitted per source.", "source", m.BySource)
+
+	total, errs, byReason := snapshotCommandMetrics()
+	writeLabeledCounter(w, "signalshield_commands_total", "Commands dispatched.", "command", total)
+	writeLabeledCounter(w, "signalshield_command_errors_total", "Commands that failed.", "command", errs)
+	writeLabeledCounter(w, "signalshield_command_errors_by_reason_total", "Command failures by cause.", "reason", byReason)
+
+	if broker != nil {
+		delivered, dropped := map[string]int64{}, map[string]int64{}
+		for _, s := range broker.Stats() {
+			delivered[s.Name], dropped[s.Name] = s.Delivered, s.Dropped
+		}
+		writeLabeledCounter(w, "signalshield_broker_delivered_total", "Detections delivered per subscriber.", "subscriber", delivered)
+		writeLabeledCounter(w, "signalshield_broker_dropped_total", "Detections dropped for slow subscribers.", "subscriber", dropped)
+	}
+}
+
+func writeCounter(w io.Writer, name, help string, v int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, v)
+}
+
+func writeLabeledCounter(w io.Writer, name, help, label string, values map[string]int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", name, label, escapeLabel(k), values[k])
+	}
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,88 @@
+package modules
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"signalshield/pkg/cache"
+)
+
+// aiCacheKeyPrefix namespaces AI replies so ClearAICache can drop them with
+// one DeletePattern, whatever cache backs them.
+const aiCacheKeyPrefix = "ai:"
+
+const defaultAICacheTTL = 10 * time.Minute
+
+var (
+	aiCacheMu sync.RWMutex
+	aiCache   cache.AgentCache = cache.NewMemoryCache()
+)
+
+// SetAICache replaces the store for cached AI replies (in-memory by default),
+// e.g. with a RedisCache shared between agent instances.
+func SetAICache(c cache.AgentCache) {
+	aiCacheMu.Lock()
+	defer aiCacheMu.Unlock()
+	aiCache = c
+}
+
+func currentAICache() cache.AgentCache {
+	aiCacheMu.RLock()
+	defer aiCacheMu.RUnlock()
+	return aiCache
+}
+
+// aiCacheTTL is how long identical prompts reuse a reply (AI_CACHE_TTL,
+// default 10m; 0 disables the cache).
+func aiCacheTTL() time.Duration {
+	return cfg().AICacheTTL
+}
+
+// aiCacheKey hashes everything that shapes the reply, so a changed system
+// prompt or setting never hits an old answer.
+func aiCacheKey(req aiRequest) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00%g\x00%t", req.systemPrompt, req.prompt, req.maxTokens, req.temperature, req.jsonMode)
+	return aiCacheKeyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedAI returns the cached reply for req, or calls fetch and caches its
+// result. noCache skips the lookup but still stores the fresh reply, so the
+// next cached call sees it. Cache errors only cost a lookup; they never fail
+// the AI call.
+func cachedAI(ctx context.Context, req aiRequest, noCache bool, fetch func() (string, error)) (string, bool, error) {
+	ttl := aiCacheTTL()
+	if ttl == 0 {
+		resp, err := fetch()
+		return resp, false, err
+	}
+	c := currentAICache()
+	key := aiCacheKey(req)
+	if !noCache {
+		if resp, err := c.Get(ctx, key); err == nil {
+			return resp, true, nil
+		}
+	}
+	resp, err := fetch()
+	if err != nil {
+		return "", false, err
+	}
+	if err := c.Set(ctx, key, resp, ttl); err != nil {
+		log.Printf("ai cache: store failed: %v", err)
+	}
+	return resp, false, nil
+}
+
+// ClearAICache drops every cached AI reply, for when the underlying data
+// changed but prompts did not.
+func ClearAICache(ctx context.Context) error {
+	if err := currentAICache().DeletePattern(ctx, aiCacheKeyPrefix+"*"); err != nil {
+		return fmt.Errorf("clear ai cache: %w", err)
+	}
+	return nil
+}
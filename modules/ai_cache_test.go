@@ -0,0 +1,37 @@
+package modules
+
+import (
+	"context"
+	"testing"
+
+	"signalshield/pkg/cache"
+)
+
+func TestCachedAI(t *testing.T) {
+	SetAICache(cache.NewMemoryCache())
+	t.Cleanup(func() { SetAICache(cache.NewMemoryCache()) })
+	ctx := context.Background()
+	req, err := newAIRequest("summarize SOL", AIOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	fetch := func() (string, error) {
+		calls++
+		return "reply", nil
+	}
+
+	cachedAI(ctx, req, false, fetch)
+	if _, hit, _ := cachedAI(ctx, req, false, fetch); !hit || calls != 1 {
+		t.Errorf("second call: hit=%v calls=%d, want cache hit after 1 call", hit, calls)
+	}
+	if _, hit, _ := cachedAI(ctx, req, true, fetch); hit || calls != 2 {
+		t.Errorf("no-cache: hit=%v calls=%d, want fresh call", hit, calls)
+	}
+	if err := ClearAICache(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, hit, _ := cachedAI(ctx, req, false, fetch); hit || calls != 3 {
+		t.Errorf("after clear: hit=%v calls=%d, want fresh call", hit, calls)
+	}
+}
@@ -0,0 +1,52 @@
+package modules
+
+import "unicode/utf8"
+
+// Default AI_CONTEXT_TOKENS per provider: well inside the models' context
+// windows (Gemini Flash, gpt-4o-mini) so long exchanges stay fast and cheap.
+const (
+	defaultGoogleContextTokens = 32000
+	defaultOpenAIContextTokens = 16000
+)
+
+// ConversationTurn is one message of a multi-turn AI exchange.
+type ConversationTurn struct {
+	Role string `json:"role"` // "user" or "assistant"
+	Text string `json:"text"`
+}
+
+// EstimateTokens approximates the token count of s (about 4 characters per
+// token for English text); good enough to stay clear of context limits.
+func EstimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + 3) / 4
+}
+
+// AIContextBudget is the token budget for one request's prompt context with
+// provider ("google" or "openai"): AI_CONTEXT_TOKENS if set, otherwise the
+// provider default.
+func AIContextBudget(provider string) int {
+	if n := cfg().AIContextTokens; n > 0 {
+		return n
+	}
+	if provider == "openai" {
+		return defaultOpenAIContextTokens
+	}
+	return defaultGoogleContextTokens
+}
+
+// TrimTurns drops the oldest turns until the system prompt, the remaining
+// turns and maxTokens reserved for the reply fit in budget. The latest turn
+// is always kept, even if it alone is over budget. turns is not modified.
+func TrimTurns(systemPrompt string, turns []ConversationTurn, maxTokens, budget int) []ConversationTurn {
+	used := EstimateTokens(systemPrompt) + maxTokens
+	start := len(turns)
+	for start > 0 {
+		n := EstimateTokens(turns[start-1].Text)
+		if start < len(turns) && used+n > budget {
+			break
+		}
+		used += n
+		start--
+	}
+	return turns[start:]
+}
@@ -0,0 +1,26 @@
+package modules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrimTurns(t *testing.T) {
+	turn := func(role string, tokens int) ConversationTurn {
+		return ConversationTurn{Role: role, Text: strings.Repeat("abcd", tokens)}
+	}
+	turns := []ConversationTurn{turn("user", 50), turn("assistant", 300), turn("user", 40), turn("assistant", 30), turn("user", 20)}
+	system := strings.Repeat("abcd", 10)
+
+	// 10 system + 100 reply + 20 + 30 + 40 = 200; the 300-token turn no longer fits
+	got := TrimTurns(system, turns, 100, 250)
+	if len(got) != 3 || got[0].Text != turns[2].Text {
+		t.Errorf("kept %d turns, want the latest 3", len(got))
+	}
+	if got := TrimTurns(system, turns, 100, 1000); len(got) != len(turns) {
+		t.Errorf("kept %d turns under a roomy budget, want all %d", len(got), len(turns))
+	}
+	if got := TrimTurns(system, turns, 100, 10); len(got) != 1 || got[0].Text != turns[4].Text {
+		t.Errorf("over budget: kept %d turns, want only the latest", len(got))
+	}
+}
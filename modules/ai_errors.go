@@ -0,0 +1,121 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AIError is a non-2xx response from an AI provider, parsed from its error body.
+type AIError struct {
+	Provider   string // "google" or "openai"
+	StatusCode int
+	Code       string // Gemini error.status (e.g. INVALID_ARGUMENT), OpenAI error.code or error.type
+	Message    string // provider message, or the raw body when it couldn't be parsed
+}
+
+func (e *AIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s api error: status %d %s: %s", e.Provider, e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s api error: status %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// InvalidKey reports whether the provider rejected the API key.
+func (e *AIError) InvalidKey() bool {
+	code := strings.ToLower(e.Code)
+	return e.StatusCode == http.StatusUnauthorized ||
+		strings.Contains(code, "api_key") ||
+		strings.Contains(strings.ToLower(e.Message), "api key not valid")
+}
+
+// ModelNotFound reports whether the requested model doesn't exist.
+func (e *AIError) ModelNotFound() bool {
+	return e.StatusCode == http.StatusNotFound || e.Code == "model_not_found"
+}
+
+// parseAIError builds the error for a non-2xx provider response, marking it
+// retryable on 429/5xx like statusError does.
+func parseAIError(provider string, status int, body []byte) error {
+	e := &AIError{Provider: provider, StatusCode: status}
+
+	var parsed struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"` // Gemini
+			Code    any    `json:"code"`   // OpenAI: string; Gemini: the HTTP code
+			Type    string `json:"type"`   // OpenAI
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil && parsed.Error.Message != "" {
+		e.Message = parsed.Error.Message
+		if code, ok := parsed.Error.Code.(string); ok {
+			e.Code = code
+		}
+		if e.Code == "" {
+			e.Code = parsed.Error.Status
+		}
+		if e.Code == "" {
+			e.Code = parsed.Error.Type
+		}
+	} else {
+		e.Message = sanitizeForLog(strings.TrimSpace(string(body)))
+	}
+
+	if status == http.StatusTooManyRequests || status >= 500 {
+		return &RetryableError{StatusCode: status, Err: e}
+	}
+	return e
+}
+
+// geminiBlockedFinish are Gemini finishReasons meaning the reply was withheld.
+var geminiBlockedFinish = map[string]bool{
+	"SAFETY":             true,
+	"RECITATION":         true,
+	"BLOCKLIST":          true,
+	"PROHIBITED_CONTENT": true,
+	"SPII":               true,
+	"IMAGE_SAFETY":       true,
+}
+
+// geminiBlocked returns a wrapped ErrContentBlocked if a Gemini response was
+// blocked, either on the prompt (promptFeedback.blockReason) or on the first
+// candidate's finishReason.
+func geminiBlocked(parsed map[string]interface{}) error {
+	if fb, ok := parsed["promptFeedback"].(map[string]interface{}); ok {
+		if reason, ok := fb["blockReason"].(string); ok && reason != "" {
+			return fmt.Errorf("%w: google prompt blocked (%s)", ErrContentBlocked, reason)
+		}
+	}
+	if cands, ok := parsed["candidates"].([]interface{}); ok && len(cands) > 0 {
+		if cand0, ok := cands[0].(map[string]interface{}); ok {
+			if reason, ok := cand0["finishReason"].(string); ok && geminiBlockedFinish[reason] {
+				return fmt.Errorf("%w: google finishReason %s", ErrContentBlocked, reason)
+			}
+		}
+	}
+	return nil
+}
+
+// openAIBlocked returns a wrapped ErrContentBlocked if the first choice was
+// cut by the content filter or is a refusal.
+func openAIBlocked(parsed map[string]interface{}) error {
+	choices, ok := parsed["choices"].([]interface{})
+	if !ok || len(choices) == 0 {
+		return nil
+	}
+	ch0, ok := choices[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if reason, _ := ch0["finish_reason"].(string); reason == "content_filter" {
+		return fmt.Errorf("%w: openai finish_reason content_filter", ErrContentBlocked)
+	}
+	if msg, ok := ch0["message"].(map[string]interface{}); ok {
+		if refusal, _ := msg["refusal"].(string); refusal != "" {
+			return fmt.Errorf("%w: openai refusal: %s", ErrContentBlocked, sanitizeForLog(refusal))
+		}
+	}
+	return nil
+}
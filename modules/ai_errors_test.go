@@ -0,0 +1,129 @@
+package modules
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseAIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   string
+		status     int
+		body       string
+		wantCode   string
+		wantMsg    string
+		invalidKey bool
+		retryable  bool
+	}{
+		{
+			name: "gemini invalid key", provider: "google", status: 400,
+			body:     `{"error":{"code":400,"message":"API key not valid. Please pass a valid API key.","status":"INVALID_ARGUMENT"}}`,
+			wantCode: "INVALID_ARGUMENT", wantMsg: "API key not valid. Please pass a valid API key.", invalidKey: true,
+		},
+		{
+			name: "openai invalid key", provider: "openai", status: 401,
+			body:     `{"error":{"message":"Incorrect API key provided","type":"invalid_request_error","param":null,"code":"invalid_api_key"}}`,
+			wantCode: "invalid_api_key", wantMsg: "Incorrect API key provided", invalidKey: true,
+		},
+		{
+			name: "openai rate limit", provider: "openai", status: 429,
+			body:     `{"error":{"message":"Rate limit reached","type":"requests","code":null}}`,
+			wantCode: "requests", wantMsg: "Rate limit reached", retryable: true,
+		},
+		{
+			name: "unparseable body", provider: "google", status: 502,
+			body:    `<html>bad gateway</html>`,
+			wantMsg: "<html>bad gateway</html>", retryable: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseAIError(tt.provider, tt.status, []byte(tt.body))
+			var ae *AIError
+			if !errors.As(err, &ae) {
+				t.Fatalf("err = %v, want *AIError", err)
+			}
+			if ae.Provider != tt.provider || ae.StatusCode != tt.status || ae.Code != tt.wantCode || ae.Message != tt.wantMsg {
+				t.Errorf("got %+v", ae)
+			}
+			if ae.InvalidKey() != tt.invalidKey {
+				t.Errorf("InvalidKey() = %v, want %v", ae.InvalidKey(), tt.invalidKey)
+			}
+			if IsRetryable(err) != tt.retryable {
+				t.Errorf("IsRetryable = %v, want %v", IsRetryable(err), tt.retryable)
+			}
+		})
+	}
+}
+
+func TestContentBlocked(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   func(map[string]interface{}) error
+		body    string
+		blocked bool
+	}{
+		{"gemini safety finish", geminiBlocked, `{"candidates":[{"finishReason":"SAFETY","safetyRatings":[]}]}`, true},
+		{"gemini prompt blocked", geminiBlocked, `{"promptFeedback":{"blockReason":"PROHIBITED_CONTENT"}}`, true},
+		{"gemini normal", geminiBlocked, `{"candidates":[{"content":{"parts":[{"text":"ok"}]},"finishReason":"STOP"}]}`, false},
+		{"openai content filter", openAIBlocked, `{"choices":[{"finish_reason":"content_filter","message":{"content":null}}]}`, true},
+		{"openai refusal", openAIBlocked, `{"choices":[{"finish_reason":"stop","message":{"content":null,"refusal":"I can't help with that."}}]}`, true},
+		{"openai normal", openAIBlocked, `{"choices":[{"finish_reason":"stop","message":{"content":"ok"}}]}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.body), &parsed); err != nil {
+				t.Fatal(err)
+			}
+			err := tt.check(parsed)
+			if errors.Is(err, ErrContentBlocked) != tt.blocked {
+				t.Errorf("err = %v, want blocked=%v", err, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestFindFirstStringLimits(t *testing.T) {
+	var deep interface{} = "leaf"
+	for i := 0; i < findStringMaxDepth+5; i++ {
+		deep = []interface{}{deep}
+	}
+	if s := findFirstString(deep); s != "" {
+		t.Errorf("past max depth: got %q, want \"\"", s)
+	}
+
+	wide := make([]interface{}, findStringMaxNodes+1)
+	for i := range wide {
+		wide[i] = map[string]interface{}{}
+	}
+	wide = append(wide, "leaf")
+	if s := findFirstString(wide); s != "" {
+		t.Errorf("past max nodes: got %q, want \"\"", s)
+	}
+
+	if s := findFirstString(map[string]interface{}{"a": []interface{}{1.0, "text"}}); s != "text" {
+		t.Errorf("shallow: got %q, want \"text\"", s)
+	}
+}
+
+func TestAITimeout(t *testing.T) {
+	t.Setenv("AI_TIMEOUT_BASE", "")
+	t.Setenv("AI_TIMEOUT_PER_TOKEN", "")
+	if got := aiTimeout(256); got < 19*time.Second || got > 21*time.Second {
+		t.Errorf("aiTimeout(256) = %s, want ~20s", got)
+	}
+	if got := aiTimeout(2000); got != 72*time.Second {
+		t.Errorf("aiTimeout(2000) = %s, want 72s", got)
+	}
+	if got := aiTimeout(1_000_000); got != maxAITimeout {
+		t.Errorf("aiTimeout clamps to %s, got %s", maxAITimeout, got)
+	}
+	t.Setenv("AI_TIMEOUT_BASE", "0s")
+	if got := aiTimeout(1); got != minAITimeout {
+		t.Errorf("aiTimeout clamps to %s, got %s", minAITimeout, got)
+	}
+}
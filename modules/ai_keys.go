@@ -0,0 +1,50 @@
+package modules
+
+import (
+	"context"
+	"time"
+)
+
+// aiKeyCheckTimeout bounds each provider's test call in ValidateAIKeys.
+const aiKeyCheckTimeout = 15 * time.Second
+
+// ValidateAIKeys is ValidateAIKeysContext without a deadline.
+func ValidateAIKeys() map[string]error {
+	return ValidateAIKeysContext(context.Background())
+}
+
+// ValidateAIKeysContext sends a tiny uncached prompt to every configured
+// provider ("google", "openai") with its current key, so rotated keys can be
+// confirmed before the old ones are revoked. A nil error means the key works;
+// providers without a key are left out.
+func ValidateAIKeysContext(ctx context.Context) map[string]error {
+	c := cfg()
+	calls := map[string]func(ctx context.Context, req aiRequest) (string, error){}
+	if c.GoogleAPIKey != "" {
+		calls["google"] = func(ctx context.Context, req aiRequest) (string, error) { return callGemini(ctx, c.GoogleAPIKey, req) }
+	}
+	if c.OpenAIAPIKey != "" {
+		calls["openai"] = func(ctx context.Context, req aiRequest) (string, error) { return callOpenAI(ctx, c.OpenAIAPIKey, req) }
+	}
+
+	req, _ := newAIRequest("Reply with the single word OK.", AIOptions{MaxTokens: 5})
+	type result struct {
+		provider string
+		err      error
+	}
+	results := make(chan result, len(calls))
+	for provider, call := range calls {
+		go func() {
+			cctx, cancel := context.WithTimeout(ctx, aiKeyCheckTimeout)
+			defer cancel()
+			_, err := call(cctx, req)
+			results <- result{provider, err}
+		}()
+	}
+	out := make(map[string]error, len(calls))
+	for range calls {
+		r := <-results
+		out[r.provider] = r.err
+	}
+	return out
+}
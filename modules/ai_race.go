@@ -0,0 +1,89 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// AIRaceEnabled reports whether AI_RACE=true and both GOOGLE_API_KEY and
+// OPENAI_API_KEY are set, so ForwardToOpenAIRace queries both providers.
+func AIRaceEnabled() bool {
+	c := cfg()
+	return c.AIRace && c.GoogleAPIKey != "" && c.OpenAIAPIKey != ""
+}
+
+// ForwardToOpenAIRace is ForwardToOpenAI that, when AIRaceEnabled, sends the
+// prompt to Gemini and OpenAI at once and returns the first successful reply
+// with the provider that produced it ("google" or "openai"); the slower
+// request is canceled. Otherwise it behaves like ForwardToOpenAI.
+func ForwardToOpenAIRace(ctx context.Context, prompt string) (string, string, error) {
+	return ForwardRaceWithOptions(ctx, prompt, AIOptions{})
+}
+
+// ForwardRaceWithOptions is ForwardToOpenAIRace with per-call options. A
+// reply served from the AI cache reports provider "cache".
+func ForwardRaceWithOptions(ctx context.Context, prompt string, opts AIOptions) (string, string, error) {
+	req, err := newAIRequest(prompt, opts)
+	if err != nil {
+		return "", "", err
+	}
+	var provider string
+	resp, hit, err := cachedAI(ctx, req, opts.NoCache, func() (string, error) {
+		var resp string
+		var err error
+		resp, provider, err = raceAI(ctx, req)
+		return resp, err
+	})
+	if hit {
+		provider = "cache"
+	}
+	return resp, provider, err
+}
+
+// raceAI sends req to the configured provider(s) as ForwardToOpenAIRace describes.
+func raceAI(ctx context.Context, req aiRequest) (string, string, error) {
+	c := cfg()
+	googleKey, openaiKey := c.GoogleAPIKey, c.OpenAIAPIKey
+
+	if !AIRaceEnabled() {
+		switch {
+		case googleKey != "":
+			resp, err := callGemini(ctx, googleKey, req)
+			return resp, "google", err
+		case openaiKey != "":
+			resp, err := callOpenAI(ctx, openaiKey, req)
+			return resp, "openai", err
+		}
+		return "", "", ErrNoAIKey
+	}
+
+	type result struct {
+		provider string
+		resp     string
+		err      error
+	}
+	raceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// buffered so the loser never blocks after we stop listening
+	results := make(chan result, 2)
+	go func() {
+		resp, err := callGemini(raceCtx, googleKey, req)
+		results <- result{"google", resp, err}
+	}()
+	go func() {
+		resp, err := callOpenAI(raceCtx, openaiKey, req)
+		results <- result{"openai", resp, err}
+	}()
+
+	var errs []error
+	for range 2 {
+		r := <-results
+		if r.err == nil {
+			return r.resp, r.provider, nil
+		}
+		log.Printf("ForwardToOpenAIRace: %s failed: %v", r.provider, r.err)
+		errs = append(errs, r.err)
+	}
+	return "", "", errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DetectionSummary is the fixed schema requested from the AI for a detection.
+type DetectionSummary struct {
+	Sentiment  string  `json:"sentiment"`  // "bullish", "bearish" or "neutral"
+	RiskFlag   bool    `json:"risk_flag"`  // true if the text suggests a scam/dump/rug risk
+	Summary    string  `json:"summary"`    // one line
+	Confidence float64 `json:"confidence"` // 0..1
+	Structured bool    `json:"structured"` // false if parsed best-effort from free text
+	Blocked    bool    `json:"blocked"`    // provider withheld the reply (content filter)
+}
+
+const detectionSummaryPrompt = `You analyze crypto social media signals.
+Return ONLY a JSON object with exactly these fields:
+{"sentiment": "bullish" | "bearish" | "neutral", "risk_flag": true | false, "summary": "<one line>", "confidence": <number 0..1>}
+
+KOL: %s
+Token: %s
+Signal: %s
+Text: %s`
+
+// SummarizeDetectionStructured asks the AI backend for a JSON summary of d and
+// unmarshals it into a DetectionSummary. If the model ignores the schema, the
+// reply is parsed best-effort (Structured=false) instead of failing.
+func SummarizeDetectionStructured(d Detection) (DetectionSummary, error) {
+	prompt := fmt.Sprintf(detectionSummaryPrompt, d.KOL, d.Token, d.Signal, d.Text)
+	opts := SummaryAIOptions()
+	opts.JSONMode = true
+	// summaries sit on the detection pipeline, so race providers if allowed
+	raw, _, err := ForwardRaceWithOptions(context.Background(), prompt, opts)
+	if errors.Is(err, ErrContentBlocked) {
+		// a blocked reply is an answer too: nothing to summarize, flag it for review
+		return DetectionSummary{Sentiment: "neutral", RiskFlag: true, Summary: "AI summary withheld by the provider's content filter", Blocked: true}, nil
+	}
+	if err != nil {
+		return DetectionSummary{}, err
+	}
+	return parseDetectionSummary(raw), nil
+}
+
+// parseDetectionSummary decodes a model reply, tolerating code fences and
+// surrounding prose; anything unparseable becomes a neutral one-line summary.
+func parseDetectionSummary(raw string) DetectionSummary {
+	var s DetectionSummary
+	if body := extractJSONObject(raw); body != "" && json.Unmarshal([]byte(body), &s) == nil {
+		s.Structured = true
+	} else {
+		s = DetectionSummary{Summary: firstLine(raw)}
+		lower := strings.ToLower(raw)
+		s.RiskFlag = strings.Contains(lower, "scam") || strings.Contains(lower, "rug") || strings.Contains(lower, "dump")
+		switch {
+		case strings.Contains(lower, "bullish"):
+			s.Sentiment = "bullish"
+		case strings.Contains(lower, "bearish"):
+			s.Sentiment = "bearish"
+		}
+	}
+
+	s.Sentiment = strings.ToLower(strings.TrimSpace(s.Sentiment))
+	switch s.Sentiment {
+	case "bullish", "bearish", "neutral":
+	default:
+		s.Sentiment = "neutral"
+	}
+	s.Summary = firstLine(s.Summary)
+	s.Confidence = clamp01(s.Confidence)
+	return s
+}
+
+// extractJSONObject returns the outermost {...} in s, or "".
+func extractJSONObject(s string) string {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start < 0 || end <= start {
+		return ""
+	}
+	return s[start : end+1]
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(strings.Trim(strings.TrimSpace(s), "`"))
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
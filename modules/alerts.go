@@ -0,0 +1,428 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAlertResetMargin = 0.02 // re-arm once the value is 2% back past the threshold
+	alertCheckInterval      = 60 * time.Second
+)
+
+// AlertCondition is a parsed alert rule such as "price>100", "change24h<-10%"
+// or "price>entry*1.5".
+type AlertCondition struct {
+	Field    string  `json:"field"`    // "price" or "change24h"
+	Op       string  `json:"op"`       // ">" or "<"
+	Value    float64 `json:"value"`    // absolute threshold, or multiplier when Relative
+	Relative bool    `json:"relative"` // threshold is entry price * Value
+}
+
+var alertCondRe = regexp.MustCompile(`^(price|change24h|change)([<>])(entry\*)?(-?[0-9]*\.?[0-9]+)(%?)$`)
+
+// ParseAlertCondition parses conditions like "price>100", "change24h<-10%"
+// and "price>entry*1.5" (spaces are ignored).
+func ParseAlertCondition(s string) (AlertCondition, error) {
+	s = strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	m := alertCondRe.FindStringSubmatch(s)
+	if m == nil {
+		return AlertCondition{}, fmt.Errorf("invalid condition %q (examples: price>100, change24h<-10%%, price>entry*1.5)", s)
+	}
+	v, err := strconv.ParseFloat(m[4], 64)
+	if err != nil {
+		return AlertCondition{}, fmt.Errorf("invalid value in %q: %w", s, err)
+	}
+	c := AlertCondition{Field: m[1], Op: m[2], Value: v, Relative: m[3] != ""}
+	if c.Field == "change" {
+		c.Field = "change24h"
+	}
+	if c.Relative && c.Field != "price" {
+		return AlertCondition{}, fmt.Errorf("entry-relative conditions only apply to price")
+	}
+	if m[5] == "%" && c.Field == "price" {
+		return AlertCondition{}, fmt.Errorf("percent conditions apply to change24h, e.g. change24h<-10%%")
+	}
+	if c.Field == "price" && c.Value <= 0 {
+		return AlertCondition{}, fmt.Errorf("price thresholds must be positive")
+	}
+	return c, nil
+}
+
+func (c AlertCondition) String() string {
+	v := strconv.FormatFloat(c.Value, 'g', -1, 64)
+	switch {
+	case c.Relative:
+		return fmt.Sprintf("%s%sentry*%s", c.Field, c.Op, v)
+	case c.Field == "change24h":
+		return fmt.Sprintf("%s%s%s%%", c.Field, c.Op, v)
+	}
+	return fmt.Sprintf("%s%s%s", c.Field, c.Op, v)
+}
+
+// Alert is a persisted alert with its hysteresis state.
+type Alert struct {
+	ID          string         `json:"id"`
+	Token       string         `json:"token"`
+	Condition   AlertCondition `json:"condition"`
+	EntryPrice  float64        `json:"entry_price,omitempty"` // price when created, for relative conditions
+	ResetMargin float64        `json:"reset_margin"`          // fraction of the threshold
+	Armed       bool           `json:"armed"`
+	TriggeredAt time.Time      `json:"triggered_at,omitzero"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// threshold returns the absolute threshold for the condition.
+func (a *Alert) threshold() float64 {
+	if a.Condition.Relative {
+		return a.EntryPrice * a.Condition.Value
+	}
+	return a.Condition.Value
+}
+
+// rearmMargin returns how far back past the threshold the value must move to
+// re-arm: ResetMargin of the threshold, or for a zero threshold (only possible
+// on change24h) the same number of percentage points, so "change24h>0%
+// reset=2%" re-arms below -2% rather than on every tick under zero.
+func (a *Alert) rearmMargin(t float64) float64 {
+	if t == 0 {
+		return a.ResetMargin * 100
+	}
+	return math.Abs(t) * a.ResetMargin
+}
+
+// Evaluate applies a new reading and reports whether the alert fires. Once
+// fired, the alert stays disarmed until the value moves back past the
+// threshold by the reset margin, so choppy prices around the boundary don't spam.
+func (a *Alert) Evaluate(value float64, now time.Time) bool {
+	t := a.threshold()
+	margin := a.rearmMargin(t)
+	if a.Armed {
+		met := (a.Condition.Op == ">" && value > t) || (a.Condition.Op == "<" && value < t)
+		if met {
+			a.Armed = false
+			a.TriggeredAt = now
+			return true
+		}
+		return false
+	}
+	if (a.Condition.Op == ">" && value < t-margin) || (a.Condition.Op == "<" && value > t+margin) {
+		a.Armed = true
+	}
+	return false
+}
+
+// AlertManager stores alerts on disk and evaluates them periodically.
+type AlertManager struct {
+	mu     sync.Mutex
+	alerts map[string]*Alert
+	path   string
+	out    chan<- Detection
+	nextID int
+}
+
+// AlertStatePath returns the alert persistence file (ALERT_STATE_FILE, default alerts.json).
+func AlertStatePath() string {
+	return cfg().AlertStateFile
+}
+
+// NewAlertManager creates a manager persisting to path and loads saved alerts.
+func NewAlertManager(path string, out chan<- Detection) *AlertManager {
+	m := &AlertManager{alerts: map[string]*Alert{}, path: path, out: out}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Println("[alerts] Warning: could not load alerts:", err)
+		}
+		return m
+	}
+	var alerts []*Alert
+	if err := json.Unmarshal(b, &alerts); err != nil {
+		log.Println("[alerts] Warning: could not load alerts:", err)
+		return m
+	}
+	for _, a := range alerts {
+		m.alerts[a.ID] = a
+		if n, err := strconv.Atoi(strings.TrimPrefix(a.ID, "a")); err == nil && n > m.nextID {
+			m.nextID = n
+		}
+	}
+	return m
+}
+
+// save persists all alerts. Caller must hold mu.
+func (m *AlertManager) save() error {
+	b, err := json.MarshalIndent(m.sorted(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, b, 0644)
+}
+
+// sorted returns the alerts ordered by creation time. Caller must hold mu.
+func (m *AlertManager) sorted() []*Alert {
+	out := make([]*Alert, 0, len(m.alerts))
+	for _, a := range m.alerts {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Add creates an alert. entryPrice is required for entry-relative conditions.
+func (m *AlertManager) Add(token string, cond AlertCondition, entryPrice, resetMargin float64) (Alert, error) {
+	if cond.Relative && entryPrice <= 0 {
+		return Alert{}, fmt.Errorf("entry price unavailable for %s", strings.ToUpper(token))
+	}
+	if resetMargin <= 0 {
+		resetMargin = defaultAlertResetMargin
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	a := &Alert{
+		ID:          fmt.Sprintf("a%d", m.nextID),
+		Token:       strings.ToUpper(canonicalSymbol(token)),
+		Condition:   cond,
+		EntryPrice:  entryPrice,
+		ResetMargin: resetMargin,
+		Armed:       true,
+		CreatedAt:   TimeNowUTC(),
+	}
+	m.alerts[a.ID] = a
+	return *a, m.save()
+}
+
+// Remove deletes an alert by ID.
+func (m *AlertManager) Remove(id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.alerts[id]; !ok {
+		return false, nil
+	}
+	delete(m.alerts, id)
+	return true, m.save()
+}
+
+// List returns a copy of all alerts.
+func (m *AlertManager) List() []Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []Alert{}
+	for _, a := range m.sorted() {
+		out = append(out, *a)
+	}
+	return out
+}
+
+// Run evaluates all alerts every minute until ctx is cancelled.
+func (m *AlertManager) Run(ctx context.Context) error {
+	return NewPoller("alerts", alertCheckInterval, func(ctx context.Context) error {
+		m.check(ctx)
+		return nil
+	}).Run(ctx)
+}
+
+func (m *AlertManager) check(ctx context.Context) {
+	m.mu.Lock()
+	tokens := map[string]bool{}
+	for _, a := range m.alerts {
+		tokens[a.Token] = true
+	}
+	m.mu.Unlock()
+	if len(tokens) == 0 {
+		return
+	}
+
+	list := make([]string, 0, len(tokens))
+	for t := range tokens {
+		list = append(list, t)
+	}
+	data, err := GetMarketDataBatchContext(ctx, list, BatchOptions{Timeout: 30 * time.Second})
+	if err != nil {
+		log.Printf("[alerts] market data: %v", summarizeErr(err))
+	}
+
+	now := TimeNowUTC()
+	var dets []Detection
+	m.mu.Lock()
+	changed := false
+	for _, a := range m.sorted() {
+		md, ok := data[strings.ToLower(a.Token)]
+		if !ok {
+			continue
+		}
+		value := md.PriceUSD
+		if a.Condition.Field == "change24h" {
+			value = md.Change24h
+		}
+		wasArmed := a.Armed
+		if a.Evaluate(value, now) {
+			d := Detection{
+				Token:      a.Token,
+				Signal:     "alert",
+				Confidence: 1,
+				Source:     SourceAlert,
+				Text:       fmt.Sprintf("Alert %s: $%s %s (now %g)", a.ID, a.Token, a.Condition, value),
+				Timestamp:  now,
+			}
+			d.EnsureID()
+			dets = append(dets, d)
+		}
+		changed = changed || wasArmed != a.Armed
+	}
+	if changed {
+		if err := m.save(); err != nil {
+			log.Println("[alerts] Warning: could not save alerts:", err)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, d := range dets {
+		select {
+		case m.out <- d:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+const alertUsage = "Usage: alert [token] [condition] [reset=2%] | alert list | alert remove [id]. Conditions: price>100, change24h<-10%, price>entry*1.5"
+
+// RunAlert implements "alert <token> <condition> [reset=2%]", "alert list"
+// and "alert remove <id>".
+func RunAlert(ctx context.Context, m *AlertManager, args []string) (string, error) {
+	if m == nil {
+		return "Alerts are not available.", nil
+	}
+	if len(args) == 0 {
+		return alertUsage, nil
+	}
+	switch strings.ToLower(args[0]) {
+	case "list":
+		alerts := m.List()
+		if len(alerts) == 0 {
+			return "No alerts.", nil
+		}
+		var b strings.Builder
+		b.WriteString("Alerts:\n")
+		for _, a := range alerts {
+			state := "armed"
+			if !a.Armed {
+				state = "triggered " + FormatReplyTime(a.TriggeredAt) + ", waiting to reset"
+			}
+			fmt.Fprintf(&b, "- %s $%s %s • %s\n", a.ID, a.Token, a.Condition, state)
+		}
+		return b.String(), nil
+	case "remove", "delete":
+		if len(args) < 2 {
+			return "Usage: alert remove [id]", nil
+		}
+		ok, err := m.Remove(args[1])
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return fmt.Sprintf("No alert %s.", args[1]), nil
+		}
+		return fmt.Sprintf("Removed alert %s.", args[1]), nil
+	}
+
+	token, cond, resetMargin, msg := parseAlertRequest(args)
+	if msg != "" {
+		return msg, nil
+	}
+
+	entry := 0.0
+	if cond.Relative {
+		md, err := GetMarketDataContext(ctx, token)
+		if err != nil {
+			log.Printf("[alerts] entry price for %s: %v", token, err)
+			return fmt.Sprintf("Cannot create alert: entry price for $%s unavailable. %s", strings.ToUpper(token), UserFacingError(err)), nil
+		}
+		entry = md.PriceUSD
+	}
+	a, err := m.Add(token, cond, entry, resetMargin)
+	if err != nil {
+		return "", err
+	}
+	reply := fmt.Sprintf("Alert %s created: $%s %s (re-arms %.1f%% past the threshold)", a.ID, a.Token, a.Condition, a.ResetMargin*100)
+	if a.Condition.Relative {
+		reply += fmt.Sprintf(" • entry %s → threshold %s", FormatPrice(a.EntryPrice), FormatPrice(a.threshold()))
+	}
+	return reply, nil
+}
+
+// parseAlertRequest parses "<token> <condition> [reset=N%]". A non-empty msg
+// is the reply to send instead (usage or a parse error).
+func parseAlertRequest(args []string) (token string, cond AlertCondition, resetMargin float64, msg string) {
+	if len(args) < 2 {
+		return "", cond, 0, alertUsage
+	}
+	token = args[0]
+	condParts := []string{}
+	for _, a := range args[1:] {
+		if v, ok := strings.CutPrefix(strings.ToLower(a), "reset="); ok {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+			if err != nil || f <= 0 {
+				return "", cond, 0, alertUsage
+			}
+			resetMargin = f / 100
+			continue
+		}
+		condParts = append(condParts, a)
+	}
+	cond, err := ParseAlertCondition(strings.Join(condParts, ""))
+	if err != nil {
+		return "", cond, 0, err.Error()
+	}
+	return token, cond, resetMargin, ""
+}
+
+// DryRunAlert validates an alert command and describes what it would do
+// without saving anything or fetching market data.
+func DryRunAlert(ctx context.Context, m *AlertManager, args []string) (string, error) {
+	if m == nil {
+		return "Alerts are not available.", nil
+	}
+	if len(args) == 0 {
+		return alertUsage, nil
+	}
+	switch strings.ToLower(args[0]) {
+	case "list":
+		return RunAlert(ctx, m, args)
+	case "remove", "delete":
+		if len(args) < 2 {
+			return "Usage: alert remove [id]", nil
+		}
+		for _, a := range m.List() {
+			if a.ID == args[1] {
+				return fmt.Sprintf("Dry run: would remove alert %s ($%s %s).", a.ID, a.Token, a.Condition), nil
+			}
+		}
+		return fmt.Sprintf("No alert %s.", args[1]), nil
+	}
+
+	token, cond, resetMargin, msg := parseAlertRequest(args)
+	if msg != "" {
+		return msg, nil
+	}
+	if resetMargin <= 0 {
+		resetMargin = defaultAlertResetMargin
+	}
+	reply := fmt.Sprintf("Dry run: would create alert $%s %s (re-arms %.1f%% past the threshold)", strings.ToUpper(token), cond, resetMargin*100)
+	if cond.Relative {
+		reply += "; the entry price is taken from live market data when the alert is created"
+	}
+	return reply + ". Nothing was saved.", nil
+}
@@ -0,0 +1,155 @@
+package modules
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseAlertCondition(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    AlertCondition
+		wantErr bool
+	}{
+		{in: "price>100", want: AlertCondition{Field: "price", Op: ">", Value: 100}},
+		{in: "Price < 0.5", want: AlertCondition{Field: "price", Op: "<", Value: 0.5}},
+		{in: "change24h<-10%", want: AlertCondition{Field: "change24h", Op: "<", Value: -10}},
+		{in: "change>5", want: AlertCondition{Field: "change24h", Op: ">", Value: 5}},
+		{in: "change24h>0%", want: AlertCondition{Field: "change24h", Op: ">", Value: 0}},
+		{in: "price>entry*1.5", want: AlertCondition{Field: "price", Op: ">", Value: 1.5, Relative: true}},
+		{in: "price>", wantErr: true},
+		{in: "volume>100", wantErr: true},
+		{in: "price>=100", wantErr: true},
+		{in: "price>10%", wantErr: true},
+		{in: "change24h<entry*0.9", wantErr: true},
+		{in: "price>0", wantErr: true},
+		{in: "price<-5", wantErr: true},
+		{in: "price<entry*0", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAlertCondition(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAlertCondition(%q) = %+v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAlertCondition(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestAlertEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cond   string
+		entry  float64
+		values []float64
+		fires  []bool // per value
+	}{
+		{
+			name:   "fires once, then stays disarmed inside the margin",
+			cond:   "price>100",
+			values: []float64{99, 101, 99, 101, 98.5, 101},
+			fires:  []bool{false, true, false, false, false, false},
+		},
+		{
+			name:   "re-arms past the margin",
+			cond:   "price>100",
+			values: []float64{101, 97.9, 101},
+			fires:  []bool{true, false, true},
+		},
+		{
+			name:   "the threshold itself does not fire",
+			cond:   "price>100",
+			values: []float64{100, 100.01},
+			fires:  []bool{false, true},
+		},
+		{
+			name:   "below conditions re-arm above the threshold",
+			cond:   "change24h<-10%",
+			values: []float64{-11, -9.9, -11, -9.7, -11},
+			fires:  []bool{true, false, false, false, true},
+		},
+		{
+			name:   "entry-relative threshold",
+			cond:   "price>entry*1.5",
+			entry:  2,
+			values: []float64{2.9, 3.1, 2.95, 3.1, 2.9, 3.1},
+			fires:  []bool{false, true, false, false, false, true},
+		},
+		{
+			name:   "zero threshold re-arms in percentage points",
+			cond:   "change24h>0%",
+			values: []float64{0.5, -0.5, 0.5, -2.5, 0.5},
+			fires:  []bool{true, false, false, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, err := ParseAlertCondition(tt.cond)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a := &Alert{Condition: cond, EntryPrice: tt.entry, ResetMargin: defaultAlertResetMargin, Armed: true}
+			now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+			for i, v := range tt.values {
+				now = now.Add(time.Minute)
+				if got := a.Evaluate(v, now); got != tt.fires[i] {
+					t.Fatalf("reading %d (%g): fired = %v, want %v", i, v, got, tt.fires[i])
+				}
+				if tt.fires[i] && !a.TriggeredAt.Equal(now) {
+					t.Errorf("reading %d: TriggeredAt = %v, want %v", i, a.TriggeredAt, now)
+				}
+			}
+		})
+	}
+}
+
+func TestAlertManagerCheck(t *testing.T) {
+	mock := newCoinGeckoMock(t) // bitcoin trades at 65000.5
+	out := make(chan Detection, 4)
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	m := NewAlertManager(path, out)
+
+	above, _ := ParseAlertCondition("price>60000")
+	below, _ := ParseAlertCondition("price<60000")
+	fired, err := m.Add("btc", above, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Add("btc", below, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	m.check(ctx)
+	select {
+	case d := <-out:
+		if d.Token != "BTC" || d.Source != SourceAlert {
+			t.Errorf("detection = %+v, want a BTC alert", d)
+		}
+	default:
+		t.Fatal("price>60000 did not fire")
+	}
+
+	// same price again: the fired alert is disarmed, the other still unmet
+	expireMarketCache()
+	m.check(ctx)
+	if len(out) != 0 {
+		t.Errorf("%d detections on the second check, want none", len(out))
+	}
+	if mock.requests.Load() < 2 {
+		t.Errorf("second check made no market request")
+	}
+
+	// the disarmed state survives a restart
+	for _, a := range NewAlertManager(path, out).List() {
+		if want := a.ID != fired.ID; a.Armed != want {
+			t.Errorf("reloaded alert %s armed = %v, want %v", a.ID, a.Armed, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditRecord is one line of the command audit trail (AUDIT_LOG).
+type AuditRecord struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args,omitempty"` // secrets replaced by RedactArgs
+	Caller     string    `json:"caller,omitempty"`
+	DryRun     bool      `json:"dry_run,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+}
+
+// AuditLogPath returns the command audit file (AUDIT_LOG); empty means
+// auditing is off.
+func AuditLogPath() string {
+	return cfg().AuditLog
+}
+
+var auditMu sync.Mutex
+
+// AppendAudit appends rec as one JSON line to filename.
+func AppendAudit(filename string, rec AuditRecord) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+const redacted = "[REDACTED]"
+
+// secretKeyRe matches the key of a key=value argument that holds a secret.
+var secretKeyRe = regexp.MustCompile(`(?i)(key|token|secret|passw(or)?d|bearer|auth|mnemonic|seed)`)
+
+// secretValueRe matches values that look like credentials on their own:
+// private keys (64 hex), OpenAI/Slack-style tokens and long opaque strings.
+// 0x wallet addresses (42 characters) stay readable.
+var secretValueRe = regexp.MustCompile(`^((0x)?[0-9a-fA-F]{64}|sk-[A-Za-z0-9_-]{16,}|xox[abprs]-[A-Za-z0-9-]+|[A-Za-z0-9_\-+/=]{44,})$`)
+
+// RedactArgs returns a copy of args with secrets replaced: values of
+// key=value pairs whose key names a secret, the argument after a --token /
+// --key style flag, and anything that looks like a key by itself.
+func RedactArgs(args []string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		switch k, _, ok := strings.Cut(a, "="); {
+		case ok && secretKeyRe.MatchString(k):
+			out[i] = k + "=" + redacted
+		case secretValueRe.MatchString(a):
+			out[i] = redacted
+		case i > 0 && strings.HasPrefix(args[i-1], "-") && !strings.Contains(args[i-1], "=") && secretKeyRe.MatchString(args[i-1]):
+			out[i] = redacted
+		default:
+			out[i] = a
+		}
+	}
+	return out
+}
+
+type auditCallerKey struct{}
+
+// WithAuditCaller attaches the identity of whoever sent a task (room, wallet,
+// user ID) so its audit record names them.
+func WithAuditCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, auditCallerKey{}, caller)
+}
+
+// AuditCaller returns the identity set by WithAuditCaller, or "".
+func AuditCaller(ctx context.Context) string {
+	s, _ := ctx.Value(auditCallerKey{}).(string)
+	return s
+}
@@ -0,0 +1,31 @@
+package modules
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRedactArgs(t *testing.T) {
+	in := []string{
+		"btc",
+		"api_key=abc123",
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+		"--token", "hunter2",
+		"sk-proj-abcdefghijklmnopqrstuvwx",
+	}
+	want := []string{
+		"btc",
+		"api_key=[REDACTED]",
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"[REDACTED]",
+		"--token", "[REDACTED]",
+		"[REDACTED]",
+	}
+	if got := RedactArgs(in); !slices.Equal(got, want) {
+		t.Errorf("RedactArgs = %q\nwant %q", got, want)
+	}
+	if in[1] != "api_key=abc123" {
+		t.Error("RedactArgs modified its input")
+	}
+}
@@ -0,0 +1,86 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const defaultRiskAversion = 0.5
+
+// RiskAversion returns RISK_AVERSION in [0..1] (default 0.5). 0 ignores risk,
+// 1 lets a maximal risk score wipe out hype entirely.
+func RiskAversion() float64 {
+	return cfg().RiskAversion
+}
+
+// BalanceScore discounts hype by risk, weighted by aversion: hype * (1 - aversion*risk).
+func BalanceScore(hype, risk, aversion float64) float64 {
+	return hype * (1 - aversion*risk)
+}
+
+// balanceVerdict maps hype/risk levels to a short recommendation. The risk
+// level that counts as too high falls as aversion rises: 0.6 at the default
+// 0.5, only a maximal score at 0, and anything from 0.2 up at 1.
+func balanceVerdict(hype, risk, aversion float64) string {
+	highHype := hype >= 0.6
+	highRisk := risk >= 1-0.8*aversion
+	switch {
+	case highHype && highRisk:
+		return "speculative; size small"
+	case highHype:
+		return "favorable; momentum with manageable risk"
+	case highRisk:
+		return "avoid; risk outweighs hype"
+	default:
+		return "neutral; wait for confirmation"
+	}
+}
+
+func levelOf(v float64) string {
+	switch {
+	case v >= 0.6:
+		return "high"
+	case v >= 0.3:
+		return "moderate"
+	default:
+		return "low"
+	}
+}
+
+// RunBalance implements "balance <token> [risk_aversion]": the risk-hype balancer.
+func RunBalance(ctx context.Context, args []string) (string, error) {
+	if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+		return "Usage: balance [token] [risk_aversion 0..1]. Example: balance sol 0.7", nil
+	}
+	sym := strings.ToUpper(canonicalSymbol(args[0]))
+	aversion := RiskAversion()
+	if len(args) > 1 {
+		v, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || v < 0 || v > 1 {
+			return "Risk aversion must be a number between 0 and 1. Example: balance sol 0.7", nil
+		}
+		aversion = v
+	}
+
+	var hype, risk float64
+	label := ""
+	if cfg().MockMode {
+		// same mock figures as the hype/riskcheck replies
+		hype, risk = 0.0, 0.30
+	} else {
+		md, l, err := marketDataOrFallback(ctx, sym)
+		if err != nil {
+			return "", fmt.Errorf("risk-hype balance for $%s: %w", sym, err)
+		}
+		hype = ComputeHypeScore(md)
+		risk = ComputeRiskScore(md)
+		label = l
+	}
+
+	combined := BalanceScore(hype, risk, aversion)
+	return withLabel(label, fmt.Sprintf("Risk-hype balance for $%s:\n- HypeScore: %.2f\n- RiskScore: %.2f\n- Risk aversion: %.2f\n- Balanced score: %.2f\nVerdict: %s hype (%.2f), %s risk (%.2f) → %s",
+		sym, hype, risk, aversion, combined,
+		levelOf(hype), hype, levelOf(risk), risk, balanceVerdict(hype, risk, aversion))), nil
+}
@@ -0,0 +1,55 @@
+package modules
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBalanceScore(t *testing.T) {
+	tests := []struct{ hype, risk, aversion, want float64 }{
+		{0.8, 0.5, 0, 0.8},
+		{0.8, 0.5, 0.5, 0.6},
+		{0.8, 0.5, 1, 0.4},
+		{0.8, 1, 1, 0},
+		{0, 0.9, 0.5, 0},
+	}
+	for _, tt := range tests {
+		if got := BalanceScore(tt.hype, tt.risk, tt.aversion); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("BalanceScore(%g, %g, %g) = %g, want %g", tt.hype, tt.risk, tt.aversion, got, tt.want)
+		}
+	}
+}
+
+func TestBalanceVerdict(t *testing.T) {
+	const (
+		favorable   = "favorable; momentum with manageable risk"
+		speculative = "speculative; size small"
+		avoid       = "avoid; risk outweighs hype"
+		neutral     = "neutral; wait for confirmation"
+	)
+	tests := []struct {
+		name                 string
+		hype, risk           float64
+		wantLow, wantDefault string // aversion 0 and 0.5
+		wantHigh             string // aversion 1
+	}{
+		{"hot and risky", 0.81, 0.72, favorable, speculative, speculative},
+		{"hot with moderate risk", 0.8, 0.4, favorable, favorable, speculative},
+		{"hot and safe", 0.8, 0.1, favorable, favorable, favorable},
+		{"quiet and risky", 0.2, 0.7, neutral, avoid, avoid},
+		{"quiet with moderate risk", 0.2, 0.3, neutral, neutral, avoid},
+		{"maximal risk is high even when ignoring risk", 0.8, 1, speculative, speculative, speculative},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, c := range []struct {
+				aversion float64
+				want     string
+			}{{0, tt.wantLow}, {defaultRiskAversion, tt.wantDefault}, {1, tt.wantHigh}} {
+				if got := balanceVerdict(tt.hype, tt.risk, c.aversion); got != c.want {
+					t.Errorf("aversion %.1f: verdict = %q, want %q", c.aversion, got, c.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxMarketsIDs is CoinGecko's per-request id limit for /coins/markets.
+	maxMarketsIDs       = 250
+	defaultBatchWorkers = 4
+)
+
+// BatchOptions tunes GetMarketDataBatchContext. Zero values use the defaults.
+type BatchOptions struct {
+	ChunkSize int           // ids per request, capped at 250
+	Workers   int           // concurrent chunk requests (default 4)
+	Timeout   time.Duration // overall deadline on top of ctx (0 = none)
+}
+
+// ChunkError is the failure of one /coins/markets request.
+type ChunkError struct {
+	IDs []string
+	Err error
+}
+
+func (e ChunkError) Error() string {
+	return fmt.Sprintf("chunk of %d ids: %v", len(e.IDs), e.Err)
+}
+
+func (e ChunkError) Unwrap() error { return e.Err }
+
+// BatchError reports the chunks that failed; results from the other chunks
+// are still returned alongside it.
+type BatchError struct {
+	Chunks []ChunkError
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Chunks) == 1 {
+		return e.Chunks[0].Error()
+	}
+	return fmt.Sprintf("%d of the batch chunks failed; first: %v", len(e.Chunks), e.Chunks[0])
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Chunks))
+	for i, c := range e.Chunks {
+		errs[i] = c
+	}
+	return errs
+}
+
+// GetMarketDataBatch fetches market data for several symbols with as few
+// /coins/markets requests as possible, using the default BatchOptions.
+func GetMarketDataBatch(symbols []string) (map[string]MarketData, error) {
+	return GetMarketDataBatchContext(context.Background(), symbols, BatchOptions{})
+}
+
+// GetMarketDataBatchContext is GetMarketDataBatch with a context and options.
+// Fresh cache entries are used as-is; the remaining symbols are split into
+// chunks fetched concurrently and cached like GetMarketData. Symbols CoinGecko
+// doesn't know are simply missing from the result. If some chunks fail, the
+// successful results are returned together with a *BatchError.
+func GetMarketDataBatchContext(ctx context.Context, symbols []string, opts BatchOptions) (map[string]MarketData, error) {
+	out := make(map[string]MarketData, len(symbols))
+	idToSym := map[string]string{}
+
+	now := time.Now()
+	cgCacheMu.Lock()
+	for _, s := range symbols {
+		sym, id, err := ResolveSymbol(s)
+		if err != nil {
+			continue
+		}
+		if e, ok := cgCache[sym]; ok && now.Before(e.expiresAt) {
+			cgStats.Hits++
+			out[sym] = e.data
+			continue
+		}
+		idToSym[id] = sym
+	}
+	cgCacheMu.Unlock()
+
+	if len(idToSym) == 0 {
+		return out, nil
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 || chunkSize > maxMarketsIDs {
+		chunkSize = maxMarketsIDs
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultBatchWorkers
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	ids := make([]string, 0, len(idToSym))
+	for id := range idToSym {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var chunks [][]string
+	for len(ids) > 0 {
+		n := min(chunkSize, len(ids))
+		chunks = append(chunks, ids[:n])
+		ids = ids[n:]
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed []ChunkError
+		sem    = make(chan struct{}, workers)
+	)
+	for _, chunk := range chunks {
+		wg.Add(1)
+		go func(chunk []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				mu.Lock()
+				failed = append(failed, ChunkError{IDs: chunk, Err: ctx.Err()})
+				mu.Unlock()
+				return
+			}
+			fetched, err := fetchMarketsChunk(ctx, chunk, idToSym)
+			noteMarketFetch(err)
+			mu.Lock()
+			defer mu.Unlock()
+			for sym, md := range fetched {
+				out[sym] = md
+			}
+			if err != nil {
+				failed = append(failed, ChunkError{IDs: chunk, Err: err})
+			}
+		}(chunk)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return out, &BatchError{Chunks: failed}
+	}
+	return out, nil
+}
+
+// fetchMarketsChunk requests /coins/markets for ids and caches the results.
+func fetchMarketsChunk(ctx context.Context, ids []string, idToSym map[string]string) (map[string]MarketData, error) {
+	u := fmt.Sprintf("%s/coins/markets?vs_currency=usd&per_page=%d&ids=%s", coinGeckoBaseURL(), maxMarketsIDs, url.QueryEscape(strings.Join(ids, ",")))
+	cgCacheMu.Lock()
+	cgStats.Misses++
+	cgCacheMu.Unlock()
+
+	resp, err := doCoinGecko(ctx, httpClient, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, statusError(resp.StatusCode, "coingecko markets status %d", resp.StatusCode)
+	}
+
+	var rows []struct {
+		ID        string  `json:"id"`
+		Price     float64 `json:"current_price"`
+		Change24h float64 `json:"price_change_percentage_24h"`
+		Volume    float64 `json:"total_volume"`
+		MarketCap float64 `json:"market_cap"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("coingecko markets decode err: %w", err)
+	}
+
+	out := make(map[string]MarketData, len(rows))
+	now := time.Now()
+	cgCacheMu.Lock()
+	defer cgCacheMu.Unlock()
+	for _, r := range rows {
+		sym, ok := idToSym[r.ID]
+		if !ok {
+			continue
+		}
+		md := MarketData{
+			ID:           r.ID,
+			Symbol:       sym,
+			PriceUSD:     r.Price,
+			Change24h:    r.Change24h,
+			Volume24h:    r.Volume,
+			MarketCapUSD: r.MarketCap,
+			RetrievedAt:  now,
+		}
+		cgCache[sym] = cgCacheEntry{data: md, expiresAt: now.Add(cacheTTL)}
+		out[sym] = md
+	}
+	return out, nil
+}
@@ -0,0 +1,175 @@
+package modules
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// DeliveryPolicy decides what happens when a subscriber's buffer is full.
+type DeliveryPolicy int
+
+const (
+	// DropWhenFull skips the subscriber for that detection (counted in its stats).
+	DropWhenFull DeliveryPolicy = iota
+	// BlockWhenFull waits for the subscriber, slowing delivery to everyone
+	// (but not Subscribe, unsubscribe or Stats).
+	BlockWhenFull
+)
+
+// SubscriberStats counts deliveries to one subscriber.
+type SubscriberStats struct {
+	Name      string `json:"name"`
+	Delivered int64  `json:"delivered"`
+	Dropped   int64  `json:"dropped"`
+	Buffered  int    `json:"buffered"`
+}
+
+type subscriber struct {
+	name      string
+	ch        chan Detection
+	done      chan struct{} // closed on shutdown, unblocks a pending send
+	stop      sync.Once
+	policy    DeliveryPolicy
+	delivered atomic.Int64
+	dropped   atomic.Int64
+
+	mu     sync.Mutex // held while sending on ch so shutdown can't close it mid-send
+	closed bool
+}
+
+// send delivers d according to the subscriber's policy, or does nothing once
+// it has been shut down.
+func (s *subscriber) send(ctx context.Context, d Detection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if s.policy == BlockWhenFull {
+		select {
+		case s.ch <- d:
+			s.delivered.Add(1)
+		case <-s.done:
+		case <-ctx.Done():
+		}
+		return
+	}
+	select {
+	case s.ch <- d:
+		s.delivered.Add(1)
+	default:
+		if n := s.dropped.Add(1); n == 1 || n%100 == 0 {
+			log.Printf("[broker] subscriber %s is falling behind (%d detections dropped)", s.name, n)
+		}
+	}
+}
+
+// shutdown closes the subscriber's channel, first unblocking a pending send.
+// Safe to call more than once.
+func (s *subscriber) shutdown() {
+	s.stop.Do(func() { close(s.done) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// DetectionBroker fans each detection read from its input out to every
+// subscriber, each with its own buffer and slow-consumer policy.
+type DetectionBroker struct {
+	in     <-chan Detection
+	mu     sync.Mutex
+	subs   map[string]*subscriber
+	closed bool
+}
+
+// NewDetectionBroker creates a broker reading detections from in.
+func NewDetectionBroker(in <-chan Detection) *DetectionBroker {
+	return &DetectionBroker{in: in, subs: map[string]*subscriber{}}
+}
+
+// Subscribe registers a named subscriber with the given buffer size and
+// returns its channel, closed when the broker stops or on unsubscribe.
+// Subscribing again with the same name replaces the previous subscription.
+func (b *DetectionBroker) Subscribe(name string, buffer int, policy DeliveryPolicy) (<-chan Detection, func()) {
+	s := &subscriber{name: name, ch: make(chan Detection, max(buffer, 0)), done: make(chan struct{}), policy: policy}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(s.ch)
+		return s.ch, func() {}
+	}
+	if old, ok := b.subs[name]; ok {
+		old.shutdown()
+	}
+	b.subs[name] = s
+	return s.ch, func() {
+		b.mu.Lock()
+		if b.subs[name] == s {
+			delete(b.subs, name)
+		}
+		b.mu.Unlock()
+		s.shutdown()
+	}
+}
+
+// Run delivers detections until ctx is cancelled or the input is closed, then
+// closes all subscriber channels.
+func (b *DetectionBroker) Run(ctx context.Context) error {
+	defer b.close()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-b.in:
+			if !ok {
+				return nil
+			}
+			b.publish(ctx, d)
+		}
+	}
+}
+
+// publish hands d to every subscriber. The subscriber set is snapshotted so a
+// blocked send doesn't hold b.mu; a subscriber removed meanwhile is skipped.
+func (b *DetectionBroker) publish(ctx context.Context, d Detection) {
+	b.mu.Lock()
+	subs := make([]*subscriber, 0, len(b.subs))
+	for _, s := range b.subs {
+		subs = append(subs, s)
+	}
+	b.mu.Unlock()
+	for _, s := range subs {
+		if ctx.Err() != nil {
+			return
+		}
+		s.send(ctx, d)
+	}
+}
+
+func (b *DetectionBroker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for name, s := range b.subs {
+		s.shutdown()
+		delete(b.subs, name)
+	}
+}
+
+// Stats returns per-subscriber delivery counters, sorted by name.
+func (b *DetectionBroker) Stats() []SubscriberStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]SubscriberStats, 0, len(b.subs))
+	for _, s := range b.subs {
+		out = append(out, SubscriberStats{Name: s.name, Delivered: s.delivered.Load(), Dropped: s.dropped.Load(), Buffered: len(s.ch)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
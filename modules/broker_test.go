@@ -0,0 +1,177 @@
+package modules
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// returnsWithin fails the test if fn doesn't return within a second.
+func returnsWithin(t *testing.T, what string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("%s did not return", what)
+	}
+}
+
+// publishAsync runs publish in the background; the returned channel is closed
+// when it returns.
+func publishAsync(b *DetectionBroker, d Detection) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		b.publish(context.Background(), d)
+		close(done)
+	}()
+	return done
+}
+
+func assertPending(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+		t.Fatal("publish returned, want it blocked on a full subscriber")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func assertClosed(t *testing.T, ch <-chan Detection) {
+	t.Helper()
+	select {
+	case d, ok := <-ch:
+		if ok {
+			t.Fatalf("received %+v, want a closed channel", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed")
+	}
+}
+
+func TestBrokerDeliveryPolicies(t *testing.T) {
+	b := NewDetectionBroker(nil)
+	_, _ = b.Subscribe("drop", 1, DropWhenFull)
+	blockCh, _ := b.Subscribe("block", 2, BlockWhenFull)
+
+	b.publish(context.Background(), Detection{Token: "A"})
+	b.publish(context.Background(), Detection{Token: "B"})
+
+	want := []SubscriberStats{
+		{Name: "block", Delivered: 2, Buffered: 2},
+		{Name: "drop", Delivered: 1, Dropped: 1, Buffered: 1},
+	}
+	if got := b.Stats(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Stats = %+v, want %+v", got, want)
+	}
+
+	// a third detection blocks on the full BlockWhenFull subscriber without
+	// holding the broker lock
+	done := publishAsync(b, Detection{Token: "C"})
+	assertPending(t, done)
+	returnsWithin(t, "Stats during a blocked send", func() { b.Stats() })
+	returnsWithin(t, "Subscribe during a blocked send", func() { b.Subscribe("late", 1, DropWhenFull) })
+
+	if d := <-blockCh; d.Token != "A" {
+		t.Errorf("first detection = %s, want A", d.Token)
+	}
+	returnsWithin(t, "publish after the subscriber caught up", func() { <-done })
+	if got := b.Stats()[0]; got.Name != "block" || got.Delivered != 3 {
+		t.Errorf("block stats = %+v, want 3 delivered", got)
+	}
+}
+
+func TestBrokerUnsubscribeWhileBlocked(t *testing.T) {
+	b := NewDetectionBroker(nil)
+	ch, unsubscribe := b.Subscribe("slow", 0, BlockWhenFull)
+
+	done := publishAsync(b, Detection{Token: "A"})
+	assertPending(t, done)
+
+	returnsWithin(t, "unsubscribe during a blocked send", unsubscribe)
+	returnsWithin(t, "blocked publish after unsubscribe", func() { <-done })
+	assertClosed(t, ch)
+	if got := b.Stats(); len(got) != 0 {
+		t.Errorf("Stats = %+v, want no subscribers", got)
+	}
+	unsubscribe() // idempotent
+}
+
+func TestBrokerResubscribeReplaces(t *testing.T) {
+	b := NewDetectionBroker(nil)
+	oldCh, oldUnsubscribe := b.Subscribe("feed", 0, BlockWhenFull)
+
+	done := publishAsync(b, Detection{Token: "A"})
+	assertPending(t, done)
+
+	newCh, _ := b.Subscribe("feed", 1, DropWhenFull)
+	returnsWithin(t, "publish blocked on the replaced subscriber", func() { <-done })
+	assertClosed(t, oldCh)
+
+	// the stale unsubscribe must not touch the replacement
+	oldUnsubscribe()
+	b.publish(context.Background(), Detection{Token: "B"})
+	select {
+	case d, ok := <-newCh:
+		if !ok || d.Token != "B" {
+			t.Fatalf("replacement received %+v (ok=%v), want B", d, ok)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("replacement subscriber got nothing")
+	}
+	if got := b.Stats(); len(got) != 1 || got[0].Name != "feed" || got[0].Delivered != 1 {
+		t.Errorf("Stats = %+v, want only the replacement with 1 delivered", got)
+	}
+}
+
+func TestBrokerRunClosesSubscribers(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(in chan Detection, cancel context.CancelFunc)
+	}{
+		{"input closed", func(in chan Detection, _ context.CancelFunc) { close(in) }},
+		{"context cancelled", func(_ chan Detection, cancel context.CancelFunc) { cancel() }},
+		{"context cancelled during a blocked send", func(in chan Detection, cancel context.CancelFunc) {
+			in <- Detection{Token: "A"} // the "slow" subscriber never reads
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := make(chan Detection)
+			b := NewDetectionBroker(in)
+			slow, _ := b.Subscribe("slow", 0, BlockWhenFull)
+			fast, _ := b.Subscribe("fast", 4, DropWhenFull)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			exited := make(chan error, 1)
+			go func() { exited <- b.Run(ctx) }()
+
+			tt.stop(in, cancel)
+			select {
+			case err := <-exited:
+				if err != nil {
+					t.Errorf("Run = %v, want nil", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Run did not exit")
+			}
+
+			assertClosed(t, slow)
+			for range fast { // drain anything delivered before the stop
+			}
+			late, unsubscribe := b.Subscribe("late", 1, DropWhenFull)
+			assertClosed(t, late)
+			unsubscribe()
+			if got := b.Stats(); len(got) != 0 {
+				t.Errorf("Stats after Run = %+v, want none", got)
+			}
+		})
+	}
+}
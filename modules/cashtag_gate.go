@@ -0,0 +1,124 @@
+package modules
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cashtag gate modes (CASHTAG_GATE).
+const (
+	CashtagGateOff     = "off"
+	CashtagGateLenient = "lenient" // default: unresolved cashtags are emitted flagged and low-confidence
+	CashtagGateStrict  = "strict"  // unresolved cashtags are dropped
+)
+
+// SignalUnverifiedToken marks a lenient-mode detection whose cashtag didn't
+// resolve to a CoinGecko coin (e.g. "$BIGGAIN").
+const SignalUnverifiedToken = "unverified_token"
+
+const (
+	cashtagConfidence    = 0.6 // base confidence of a cashtag mention
+	cashtagLowConfidence = 0.2 // cap for unverified cashtags in lenient mode
+	cashtagValidTTL      = 24 * time.Hour
+	cashtagInvalidTTL    = time.Hour // new listings should start resolving soon
+)
+
+var (
+	cashtagMu    sync.Mutex
+	cashtagCache = map[string]cashtagEntry{}
+)
+
+type cashtagEntry struct {
+	valid bool
+	at    time.Time
+}
+
+// cashtagValid reports whether tag resolves to a CoinGecko coin. ok is false
+// when the lookup failed for another reason (API down, rate limited); those
+// results aren't cached and callers should fail open.
+func cashtagValid(tag string) (valid, ok bool) {
+	sym := canonicalSymbol(tag)
+	if sym == "" {
+		return false, true
+	}
+	if isKnownSymbol(sym) {
+		return true, true
+	}
+
+	cashtagMu.Lock()
+	e, cached := cashtagCache[sym]
+	cashtagMu.Unlock()
+	if cached {
+		ttl := cashtagValidTTL
+		if !e.valid {
+			ttl = cashtagInvalidTTL
+		}
+		if time.Since(e.at) <= ttl {
+			return e.valid, true
+		}
+	}
+
+	_, err := GetMarketData(sym)
+	switch {
+	case err == nil:
+		e = cashtagEntry{valid: true, at: time.Now()}
+	case errors.Is(err, ErrNotFound):
+		e = cashtagEntry{valid: false, at: time.Now()}
+	default:
+		log.Printf("[xscanner] cashtag gate: $%s lookup failed, keeping detection: %v", strings.ToUpper(sym), summarizeErr(err))
+		return false, false
+	}
+	cashtagMu.Lock()
+	cashtagCache[sym] = e
+	cashtagMu.Unlock()
+	return e.valid, true
+}
+
+// gateCashtag applies CASHTAG_GATE to d. It returns false when d should be
+// dropped (strict mode, unresolved token); in lenient mode an unresolved
+// token is kept but flagged and capped at low confidence.
+func gateCashtag(d *Detection, mode string) bool {
+	if mode == CashtagGateOff {
+		return true
+	}
+	if valid, ok := cashtagValid(d.Token); valid || !ok {
+		return true
+	}
+	if mode == CashtagGateStrict {
+		return false
+	}
+	d.Signal = SignalUnverifiedToken
+	d.Confidence = min(d.Confidence, cashtagLowConfidence)
+	return true
+}
+
+// DetectionFromPost turns a KOL post into a detection carrying the first token
+// it mentions (ExtractTokens). Cashtags are checked against the cashtag gate:
+// one the gate drops is counted as DropInvalidCashtag and the next token is
+// tried. Contract addresses can't be resolved by symbol and skip the gate.
+// ok is false when no token survives.
+func DetectionFromPost(kol, text, link, source string, at time.Time) (d Detection, ok bool) {
+	mode := cfg().CashtagGate
+	for _, tag := range ExtractTokens(text) {
+		d := Detection{
+			KOL:        kol,
+			Token:      tag,
+			Signal:     "early_call",
+			Confidence: cashtagConfidence,
+			Source:     source,
+			Text:       text,
+			Link:       link,
+			Timestamp:  at,
+		}
+		if !IsEVMAddress(tag) && !gateCashtag(&d, mode) {
+			recordScannerDrop(DropInvalidCashtag)
+			continue
+		}
+		d.EnsureID()
+		return d, true
+	}
+	return Detection{}, false
+}
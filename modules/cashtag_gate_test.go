@@ -0,0 +1,43 @@
+package modules
+
+import (
+	"testing"
+	"time"
+)
+
+func resetCashtagCache() {
+	cashtagMu.Lock()
+	defer cashtagMu.Unlock()
+	cashtagCache = map[string]cashtagEntry{}
+}
+
+func TestDetectionFromPost(t *testing.T) {
+	m := newCoinGeckoMock(t)
+	resetCashtagCache()
+	const post = "loading $NOTACOIN and $SOL, target $100k"
+
+	t.Setenv("CASHTAG_GATE", "strict")
+	d, ok := DetectionFromPost("GCR", post, "", "x", time.Now())
+	if !ok || d.Token != "SOL" {
+		t.Fatalf("strict: got %+v (ok=%v), want the first valid token SOL", d, ok)
+	}
+	if _, ok := DetectionFromPost("GCR", "only $NOTACOIN here", "", "x", time.Now()); ok {
+		t.Error("strict: post with only an unresolved cashtag produced a detection")
+	}
+
+	t.Setenv("CASHTAG_GATE", "lenient")
+	d, ok = DetectionFromPost("GCR", post, "", "x", time.Now())
+	if !ok || d.Token != "NOTACOIN" || d.Signal != SignalUnverifiedToken || d.Confidence > cashtagLowConfidence {
+		t.Errorf("lenient: got %+v (ok=%v), want the first token flagged low-confidence", d, ok)
+	}
+
+	before := m.requests.Load()
+	DetectionFromPost("GCR", post, "", "x", time.Now())
+	if m.requests.Load() != before {
+		t.Errorf("validity lookups were not cached (%d new requests)", m.requests.Load()-before)
+	}
+
+	if _, ok := DetectionFromPost("GCR", "gm, no tickers today", "", "x", time.Now()); ok {
+		t.Error("post without tokens produced a detection")
+	}
+}
@@ -0,0 +1,150 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCoinGeckoRateLimited is wrapped by errors for CoinGecko 429 responses and
+// for requests skipped because we are still inside a 429's Retry-After window.
+// Callers fall back to stale cache (GetMarketDataStale does this already).
+var ErrCoinGeckoRateLimited = errors.New("coingecko rate limited")
+
+const (
+	defaultCoinGeckoRetryAfter = 60 * time.Second
+	defaultCoinGeckoMaxWait    = 5 * time.Second
+)
+
+// cgGate paces CoinGecko requests (COINGECKO_RATE_PER_MIN) and holds them back
+// after a 429 until Retry-After has passed.
+var cgGate struct {
+	mu           sync.Mutex
+	next         time.Time // earliest start of the next paced request
+	blockedUntil time.Time // end of the current Retry-After window
+}
+
+// coinGeckoRatePerMin is COINGECKO_RATE_PER_MIN; 0 (default) disables pacing.
+// The public free tier allows roughly 30.
+func coinGeckoRatePerMin() int {
+	return cfg().CoinGeckoRatePerMin
+}
+
+// coinGeckoMaxWait is how long a request may block to retry a 429 once
+// (COINGECKO_MAX_RETRY_WAIT, default 5s). Longer Retry-After windows fail fast.
+func coinGeckoMaxWait() time.Duration {
+	return cfg().CoinGeckoMaxRetryWait
+}
+
+// rateLimitedError is the retryable 429 error carrying the remaining wait.
+func rateLimitedError(wait time.Duration) error {
+	return &RetryableError{
+		StatusCode: http.StatusTooManyRequests,
+		Err:        fmt.Errorf("coingecko status 429, retry after %s: %w", wait.Round(time.Second), ErrCoinGeckoRateLimited),
+	}
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or HTTP-date form,
+// defaulting to 60s when it is missing or unparseable.
+func parseRetryAfter(h string, now time.Time) time.Duration {
+	h = strings.TrimSpace(h)
+	if secs, err := strconv.Atoi(h); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return defaultCoinGeckoRetryAfter
+}
+
+// cgAcquire waits for a pacing slot. Inside a Retry-After window it fails
+// immediately with a rate-limited error instead of sending a doomed request.
+func cgAcquire(ctx context.Context) error {
+	cgGate.mu.Lock()
+	now := time.Now()
+	if now.Before(cgGate.blockedUntil) {
+		wait := cgGate.blockedUntil.Sub(now)
+		cgGate.mu.Unlock()
+		return rateLimitedError(wait)
+	}
+	var delay time.Duration
+	if rpm := coinGeckoRatePerMin(); rpm > 0 {
+		slot := cgGate.next
+		if slot.Before(now) {
+			slot = now
+		}
+		cgGate.next = slot.Add(time.Minute / time.Duration(rpm))
+		delay = slot.Sub(now)
+	}
+	cgGate.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// cgBlock starts (or extends) a Retry-After window.
+func cgBlock(d time.Duration) {
+	cgGate.mu.Lock()
+	defer cgGate.mu.Unlock()
+	if until := time.Now().Add(d); until.After(cgGate.blockedUntil) {
+		cgGate.blockedUntil = until
+	}
+}
+
+// doCoinGecko sends a GET to a CoinGecko URL through the rate gate. A 429 is
+// retried once if its Retry-After fits within COINGECKO_MAX_RETRY_WAIT and
+// ctx; otherwise it becomes a rate-limited error. Transport failures come back
+// as networkError, unless ctx ended first; other responses are returned for the
+// caller to check.
+func doCoinGecko(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := cgAcquire(ctx); err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				// the caller gave up; not a sign CoinGecko is down
+				return nil, fmt.Errorf("coingecko request abandoned: %w", ctx.Err())
+			}
+			return nil, networkError("coingecko http err: %w", err)
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		wait := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		cgBlock(wait)
+		deadline, hasDeadline := ctx.Deadline()
+		if attempt > 0 || wait > coinGeckoMaxWait() || (hasDeadline && time.Until(deadline) < wait) {
+			return nil, rateLimitedError(wait)
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, rateLimitedError(wait)
+		case <-t.C:
+		}
+	}
+}
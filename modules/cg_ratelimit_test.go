@@ -0,0 +1,74 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"2", 2 * time.Second},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"", defaultCoinGeckoRetryAfter},
+		{"soon", defaultCoinGeckoRetryAfter},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.in, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDoCoinGeckoRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	resetMarketCache()
+
+	resp, err := doCoinGecko(context.Background(), srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("short Retry-After: err = %v, want retry to succeed", err)
+	}
+	resp.Body.Close()
+	if n := calls.Load(); n != 2 {
+		t.Errorf("calls = %d, want 2", n)
+	}
+}
+
+func TestDoCoinGeckoCooldown(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	resetMarketCache()
+	defer resetMarketCache()
+
+	for i := 0; i < 2; i++ {
+		_, err := doCoinGecko(context.Background(), srv.Client(), srv.URL)
+		if !errors.Is(err, ErrCoinGeckoRateLimited) || !IsRetryable(err) {
+			t.Fatalf("attempt %d: err = %v, want retryable ErrCoinGeckoRateLimited", i, err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1 (second request held back by Retry-After)", n)
+	}
+}
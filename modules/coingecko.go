@@ -1,9 +1,10 @@
 package modules
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"log"
 	"strings"
 	"sync"
 	"time"
@@ -50,51 +51,144 @@ type cgCacheEntry struct {
 	expiresAt time.Time
 }
 
+// cgCall is an in-flight fetch that concurrent callers wait on; done is
+// closed once md, stale and err are set
+type cgCall struct {
+	done  chan struct{}
+	md    MarketData
+	stale bool
+	err   error
+}
+
+// CacheStats counts market-data cache outcomes since start.
+type CacheStats struct {
+	Hits        int64 `json:"hits"`
+	Misses      int64 `json:"misses"`       // live fetches started
+	Coalesced   int64 `json:"coalesced"`    // misses that joined an in-flight fetch
+	StaleServed int64 `json:"stale_served"` // failed fetches answered with last-known data
+	Entries     int   `json:"entries"`      // non-expired entries
+}
+
+var (
+	cgInflight = map[string]*cgCall{}
+	cgStats    CacheStats
+)
+
+const defaultCoinGeckoBaseURL = "https://api.coingecko.com/api/v3"
+
+var (
+	cgBaseURLMu sync.Mutex
+	cgBaseURL   = defaultCoinGeckoBaseURL
+)
+
+// SetCoinGeckoBaseURL points all CoinGecko requests at another API root, e.g.
+// a pro endpoint or a test server. Empty restores the public API.
+func SetCoinGeckoBaseURL(u string) {
+	cgBaseURLMu.Lock()
+	defer cgBaseURLMu.Unlock()
+	u = strings.TrimRight(strings.TrimSpace(u), "/")
+	if u == "" {
+		u = defaultCoinGeckoBaseURL
+	}
+	cgBaseURL = u
+}
+
+func coinGeckoBaseURL() string {
+	cgBaseURLMu.Lock()
+	defer cgBaseURLMu.Unlock()
+	return cgBaseURL
+}
+
 var (
 	cgCache    = map[string]cgCacheEntry{}
 	cgCacheMu  = sync.Mutex{}
 	cacheTTL   = 30 * time.Second
-	httpClient = &http.Client{Timeout: 10 * time.Second}
+	httpClient = newHTTPClient(10 * time.Second)
 )
 
 // GetMarketData fetches market data for a symbol (e.g., "SOL", "BTC").
+// If the live fetch fails but an expired cache entry exists, the stale entry is
+// returned (RetrievedAt preserved); use GetMarketDataStale to tell.
 func GetMarketData(symbol string) (MarketData, error) {
-	sym := strings.ToLower(strings.TrimSpace(symbol))
+	return GetMarketDataContext(context.Background(), symbol)
+}
+
+// GetMarketDataContext is GetMarketData bounded by ctx: a cancelled or
+// expired ctx (e.g. an abandoned command) stops waiting for the rate limiter
+// and the request.
+func GetMarketDataContext(ctx context.Context, symbol string) (MarketData, error) {
+	md, _, err := GetMarketDataStaleContext(ctx, symbol)
+	return md, err
+}
+
+// GetMarketDataStale is GetMarketData that also reports whether the data is a
+// last-known-good entry served because the live fetch failed.
+func GetMarketDataStale(symbol string) (md MarketData, stale bool, err error) {
+	return GetMarketDataStaleContext(context.Background(), symbol)
+}
+
+// GetMarketDataStaleContext is GetMarketDataStale bounded by ctx.
+func GetMarketDataStaleContext(ctx context.Context, symbol string) (md MarketData, stale bool, err error) {
+	sym, id, err := ResolveSymbol(symbol)
+	if err != nil {
+		return MarketData{}, false, err
+	}
 	// cache check
 	cgCacheMu.Lock()
 	if e, ok := cgCache[sym]; ok && time.Now().Before(e.expiresAt) {
+		cgStats.Hits++
+		cgCacheMu.Unlock()
+		return e.data, false, nil
+	}
+	// coalesce concurrent misses for the same symbol into one fetch
+	if c, ok := cgInflight[sym]; ok {
+		cgStats.Coalesced++
 		cgCacheMu.Unlock()
-		return e.data, nil
+		select {
+		case <-c.done:
+			return c.md, c.stale, c.err
+		case <-ctx.Done():
+			return MarketData{}, false, ctx.Err()
+		}
 	}
+	cgStats.Misses++
+	c := &cgCall{done: make(chan struct{})}
+	cgInflight[sym] = c
 	cgCacheMu.Unlock()
 
-	id, ok := cgSymbolToID[sym]
-	if !ok {
-		// try direct id fallback
-		id = sym
+	c.md, c.stale, c.err = fetchMarketData(ctx, sym, id)
+
+	cgCacheMu.Lock()
+	delete(cgInflight, sym)
+	if c.stale {
+		cgStats.StaleServed++
 	}
+	cgCacheMu.Unlock()
+	close(c.done)
 
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/%s?localization=false&tickers=false&market_data=true&community_data=false&developer_data=false&sparkline=false", id)
+	return c.md, c.stale, c.err
+}
 
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("Accept", "application/json")
+// fetchMarketData performs the live CoinGecko fetch for sym and updates the cache.
+func fetchMarketData(ctx context.Context, sym, id string) (md MarketData, stale bool, err error) {
+	url := fmt.Sprintf("%s/coins/%s?localization=false&tickers=false&market_data=true&community_data=false&developer_data=false&sparkline=false", coinGeckoBaseURL(), id)
 
-	resp, err := httpClient.Do(req)
+	resp, err := doCoinGecko(ctx, httpClient, url)
 	if err != nil {
-		return MarketData{}, fmt.Errorf("coingecko http err: %w", err)
+		return staleOnError(sym, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return MarketData{}, fmt.Errorf("coingecko status %d", resp.StatusCode)
+		return staleOnError(sym, unknownSymbolOnNotFound(sym, statusError(resp.StatusCode, "coingecko status %d", resp.StatusCode)))
 	}
 
 	var body map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
-		return MarketData{}, fmt.Errorf("coingecko decode err: %w", err)
+		return staleOnError(sym, fmt.Errorf("coingecko decode err: %w", err))
 	}
 
-	md := MarketData{
+	md = MarketData{
 		ID:          id,
 		Symbol:      sym,
 		RetrievedAt: time.Now(),
@@ -121,6 +215,8 @@ func GetMarketData(symbol string) (MarketData, error) {
 		}
 	}
 
+	noteMarketFetch(nil)
+
 	// save to cache
 	cgCacheMu.Lock()
 	cgCache[sym] = cgCacheEntry{
@@ -129,11 +225,54 @@ func GetMarketData(symbol string) (MarketData, error) {
 	}
 	cgCacheMu.Unlock()
 
-	return md, nil
+	return md, false, nil
+}
+
+const defaultMaxStale = 10 * time.Minute
+
+var (
+	maxStaleMu       sync.Mutex
+	maxStaleOverride *time.Duration
+)
+
+// SetMaxStale sets how old a last-known-good entry may be and still be served
+// when the live fetch fails. 0 disables the stale fallback. Overrides MAX_STALE.
+func SetMaxStale(d time.Duration) {
+	maxStaleMu.Lock()
+	defer maxStaleMu.Unlock()
+	maxStaleOverride = &d
+}
+
+// MaxStale returns the stale cutoff: SetMaxStale, else MAX_STALE (e.g. "10m"), else 10 minutes.
+func MaxStale() time.Duration {
+	maxStaleMu.Lock()
+	defer maxStaleMu.Unlock()
+	if maxStaleOverride != nil {
+		return *maxStaleOverride
+	}
+	return cfg().MaxStale
+}
+
+// staleOnError returns the expired cache entry for sym (stale=true) if there is
+// one no older than MaxStale, otherwise the fetch error.
+func staleOnError(sym string, fetchErr error) (MarketData, bool, error) {
+	noteMarketFetch(fetchErr)
+	cgCacheMu.Lock()
+	e, ok := cgCache[sym]
+	cgCacheMu.Unlock()
+	if !ok || time.Since(e.data.RetrievedAt) > MaxStale() {
+		return MarketData{}, false, fetchErr
+	}
+	log.Printf("[coingecko] %s: live fetch failed, serving data from %s: %v", sym, e.data.RetrievedAt.Format(time.RFC3339), fetchErr)
+	return e.data, true, nil
 }
 
-// ComputeHypeScore builds a simple hype score [0..1] using change24h and volume/marketcap
+// ComputeHypeScore builds a simple hype score [0..1] using change24h and volume/marketcap.
+// Stablecoins always score 0: their volume reflects liquidity, not hype.
 func ComputeHypeScore(m MarketData) float64 {
+	if IsStablecoin(m.Symbol) {
+		return 0
+	}
 	score := 0.0
 	clamp := func(v float64) float64 {
 		if v < 0 {
@@ -157,3 +296,69 @@ func ComputeHypeScore(m MarketData) float64 {
 	}
 	return score
 }
+
+// ComputeRiskScore builds a simple risk score [0..1] from market cap size and 24h volatility
+func ComputeRiskScore(m MarketData) float64 {
+	score := 0.0
+	if m.MarketCapUSD <= 0 {
+		score = 0.9
+	} else {
+		mc := m.MarketCapUSD
+		if mc < 1_000_000 {
+			score = 0.85
+		} else if mc < 10_000_000 {
+			score = 0.6
+		} else if mc < 100_000_000 {
+			score = 0.4
+		} else if mc < 1_000_000_000 {
+			score = 0.25
+		} else {
+			score = 0.12
+		}
+	}
+	if m.Change24h > 5 || m.Change24h < -5 {
+		score = score + 0.15
+	}
+	if score > 1 {
+		score = 1
+	}
+	return score
+}
+
+// DumpCache returns a snapshot of the non-expired market-data cache entries.
+func DumpCache() map[string]MarketData {
+	now := time.Now()
+	cgCacheMu.Lock()
+	defer cgCacheMu.Unlock()
+	out := make(map[string]MarketData, len(cgCache))
+	for sym, e := range cgCache {
+		if now.Before(e.expiresAt) {
+			out[sym] = e.data
+		}
+	}
+	return out
+}
+
+// GetCacheStats returns the market-data cache counters.
+func GetCacheStats() CacheStats {
+	now := time.Now()
+	cgCacheMu.Lock()
+	defer cgCacheMu.Unlock()
+	stats := cgStats
+	for _, e := range cgCache {
+		if now.Before(e.expiresAt) {
+			stats.Entries++
+		}
+	}
+	return stats
+}
+
+// SetCacheTTL changes how long market data is served from cache (hot-reloadable).
+func SetCacheTTL(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	cgCacheMu.Lock()
+	cacheTTL = d
+	cgCacheMu.Unlock()
+}
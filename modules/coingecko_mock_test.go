@@ -0,0 +1,129 @@
+package modules
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cgFixtures are canned /coins/{id} responses keyed by CoinGecko id.
+// "missingcoin" answers 404 and "ratelimited" answers 429.
+var cgFixtures = map[string]string{
+	"bitcoin": `{"id":"bitcoin","symbol":"btc","market_data":{
+		"current_price":{"usd":65000.5},
+		"price_change_percentage_24h":2.5,
+		"total_volume":{"usd":30000000000},
+		"market_cap":{"usd":1280000000000}}}`,
+	// partial data: price only, everything else missing
+	"partialcoin": `{"id":"partialcoin","symbol":"partialcoin","market_data":{
+		"current_price":{"usd":0.0042}}}`,
+}
+
+// coinGeckoMock is an httptest.Server speaking the subset of the CoinGecko
+// API used by this package.
+type coinGeckoMock struct {
+	*httptest.Server
+	requests atomic.Int64
+	failWith atomic.Int32 // non-zero: answer every request with this status
+}
+
+// newCoinGeckoMock starts a mock server, points the package at it and resets
+// the market-data cache. Everything is restored when the test ends.
+func newCoinGeckoMock(t *testing.T) *coinGeckoMock {
+	t.Helper()
+	m := &coinGeckoMock{}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serve))
+
+	resetMarketCache()
+	prevTTL := cacheTTL
+	SetCoinGeckoBaseURL(m.URL)
+	t.Cleanup(func() {
+		m.Close()
+		SetCoinGeckoBaseURL("")
+		SetCacheTTL(prevTTL)
+		resetMarketCache()
+	})
+	return m
+}
+
+func (m *coinGeckoMock) serve(w http.ResponseWriter, r *http.Request) {
+	m.requests.Add(1)
+	if code := m.failWith.Load(); code != 0 {
+		http.Error(w, `{"error":"mock failure"}`, int(code))
+		return
+	}
+
+	if r.URL.Path == "/coins/markets" {
+		rows := []map[string]interface{}{}
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			var coin struct {
+				MarketData struct {
+					Price     map[string]float64 `json:"current_price"`
+					Change    float64            `json:"price_change_percentage_24h"`
+					Volume    map[string]float64 `json:"total_volume"`
+					MarketCap map[string]float64 `json:"market_cap"`
+				} `json:"market_data"`
+			}
+			body, ok := cgFixtures[id]
+			if !ok || json.Unmarshal([]byte(body), &coin) != nil {
+				continue
+			}
+			rows = append(rows, map[string]interface{}{
+				"id":                          id,
+				"current_price":               coin.MarketData.Price["usd"],
+				"price_change_percentage_24h": coin.MarketData.Change,
+				"total_volume":                coin.MarketData.Volume["usd"],
+				"market_cap":                  coin.MarketData.MarketCap["usd"],
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rows)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/coins/")
+	switch id {
+	case "ratelimited":
+		http.Error(w, `{"status":{"error_code":429,"error_message":"rate limited"}}`, http.StatusTooManyRequests)
+		return
+	case "missingcoin":
+		http.Error(w, `{"error":"coin not found"}`, http.StatusNotFound)
+		return
+	}
+	body, ok := cgFixtures[id]
+	if !ok {
+		http.Error(w, `{"error":"coin not found"}`, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(body))
+}
+
+// expireMarketCache makes every cached entry stale without dropping it.
+func expireMarketCache() {
+	cgCacheMu.Lock()
+	defer cgCacheMu.Unlock()
+	for sym, e := range cgCache {
+		e.expiresAt = time.Now().Add(-time.Second)
+		cgCache[sym] = e
+	}
+}
+
+func resetMarketCache() {
+	cgCacheMu.Lock()
+	defer cgCacheMu.Unlock()
+	cgCache = map[string]cgCacheEntry{}
+	cgInflight = map[string]*cgCall{}
+	cgStats = CacheStats{}
+
+	cgGate.mu.Lock()
+	cgGate.next, cgGate.blockedUntil = time.Time{}, time.Time{}
+	cgGate.mu.Unlock()
+	marketHealthMu.Lock()
+	marketFailingAt = time.Time{}
+	marketHealthMu.Unlock()
+}
@@ -0,0 +1,237 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetMarketData(t *testing.T) {
+	newCoinGeckoMock(t)
+
+	md, err := GetMarketData("BTC")
+	if err != nil {
+		t.Fatalf("GetMarketData: %v", err)
+	}
+	if md.ID != "bitcoin" || md.Symbol != "btc" {
+		t.Errorf("id/symbol = %q/%q, want bitcoin/btc", md.ID, md.Symbol)
+	}
+	if md.PriceUSD != 65000.5 || md.Change24h != 2.5 || md.Volume24h != 3e10 || md.MarketCapUSD != 1.28e12 {
+		t.Errorf("unexpected market data: %+v", md)
+	}
+}
+
+func TestGetMarketDataPartial(t *testing.T) {
+	newCoinGeckoMock(t)
+
+	md, err := GetMarketData("partialcoin")
+	if err != nil {
+		t.Fatalf("GetMarketData: %v", err)
+	}
+	if md.PriceUSD != 0.0042 {
+		t.Errorf("PriceUSD = %v, want 0.0042", md.PriceUSD)
+	}
+	if md.Change24h != 0 || md.Volume24h != 0 || md.MarketCapUSD != 0 {
+		t.Errorf("missing fields should be zero: %+v", md)
+	}
+}
+
+func TestGetMarketDataErrors(t *testing.T) {
+	newCoinGeckoMock(t)
+
+	_, err := GetMarketData("missingcoin")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("404: err = %v, want ErrNotFound", err)
+	}
+	if IsRetryable(err) {
+		t.Errorf("404 should not be retryable")
+	}
+
+	_, err = GetMarketData("ratelimited")
+	var re *RetryableError
+	if !errors.As(err, &re) || re.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("429: err = %v, want RetryableError with status 429", err)
+	}
+}
+
+func TestGetMarketDataCache(t *testing.T) {
+	m := newCoinGeckoMock(t)
+
+	for i := 0; i < 3; i++ {
+		if _, err := GetMarketData("btc"); err != nil {
+			t.Fatalf("GetMarketData: %v", err)
+		}
+	}
+	if got := m.requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	if st := GetCacheStats(); st.Hits != 2 || st.Misses != 1 || st.Entries != 1 {
+		t.Errorf("stats = %+v, want 2 hits, 1 miss, 1 entry", st)
+	}
+}
+
+func TestGetMarketDataTTLExpiry(t *testing.T) {
+	m := newCoinGeckoMock(t)
+	SetCacheTTL(20 * time.Millisecond)
+
+	if _, err := GetMarketData("btc"); err != nil {
+		t.Fatalf("GetMarketData: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, err := GetMarketData("btc"); err != nil {
+		t.Fatalf("GetMarketData: %v", err)
+	}
+	if got := m.requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2 after TTL expiry", got)
+	}
+}
+
+func TestGetMarketDataServesStaleOnError(t *testing.T) {
+	m := newCoinGeckoMock(t)
+
+	fresh, err := GetMarketData("btc")
+	if err != nil {
+		t.Fatalf("GetMarketData: %v", err)
+	}
+	expireMarketCache()
+	m.failWith.Store(http.StatusInternalServerError)
+
+	md, stale, err := GetMarketDataStale("btc")
+	if err != nil {
+		t.Fatalf("GetMarketDataStale: %v", err)
+	}
+	if !stale || md.PriceUSD != fresh.PriceUSD || !md.RetrievedAt.Equal(fresh.RetrievedAt) {
+		t.Errorf("got stale=%v %+v, want last-known %+v", stale, md, fresh)
+	}
+
+	// nothing cached for eth: the error surfaces
+	if _, _, err := GetMarketDataStale("eth"); !IsRetryable(err) {
+		t.Errorf("eth: err = %v, want retryable 500", err)
+	}
+}
+
+func TestFallbackRespectsMaxStale(t *testing.T) {
+	m := newCoinGeckoMock(t)
+	t.Setenv("FALLBACK_MOCK_ON_ERROR", "true")
+	SetMaxStale(time.Minute)
+	t.Cleanup(func() {
+		maxStaleMu.Lock()
+		maxStaleOverride = nil
+		maxStaleMu.Unlock()
+	})
+
+	if _, err := GetMarketData("btc"); err != nil {
+		t.Fatalf("GetMarketData: %v", err)
+	}
+	cgCacheMu.Lock()
+	e := cgCache["btc"]
+	e.data.RetrievedAt = time.Now().Add(-2 * time.Minute)
+	e.expiresAt = time.Now().Add(-time.Second)
+	cgCache["btc"] = e
+	cgCacheMu.Unlock()
+	m.failWith.Store(http.StatusInternalServerError)
+
+	if _, ok := lastKnownMarketData("btc"); ok {
+		t.Error("lastKnownMarketData returned an entry older than MAX_STALE")
+	}
+	if _, label, _ := marketDataOrFallback(context.Background(), "btc"); strings.HasPrefix(label, "[stale]") {
+		t.Errorf("label = %q, want no stale data past MAX_STALE", label)
+	}
+	if reply, _ := fallbackReply("btc", "price", &RetryableError{Err: errors.New("down")}, func(md MarketData) string { return FormatPrice(md.PriceUSD) }); strings.HasPrefix(reply, "[stale]") {
+		t.Errorf("fallbackReply = %q, want no stale data past MAX_STALE", reply)
+	}
+}
+
+func TestFallbackOnlyOnOutage(t *testing.T) {
+	m := newCoinGeckoMock(t)
+	t.Setenv("FALLBACK_MOCK_ON_ERROR", "true")
+
+	// a typo is not an outage: the not-found error and its suggestions win
+	_, label, err := marketDataOrFallback(context.Background(), "solanna")
+	if err == nil || label != "" {
+		t.Fatalf("unknown symbol: label %q, err %v; want the lookup error", label, err)
+	}
+	var ue *UnknownSymbolError
+	if reply, err := BuildRiskReply(context.Background(), "solanna"); !errors.As(err, &ue) {
+		t.Errorf("risk reply for unknown symbol = %q, %v; want the lookup error, not a score", reply, err)
+	}
+
+	// an outage with nothing cached is reported, not scored as zeros
+	m.failWith.Store(http.StatusServiceUnavailable)
+	if _, _, err := marketDataOrFallback(context.Background(), "eth"); err == nil || !IsRetryable(err) {
+		t.Errorf("outage without cache: err = %v, want retryable unavailable error", err)
+	}
+	if reply, err := BuildRiskReply(context.Background(), "eth"); err == nil || strings.Contains(reply, "RiskScore") {
+		t.Errorf("risk reply during outage = %q, %v; want an error, not a score", reply, err)
+	}
+}
+
+func TestMarketRepliesReturnProviderErrors(t *testing.T) {
+	m := newCoinGeckoMock(t)
+	m.failWith.Store(http.StatusServiceUnavailable)
+
+	ctx := context.Background()
+	runs := map[string]func() (string, error){
+		"hype":      func() (string, error) { return RunHype(ctx, []string{"eth"}) },
+		"sentiment": func() (string, error) { return RunSentiment(ctx, []string{"eth"}) },
+		"watchlist": func() (string, error) { return BuildWatchlistSentimentReply(ctx, []string{"eth", "sol"}) },
+		"riskcheck": func() (string, error) { return RunRiskCheck(ctx, []string{"eth"}) },
+		"balance":   func() (string, error) { return RunBalance(ctx, []string{"eth"}) },
+		"dumpalert": func() (string, error) { return RunDumpAlert(context.Background(), []string{"eth"}) },
+	}
+	for name, run := range runs {
+		// the dispatcher maps the error for the user and counts it by reason
+		reply, err := run()
+		var re *RetryableError
+		if reply != "" || !errors.As(err, &re) || re.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("%s = %q, %v; want the upstream 503 as the error", name, reply, err)
+			continue
+		}
+		if msg := UserFacingError(err); !strings.Contains(msg, "having trouble") {
+			t.Errorf("%s: UserFacingError = %q, want the outage message", name, msg)
+		}
+	}
+}
+
+func TestGetMarketDataContextCancelled(t *testing.T) {
+	m := newCoinGeckoMock(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := GetMarketDataContext(ctx, "btc")
+	if !errors.Is(err, context.Canceled) || IsRetryable(err) {
+		t.Errorf("err = %v, want non-retryable context.Canceled", err)
+	}
+	if MarketDataDown() {
+		t.Error("an abandoned call marked market data as down")
+	}
+	if n := m.requests.Load(); n != 0 {
+		t.Errorf("%d requests sent for a cancelled call, want 0", n)
+	}
+}
+
+func TestGetMarketDataBatch(t *testing.T) {
+	m := newCoinGeckoMock(t)
+
+	data, err := GetMarketDataBatch([]string{"BTC", "partialcoin", "missingcoin"})
+	if err != nil {
+		t.Fatalf("GetMarketDataBatch: %v", err)
+	}
+	if len(data) != 2 || data["btc"].PriceUSD != 65000.5 || data["partialcoin"].PriceUSD != 0.0042 {
+		t.Errorf("unexpected batch result: %+v", data)
+	}
+	if got := m.requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want a single markets request", got)
+	}
+
+	// batch results are cached for single lookups
+	if _, err := GetMarketData("btc"); err != nil {
+		t.Fatalf("GetMarketData: %v", err)
+	}
+	if got := m.requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want cached lookup", got)
+	}
+}
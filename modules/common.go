@@ -1,8 +1,47 @@
 package modules
 
-import "time"
+import (
+	"log"
+	"sync"
+	"time"
+)
 
 // Only keep TimeNowUTC here
 func TimeNowUTC() time.Time {
 	return time.Now().UTC()
 }
+
+const replyTimeLayout = "2006-01-02 15:04:05 MST"
+
+var (
+	replyTZMu     sync.Mutex
+	replyTZName   string
+	replyTZLoc    = time.UTC
+	replyTZLoaded bool
+)
+
+// replyLocation returns the REPLY_TZ zone (IANA name, default UTC). An invalid
+// zone falls back to UTC with a warning logged once per value.
+func replyLocation() *time.Location {
+	name := cfg().ReplyTZ
+	replyTZMu.Lock()
+	defer replyTZMu.Unlock()
+	if replyTZLoaded && name == replyTZName {
+		return replyTZLoc
+	}
+	replyTZName, replyTZLoc, replyTZLoaded = name, time.UTC, true
+	if name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			log.Printf("Warning: invalid REPLY_TZ %q, using UTC: %v", name, err)
+		} else {
+			replyTZLoc = loc
+		}
+	}
+	return replyTZLoc
+}
+
+// FormatReplyTime renders t for user-facing replies in the REPLY_TZ zone.
+func FormatReplyTime(t time.Time) string {
+	return t.In(replyLocation()).Format(replyTimeLayout)
+}
@@ -1,10 +1,10 @@
 package modules
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"strings"
 	"time"
 )
@@ -15,31 +15,48 @@ import (
 // GetCoinGeckoFull fetches the full CoinGecko JSON for a given id or symbol.
 // Returns a generic map (same shape as JSON).
 func GetCoinGeckoFull(idOrSymbol string) (map[string]interface{}, error) {
+	return GetCoinGeckoFullContext(context.Background(), idOrSymbol)
+}
+
+// GetCoinGeckoFullContext is GetCoinGeckoFull bounded by ctx.
+func GetCoinGeckoFullContext(ctx context.Context, idOrSymbol string) (map[string]interface{}, error) {
+	return getCoinGeckoFull(ctx, idOrSymbol, false)
+}
+
+// GetCoinGeckoExtended is GetCoinGeckoFull with the 7-day sparkline included
+// (market_data.sparkline_7d.price), for FormatCoinGeckoExtended.
+func GetCoinGeckoExtended(idOrSymbol string) (map[string]interface{}, error) {
+	return GetCoinGeckoExtendedContext(context.Background(), idOrSymbol)
+}
+
+// GetCoinGeckoExtendedContext is GetCoinGeckoExtended bounded by ctx.
+func GetCoinGeckoExtendedContext(ctx context.Context, idOrSymbol string) (map[string]interface{}, error) {
+	return getCoinGeckoFull(ctx, idOrSymbol, true)
+}
+
+func getCoinGeckoFull(ctx context.Context, idOrSymbol string, sparkline bool) (map[string]interface{}, error) {
 	s := strings.TrimSpace(idOrSymbol)
 	if s == "" {
 		return nil, fmt.Errorf("empty idOrSymbol")
 	}
 
-	// try symbol mapping first
-	l := strings.ToLower(s)
-	if id, ok := cgSymbolToID[l]; ok {
-		l = id
+	sym, id, err := ResolveSymbol(s)
+	if err != nil {
+		return nil, err
 	}
 
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/%s?localization=false&tickers=false&market_data=true&community_data=false&developer_data=false&sparkline=false", l)
-	client := &http.Client{Timeout: 12 * time.Second}
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("Accept", "application/json")
-	resp, err := client.Do(req)
+	url := fmt.Sprintf("%s/coins/%s?localization=false&tickers=false&market_data=true&community_data=false&developer_data=false&sparkline=%t", coinGeckoBaseURL(), id, sparkline)
+	client := newHTTPClient(12 * time.Second)
+	resp, err := doCoinGecko(ctx, client, url)
 	if err != nil {
-		return nil, fmt.Errorf("coingecko http err: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		// read body to include in error (but truncate)
 		bodyB, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		return nil, fmt.Errorf("coingecko status %d: %s", resp.StatusCode, string(bodyB))
+		return nil, unknownSymbolOnNotFound(sym, statusError(resp.StatusCode, "coingecko status %d: %s", resp.StatusCode, string(bodyB)))
 	}
 
 	var out map[string]interface{}
@@ -105,8 +122,41 @@ func FormatCoinGeckoSummary(full map[string]interface{}) string {
 	mcap := getFloat("market_data", "market_cap", "usd")
 
 	// Build summary
-	summary := fmt.Sprintf("%s (%s)\nPrice: $%.6f\n24h: %+0.2f%% • Volume: $%.0f • MarketCap: $%.0f",
-		nameOr(symbol, name), strings.ToUpper(symbol), price, change24, vol, mcap)
+	summary := fmt.Sprintf("%s (%s)\nPrice: %s\n24h: %+0.2f%% • Volume: %s • MarketCap: %s",
+		nameOr(symbol, name), strings.ToUpper(symbol), FormatPrice(price), change24, FormatLargeUSD(vol), FormatLargeUSD(mcap))
+	return summary
+}
+
+// sparklineWidth is how many characters the extended gecko sparkline uses
+// (one per 7 hours of the 168 hourly points).
+const sparklineWidth = 24
+
+// FormatCoinGeckoExtended is FormatCoinGeckoSummary plus 7d/30d changes and a
+// sparkline of the last 7 days, from a GetCoinGeckoExtended response.
+func FormatCoinGeckoExtended(full map[string]interface{}) string {
+	summary := FormatCoinGeckoSummary(full)
+	if full == nil {
+		return summary
+	}
+	change7d := safeGetFloat(full, "market_data", "price_change_percentage_7d_in_currency", "usd")
+	change30d := safeGetFloat(full, "market_data", "price_change_percentage_30d_in_currency", "usd")
+	summary += fmt.Sprintf("\n7d: %+0.2f%% • 30d: %+0.2f%%", change7d, change30d)
+
+	var prices []float64
+	if md, ok := full["market_data"].(map[string]interface{}); ok {
+		if spark, ok := md["sparkline_7d"].(map[string]interface{}); ok {
+			if raw, ok := spark["price"].([]interface{}); ok {
+				for _, v := range raw {
+					if f, ok := v.(float64); ok {
+						prices = append(prices, f)
+					}
+				}
+			}
+		}
+	}
+	if line := Sparkline(prices, sparklineWidth); line != "" {
+		summary += "\n7d chart: " + line
+	}
 	return summary
 }
 
@@ -123,84 +173,128 @@ func nameOr(sym, name string) string {
 // GetMarketCap returns a human readable market cap string for the symbol.
 // Signature matches main.go expectation: returns (string, error)
 func GetMarketCap(symbol string) (string, error) {
+	return GetMarketCapContext(context.Background(), symbol)
+}
+
+// GetMarketCapContext is GetMarketCap bounded by ctx.
+func GetMarketCapContext(ctx context.Context, symbol string) (string, error) {
 	if strings.TrimSpace(symbol) == "" {
 		return "Usage: marketcap [token]", nil
 	}
 	// Prefer using our fast GetMarketData cache
-	md, err := GetMarketData(symbol)
+	md, stale, err := GetMarketDataStaleContext(ctx, symbol)
 	if err == nil {
 		if md.MarketCapUSD > 0 {
-			return fmt.Sprintf("$%.0f", md.MarketCapUSD), nil
+			if stale {
+				return staleValueReply("market cap", FormatLargeUSD(md.MarketCapUSD), md.RetrievedAt), nil
+			}
+			return FormatLargeUSD(md.MarketCapUSD), nil
 		}
 		// if not present, fall through to full fetch
 	}
 
 	// fallback to full fetch
-	full, err := GetCoinGeckoFull(symbol)
+	full, err := GetCoinGeckoFullContext(ctx, symbol)
 	if err != nil {
+		if reply, ok := fallbackReply(symbol, "market cap", err, func(md MarketData) string { return FormatLargeUSD(md.MarketCapUSD) }); ok {
+			return reply, nil
+		}
 		return "", err
 	}
 	mcap := safeGetFloat(full, "market_data", "market_cap", "usd")
 	if mcap <= 0 {
 		return "Market cap: unavailable", nil
 	}
-	return fmt.Sprintf("$%.0f", mcap), nil
+	return FormatLargeUSD(mcap), nil
 }
 
 // GetVolume returns 24h volume for the symbol as string (string, error)
 func GetVolume(symbol string) (string, error) {
+	return GetVolumeContext(context.Background(), symbol)
+}
+
+// GetVolumeContext is GetVolume bounded by ctx.
+func GetVolumeContext(ctx context.Context, symbol string) (string, error) {
 	if strings.TrimSpace(symbol) == "" {
 		return "Usage: volume [token]", nil
 	}
-	md, err := GetMarketData(symbol)
+	md, stale, err := GetMarketDataStaleContext(ctx, symbol)
 	if err == nil {
 		if md.Volume24h > 0 {
-			return fmt.Sprintf("$%.0f", md.Volume24h), nil
+			if stale {
+				return staleValueReply("volume", FormatLargeUSD(md.Volume24h), md.RetrievedAt), nil
+			}
+			return FormatLargeUSD(md.Volume24h), nil
 		}
 	}
-	full, err := GetCoinGeckoFull(symbol)
+	full, err := GetCoinGeckoFullContext(ctx, symbol)
 	if err != nil {
+		if reply, ok := fallbackReply(symbol, "volume", err, func(md MarketData) string { return FormatLargeUSD(md.Volume24h) }); ok {
+			return reply, nil
+		}
 		return "", err
 	}
 	vol := safeGetFloat(full, "market_data", "total_volume", "usd")
 	if vol <= 0 {
 		return "Volume: unavailable", nil
 	}
-	return fmt.Sprintf("$%.0f", vol), nil
+	return FormatLargeUSD(vol), nil
 }
 
 // GetCoinPrice returns the current USD price as string (string, error)
 func GetCoinPrice(symbol string) (string, error) {
+	return GetCoinPriceContext(context.Background(), symbol)
+}
+
+// GetCoinPriceContext is GetCoinPrice bounded by ctx.
+func GetCoinPriceContext(ctx context.Context, symbol string) (string, error) {
 	if strings.TrimSpace(symbol) == "" {
 		return "Usage: price [token]", nil
 	}
-	md, err := GetMarketData(symbol)
+	md, stale, err := GetMarketDataStaleContext(ctx, symbol)
 	if err == nil && md.PriceUSD > 0 {
-		return fmt.Sprintf("$%.6f", md.PriceUSD), nil
+		if stale {
+			return staleValueReply("price", FormatPrice(md.PriceUSD), md.RetrievedAt), nil
+		}
+		return FormatPrice(md.PriceUSD), nil
 	}
-	full, err := GetCoinGeckoFull(symbol)
+	full, err := GetCoinGeckoFullContext(ctx, symbol)
 	if err != nil {
+		if reply, ok := fallbackReply(symbol, "price", err, func(md MarketData) string { return FormatPrice(md.PriceUSD) }); ok {
+			return reply, nil
+		}
 		return "", err
 	}
 	price := safeGetFloat(full, "market_data", "current_price", "usd")
 	if price <= 0 {
 		return "Price: unavailable", nil
 	}
-	return fmt.Sprintf("$%.6f", price), nil
+	return FormatPrice(price), nil
 }
 
 // GetTrendSnapshot returns a short human-readable trend string for a token.
 // Signature: (string, error)
 func GetTrendSnapshot(symbol string) (string, error) {
+	return GetTrendSnapshotContext(context.Background(), symbol)
+}
+
+// GetTrendSnapshotContext is GetTrendSnapshot bounded by ctx.
+func GetTrendSnapshotContext(ctx context.Context, symbol string) (string, error) {
 	if strings.TrimSpace(symbol) == "" {
 		return "Usage: trend [token]", nil
 	}
+	if IsStablecoin(symbol) {
+		return fmt.Sprintf("Trend snapshot for %s: stablecoin (pegged)", strings.ToUpper(canonicalSymbol(symbol))), nil
+	}
 	// Use quick market data
-	md, err := GetMarketData(symbol)
+	md, stale, err := GetMarketDataStaleContext(ctx, symbol)
 	if err != nil {
 		// try full fallback for more fields
-		full, err2 := GetCoinGeckoFull(symbol)
+		full, err2 := GetCoinGeckoFullContext(ctx, symbol)
 		if err2 != nil {
+			if reply, ok := fallbackReply(symbol, "24h change", err2, func(md MarketData) string { return fmt.Sprintf("%+0.2f%%", md.Change24h) }); ok {
+				return fmt.Sprintf("Trend snapshot for %s: %s", strings.ToUpper(symbol), reply), nil
+			}
 			return "", err
 		}
 		// attempt to derive change
@@ -219,5 +313,9 @@ func GetTrendSnapshot(symbol string) (string, error) {
 	} else if change <= -1 {
 		trend = "bearish momentum"
 	}
-	return fmt.Sprintf("Trend snapshot for %s: %s (24h %+0.2f%%)", strings.ToUpper(symbol), trend, change), nil
+	reply := fmt.Sprintf("Trend snapshot for %s: %s (24h %+0.2f%%)", strings.ToUpper(symbol), trend, change)
+	if stale {
+		reply = withLabel(staleLabel(md.RetrievedAt), reply)
+	}
+	return reply, nil
 }
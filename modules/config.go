@@ -0,0 +1,393 @@
+package modules
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config is every environment tunable the modules package reads, parsed once.
+// Invalid values fall back to the defaults; validateEnv in package main reports
+// them at startup.
+type Config struct {
+	// MockMode (MOCK_MODE) answers market commands with canned data.
+	MockMode bool
+	// FallbackMockOnError (FALLBACK_MOCK_ON_ERROR) answers with labeled
+	// last-known values (up to MAX_STALE old) when the provider is down.
+	FallbackMockOnError bool
+	// MockRealistic (MOCK_REALISTIC) varies mock detections per tick, drawn from
+	// MockBursts (MOCK_BURSTS) and capped at MockMaxPerTick (MOCK_MAX_PER_TICK, default 10).
+	MockRealistic  bool
+	MockBursts     []MockBurst
+	MockMaxPerTick int
+
+	// GoogleAPIKey (GOOGLE_API_KEY) and OpenAIAPIKey (OPENAI_API_KEY) enable
+	// the AI backend; Gemini is preferred when both are set.
+	GoogleAPIKey string
+	OpenAIAPIKey string
+	// Per-provider models, so a fallback or race uses each provider's own
+	// choice: GeminiModel (GEMINI_MODEL, or its older alias GOOGLE_MODEL;
+	// default gemini-2.5-flash) and OpenAIModel (OPENAI_MODEL, default
+	// gpt-4o-mini).
+	GeminiModel string
+	OpenAIModel string
+	// SystemPrompt (SYSTEM_PROMPT) is the default AI persona.
+	SystemPrompt string
+	// AIRace (AI_RACE) queries both providers for detection summaries.
+	AIRace bool
+	// AICacheTTL (AI_CACHE_TTL, default 10m, 0 = off) reuses identical AI replies.
+	AICacheTTL time.Duration
+	// Generation settings: SUMMARY_MAX_TOKENS (128), SUMMARY_TEMPERATURE (0.1),
+	// AI_MAX_TOKENS (512), AI_TEMPERATURE (0.4).
+	SummaryMaxTokens   int
+	SummaryTemperature float64
+	ChatMaxTokens      int
+	ChatTemperature    float64
+	// AI HTTP timeout = AITimeoutBase (AI_TIMEOUT_BASE, default 12s) +
+	// AITimeoutPerToken (AI_TIMEOUT_PER_TOKEN, default 30ms) x max tokens.
+	AITimeoutBase     time.Duration
+	AITimeoutPerToken time.Duration
+	// AIContextTokens (AI_CONTEXT_TOKENS, 0 = per-provider default) bounds the
+	// estimated prompt context of multi-turn AI requests; oldest turns go first.
+	AIContextTokens int
+
+	// ProxyURL (PROXY_URL) routes outbound HTTP; empty honors HTTP(S)_PROXY.
+	ProxyURL string
+	// LogBufferSize (LOG_BUFFER_SIZE, default 0 = off) keeps that many recent
+	// log lines in memory for GET /logs.
+	LogBufferSize int
+	// MaxStale (MAX_STALE, default 10m) is how old cached market data may be
+	// when served after a failed fetch.
+	MaxStale time.Duration
+	// CoinGeckoRatePerMin (COINGECKO_RATE_PER_MIN, 0 = off) paces requests;
+	// CoinGeckoMaxRetryWait (COINGECKO_MAX_RETRY_WAIT, default 5s) is the
+	// longest Retry-After worth waiting out.
+	CoinGeckoRatePerMin   int
+	CoinGeckoMaxRetryWait time.Duration
+
+	// ReplyTZ (REPLY_TZ, IANA name, default UTC) is the zone for reply timestamps.
+	ReplyTZ string
+	// ReplyRawNumbers (REPLY_RAW_NUMBERS) shows full figures instead of $1.43T.
+	ReplyRawNumbers bool
+	// ReplyModeBanner (REPLY_MODE_BANNER, default true) prefixes degraded replies.
+	ReplyModeBanner bool
+
+	// RiskAversion (RISK_AVERSION, 0..1, default 0.5) weighs risk in balance.
+	RiskAversion float64
+	// Watchlist (WATCHLIST, default BTC,ETH,SOL) backs sentiment all, dumpalert and digest.
+	Watchlist []string
+	// Stablecoins (STABLECOINS) adds pegged tokens to the built-in list.
+	Stablecoins []string
+	// DumpBands (DUMP_BANDS) is the market-cap-aware dump curve.
+	DumpBands []DumpBand
+
+	// ScannerMinConfidence (SCANNER_MIN_CONFIDENCE, 0..1) and
+	// ScannerMinMarketCap (SCANNER_MIN_MARKETCAP, USD) filter detections; 0 = off.
+	ScannerMinConfidence float64
+	ScannerMinMarketCap  float64
+	// CashtagGate (CASHTAG_GATE: strict, lenient (default) or off) checks
+	// scanned cashtags against CoinGecko before they become detections.
+	CashtagGate string
+	// XScanMode (X_SCAN_MODE: search (default) or timeline) is how the real
+	// scanner finds KOL posts.
+	XScanMode string
+	// XAPIBaseURL (X_API_BASE_URL, default https://api.twitter.com/2) is the
+	// X API root the real scanner calls, e.g. a proxy.
+	XAPIBaseURL string
+	// TokenPattern (TOKEN_PATTERN) is the regex ExtractTokens uses for
+	// tickers; its first capture group is the token. EVM addresses are always
+	// matched.
+	TokenPattern string
+	// ScannerMaxResults (SCANNER_MAX_RESULTS, default 10) caps tweets per
+	// query or timeline per poll; ScannerLookback (SCANNER_LOOKBACK, default
+	// 1h) bounds the initial fetch when there is no stored sinceID. Mock mode
+	// ignores both.
+	ScannerMaxResults int
+	ScannerLookback   time.Duration
+
+	// DetectionEnrich (DETECTION_ENRICH) attaches a market snapshot to
+	// detections before they are saved.
+	DetectionEnrich bool
+
+	// State files: DETECTION_LOG_FILE (detections.jsonl), MONITOR_STATE_FILE
+	// (monitors.json), ALERT_STATE_FILE (alerts.json), X_STATE_FILE
+	// (xscanner_state.json).
+	DetectionLogFile string
+	MonitorStateFile string
+	AlertStateFile   string
+	XStateFile       string
+	// DetectionRotation is DETECTION_LOG_MAX_MB / _MAX_AGE / _KEEP / _COMPRESS.
+	DetectionRotation RotationPolicy
+	// AuditLog (AUDIT_LOG, default off) is the JSONL file every command
+	// dispatch is recorded to.
+	AuditLog string
+
+	// DigestInterval (DIGEST_INTERVAL, e.g. 24h or 7d, 0 = off) schedules the
+	// digest, posted to DigestWebhookURL (DIGEST_WEBHOOK_URL) in
+	// DigestWebhookFormat (DIGEST_WEBHOOK_FORMAT: plain, slack, discord or
+	// telegram) or logged; DigestAI (DIGEST_AI) appends an AI narrative.
+	DigestInterval      time.Duration
+	DigestWebhookURL    string
+	DigestWebhookFormat string
+	DigestAI            bool
+
+	// PrivateKey (PRIVATE_KEY), OwnerAddress (OWNER_ADDRESS) and BackendURL
+	// (BACKEND_URL, default http://localhost:8080) are checked by GET /selftest.
+	PrivateKey   string
+	OwnerAddress string
+	BackendURL   string
+}
+
+// AIConfigured reports whether either AI provider key is set.
+func (c *Config) AIConfigured() bool {
+	return c.GoogleAPIKey != "" || c.OpenAIAPIKey != ""
+}
+
+// LoadConfig reads Config from the environment.
+func LoadConfig() *Config {
+	c := &Config{
+		MockMode:            envBool("MOCK_MODE"),
+		FallbackMockOnError: envBool("FALLBACK_MOCK_ON_ERROR"),
+		MockRealistic:       envBool("MOCK_REALISTIC"),
+		MockBursts:          defaultMockBursts,
+		MockMaxPerTick:      defaultMockMaxPerTick,
+
+		GoogleAPIKey:       envString("GOOGLE_API_KEY"),
+		OpenAIAPIKey:       envString("OPENAI_API_KEY"),
+		GeminiModel:        defaultGeminiModel,
+		OpenAIModel:        defaultOpenAIModel,
+		SystemPrompt:       envString("SYSTEM_PROMPT"),
+		AIRace:             envBool("AI_RACE"),
+		AICacheTTL:         defaultAICacheTTL,
+		SummaryMaxTokens:   128,
+		SummaryTemperature: 0.1,
+		ChatMaxTokens:      512,
+		ChatTemperature:    0.4,
+		AITimeoutBase:      defaultAITimeoutBase,
+		AITimeoutPerToken:  defaultAITimeoutPerToken,
+
+		ProxyURL:              envString("PROXY_URL"),
+		MaxStale:              defaultMaxStale,
+		CoinGeckoMaxRetryWait: defaultCoinGeckoMaxWait,
+
+		ReplyTZ:         envString("REPLY_TZ"),
+		ReplyRawNumbers: envBool("REPLY_RAW_NUMBERS"),
+		ReplyModeBanner: strings.ToLower(envString("REPLY_MODE_BANNER")) != "false",
+
+		CashtagGate:       CashtagGateLenient,
+		XScanMode:         XScanSearch,
+		TokenPattern:      defaultTokenPattern,
+		ScannerMaxResults: defaultScannerMaxResults,
+		ScannerLookback:   defaultScannerLookback,
+
+		RiskAversion: defaultRiskAversion,
+		Watchlist:    defaultWatchlist,
+		DumpBands:    defaultDumpBands,
+
+		DetectionEnrich:  envBool("DETECTION_ENRICH"),
+		DetectionLogFile: defaultDetectionLog,
+		MonitorStateFile: "monitors.json",
+		AlertStateFile:   "alerts.json",
+		XStateFile:       "xscanner_state.json",
+		DetectionRotation: RotationPolicy{
+			MaxBytes: defaultDetectionLogMaxMB << 20,
+			Keep:     defaultDetectionLogKeep,
+			Compress: envBool("DETECTION_LOG_COMPRESS"),
+		},
+
+		DigestWebhookURL:    envString("DIGEST_WEBHOOK_URL"),
+		DigestWebhookFormat: envString("DIGEST_WEBHOOK_FORMAT"),
+		DigestAI:            envBool("DIGEST_AI"),
+
+		PrivateKey:   envString("PRIVATE_KEY"),
+		OwnerAddress: envString("OWNER_ADDRESS"),
+		BackendURL:   "http://localhost:8080",
+	}
+
+	if s := envString("MOCK_BURSTS"); s != "" {
+		if bursts, err := ParseMockBursts(s); err == nil {
+			c.MockBursts = bursts
+		}
+	}
+	if v, err := strconv.Atoi(envString("MOCK_MAX_PER_TICK")); err == nil && v > 0 {
+		c.MockMaxPerTick = v
+	}
+
+	for _, name := range []string{"GEMINI_MODEL", "GOOGLE_MODEL"} {
+		if m := envString(name); m != "" {
+			// the REST path adds "models/" itself
+			c.GeminiModel = strings.TrimPrefix(m, "models/")
+			break
+		}
+	}
+	if v, err := strconv.Atoi(envString("LOG_BUFFER_SIZE")); err == nil && v > 0 {
+		c.LogBufferSize = v
+	}
+	if m := envString("OPENAI_MODEL"); m != "" {
+		c.OpenAIModel = m
+	}
+
+	if d, err := time.ParseDuration(envString("AI_CACHE_TTL")); err == nil && d >= 0 {
+		c.AICacheTTL = d
+	}
+	if v, err := strconv.Atoi(envString("SUMMARY_MAX_TOKENS")); err == nil && v > 0 {
+		c.SummaryMaxTokens = v
+	}
+	if v, err := strconv.ParseFloat(envString("SUMMARY_TEMPERATURE"), 64); err == nil && v >= 0 && v <= 2 {
+		c.SummaryTemperature = v
+	}
+	if v, err := strconv.Atoi(envString("AI_MAX_TOKENS")); err == nil && v > 0 {
+		c.ChatMaxTokens = v
+	}
+	if v, err := strconv.ParseFloat(envString("AI_TEMPERATURE"), 64); err == nil && v >= 0 && v <= 2 {
+		c.ChatTemperature = v
+	}
+
+	if d, err := time.ParseDuration(envString("AI_TIMEOUT_BASE")); err == nil && d >= 0 {
+		c.AITimeoutBase = d
+	}
+	if d, err := time.ParseDuration(envString("AI_TIMEOUT_PER_TOKEN")); err == nil && d >= 0 {
+		c.AITimeoutPerToken = d
+	}
+	if v, err := strconv.Atoi(envString("AI_CONTEXT_TOKENS")); err == nil && v >= 0 {
+		c.AIContextTokens = v
+	}
+
+	if d, err := time.ParseDuration(envString("MAX_STALE")); err == nil && d >= 0 {
+		c.MaxStale = d
+	}
+	if v, err := strconv.Atoi(envString("COINGECKO_RATE_PER_MIN")); err == nil && v > 0 {
+		c.CoinGeckoRatePerMin = v
+	}
+	if d, err := time.ParseDuration(envString("COINGECKO_MAX_RETRY_WAIT")); err == nil && d >= 0 {
+		c.CoinGeckoMaxRetryWait = d
+	}
+
+	if v, err := strconv.ParseFloat(envString("RISK_AVERSION"), 64); err == nil && v >= 0 && v <= 1 {
+		c.RiskAversion = v
+	}
+	if list := upperList(envString("WATCHLIST")); len(list) > 0 {
+		c.Watchlist = list
+	}
+	for _, s := range strings.Split(envString("STABLECOINS"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			c.Stablecoins = append(c.Stablecoins, canonicalSymbol(s))
+		}
+	}
+	if s := envString("DUMP_BANDS"); s != "" {
+		if bands, err := ParseDumpBands(s); err == nil {
+			c.DumpBands = bands
+		}
+	}
+
+	if v, err := strconv.ParseFloat(envString("SCANNER_MIN_CONFIDENCE"), 64); err == nil && v >= 0 && v <= 1 {
+		c.ScannerMinConfidence = v
+	}
+	if v, err := strconv.ParseFloat(envString("SCANNER_MIN_MARKETCAP"), 64); err == nil && v >= 0 {
+		c.ScannerMinMarketCap = v
+	}
+
+	switch mode := strings.ToLower(envString("CASHTAG_GATE")); mode {
+	case CashtagGateOff, CashtagGateStrict:
+		c.CashtagGate = mode
+	}
+	if strings.EqualFold(envString("X_SCAN_MODE"), XScanTimeline) {
+		c.XScanMode = XScanTimeline
+	}
+	if p := envString("TOKEN_PATTERN"); p != "" {
+		c.TokenPattern = p
+	}
+	if u := envString("X_API_BASE_URL"); u != "" {
+		c.XAPIBaseURL = u
+	}
+	if v, err := strconv.Atoi(envString("SCANNER_MAX_RESULTS")); err == nil && v > 0 {
+		c.ScannerMaxResults = v
+	}
+	if d, err := time.ParseDuration(envString("SCANNER_LOOKBACK")); err == nil && d > 0 {
+		c.ScannerLookback = d
+	}
+
+	if p := envString("DETECTION_LOG_FILE"); p != "" {
+		c.DetectionLogFile = p
+	}
+	if p := envString("MONITOR_STATE_FILE"); p != "" {
+		c.MonitorStateFile = p
+	}
+	if p := envString("ALERT_STATE_FILE"); p != "" {
+		c.AlertStateFile = p
+	}
+	if p := envString("X_STATE_FILE"); p != "" {
+		c.XStateFile = p
+	}
+	c.AuditLog = envString("AUDIT_LOG")
+	if v, err := strconv.Atoi(envString("DETECTION_LOG_MAX_MB")); err == nil && v >= 0 {
+		c.DetectionRotation.MaxBytes = int64(v) << 20
+	}
+	if d, err := time.ParseDuration(envString("DETECTION_LOG_MAX_AGE")); err == nil && d >= 0 {
+		c.DetectionRotation.MaxAge = d
+	}
+	if v, err := strconv.Atoi(envString("DETECTION_LOG_KEEP")); err == nil && v >= 0 {
+		c.DetectionRotation.Keep = v
+	}
+
+	if s := envString("DIGEST_INTERVAL"); s != "" {
+		if d, err := ParsePeriod(s); err == nil {
+			c.DigestInterval = d
+		}
+	}
+	if u := strings.TrimRight(envString("BACKEND_URL"), "/"); u != "" {
+		c.BackendURL = u
+	}
+	return c
+}
+
+func envString(name string) string {
+	return strings.TrimSpace(os.Getenv(name))
+}
+
+func envBool(name string) bool {
+	return strings.ToLower(envString(name)) == "true"
+}
+
+// upperList splits a comma-separated list, trimming and uppercasing entries.
+func upperList(s string) []string {
+	var out []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.ToUpper(strings.TrimSpace(t)); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+var (
+	configMu sync.RWMutex
+	config   *Config
+)
+
+// SetConfig installs c as the package configuration; main calls it with
+// LoadConfig() at startup and again on SIGHUP. SetConfig(nil) goes back to
+// reading the environment on every call, which is what tests rely on.
+func SetConfig(c *Config) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	config = c
+}
+
+// CurrentConfig returns the installed Config, or a fresh LoadConfig() if none
+// is set. Treat the result as read-only.
+func CurrentConfig() *Config {
+	return cfg()
+}
+
+func cfg() *Config {
+	configMu.RLock()
+	c := config
+	configMu.RUnlock()
+	if c == nil {
+		return LoadConfig()
+	}
+	return c
+}
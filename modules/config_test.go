@@ -0,0 +1,58 @@
+package modules
+
+import "testing"
+
+func TestLoadConfigDefaults(t *testing.T) {
+	for _, name := range []string{"RISK_AVERSION", "WATCHLIST", "MAX_STALE", "REPLY_MODE_BANNER"} {
+		t.Setenv(name, "")
+	}
+	c := LoadConfig()
+	if c.RiskAversion != defaultRiskAversion || c.MaxStale != defaultMaxStale || !c.ReplyModeBanner {
+		t.Errorf("defaults: %+v", c)
+	}
+	if len(c.Watchlist) != 3 {
+		t.Errorf("Watchlist = %v, want default", c.Watchlist)
+	}
+
+	t.Setenv("RISK_AVERSION", "2") // out of range, ignored
+	t.Setenv("WATCHLIST", " pepe, ,wif")
+	c = LoadConfig()
+	if c.RiskAversion != defaultRiskAversion {
+		t.Errorf("RiskAversion = %v, want default for invalid value", c.RiskAversion)
+	}
+	if len(c.Watchlist) != 2 || c.Watchlist[0] != "PEPE" || c.Watchlist[1] != "WIF" {
+		t.Errorf("Watchlist = %v, want [PEPE WIF]", c.Watchlist)
+	}
+}
+
+func TestSetConfig(t *testing.T) {
+	c := LoadConfig()
+	c.ReplyRawNumbers = true
+	c.Watchlist = []string{"DOGE"}
+	SetConfig(c)
+	t.Cleanup(func() { SetConfig(nil) })
+
+	if got := FormatLargeUSD(2e9); got != "$2,000,000,000" {
+		t.Errorf("FormatLargeUSD with ReplyRawNumbers = %q", got)
+	}
+	if got := Watchlist(); len(got) != 1 || got[0] != "DOGE" {
+		t.Errorf("Watchlist = %v, want [DOGE]", got)
+	}
+}
+
+func TestLoadConfigAIModels(t *testing.T) {
+	t.Setenv("GEMINI_MODEL", "")
+	t.Setenv("GOOGLE_MODEL", "models/gemini-2.5-pro")
+	t.Setenv("OPENAI_MODEL", "")
+	c := LoadConfig()
+	if c.GeminiModel != "gemini-2.5-pro" || c.OpenAIModel != defaultOpenAIModel {
+		t.Errorf("models = %q / %q, want the GOOGLE_MODEL alias without models/ and the OpenAI default", c.GeminiModel, c.OpenAIModel)
+	}
+
+	t.Setenv("GEMINI_MODEL", "gemini-2.5-flash-lite")
+	t.Setenv("OPENAI_MODEL", "gpt-4.1")
+	c = LoadConfig()
+	if c.GeminiModel != "gemini-2.5-flash-lite" || c.OpenAIModel != "gpt-4.1" {
+		t.Errorf("models = %q / %q, want GEMINI_MODEL over GOOGLE_MODEL and OPENAI_MODEL", c.GeminiModel, c.OpenAIModel)
+	}
+}
@@ -1,13 +1,18 @@
 package modules
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 )
 
 // Detection represents a single detection / signal from the scanner.
 type Detection struct {
+	ID         string    `json:"id"`         // deterministic, see DetectionID
 	KOL        string    `json:"kol"`        // nama KOL / influencer
 	Token      string    `json:"token"`      // ticker / token id / nama
 	Signal     string    `json:"signal"`     // e.g. "early_call", "dump_warning"
@@ -16,11 +21,56 @@ type Detection struct {
 	Text       string    `json:"text"`       // raw text/snippet yang memicu deteksi
 	Link       string    `json:"link"`       // optional link (tweet, post, tx)
 	Timestamp  time.Time `json:"timestamp"`  // waktu deteksi
+
+	Summary      string    `json:"summary,omitempty"`      // AI take on the detection, if any
+	SummarizedAt time.Time `json:"summarized_at,omitzero"` // when Summary was produced
+
+	Market *DetectionMarket `json:"market,omitempty"` // market snapshot at save time (DETECTION_ENRICH)
+}
+
+// Sources of detections the agent raises itself (monitor threshold crossings,
+// fired alerts) rather than reads from a KOL post.
+const (
+	SourceMonitor = "monitor"
+	SourceAlert   = "alert"
+)
+
+// IsKOLMention reports whether d is a KOL post rather than one of the agent's
+// own monitor or alert events. Only mentions count towards mention totals,
+// velocity and corroboration.
+func (d Detection) IsKOLMention() bool {
+	return d.Source != SourceMonitor && d.Source != SourceAlert
+}
+
+// KOLMentions returns the detections in dets that are KOL mentions.
+func KOLMentions(dets []Detection) []Detection {
+	out := make([]Detection, 0, len(dets))
+	for _, d := range dets {
+		if d.IsKOLMention() {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// DetectionID derives a stable ID from KOL, token, text and timestamp, so the
+// same detection always gets the same ID (used for dedup and follow-up records).
+func DetectionID(d Detection) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d", d.KOL, strings.ToUpper(d.Token), d.Text, d.Timestamp.UnixNano())))
+	return hex.EncodeToString(h[:8])
+}
+
+// EnsureID sets d.ID if it is empty.
+func (d *Detection) EnsureID() {
+	if d.ID == "" {
+		d.ID = DetectionID(*d)
+	}
 }
 
 // SaveDetection writes a Detection as pretty JSON to filename (overwrites/creates).
 // This is simple and safe for mock/stub usage.
 func SaveDetection(filename string, d Detection) error {
+	d.EnsureID()
 	b, err := json.MarshalIndent(d, "", "  ")
 	if err != nil {
 		return err
@@ -0,0 +1,94 @@
+package modules
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Detection sort keys for FilterOpts.SortBy.
+const (
+	SortByTimestamp  = "timestamp" // default
+	SortByConfidence = "confidence"
+)
+
+// FilterOpts selects, orders and pages detections for FilterDetections. Zero
+// fields don't filter. Results are sorted by SortBy (timestamp by default),
+// newest / highest first unless Ascending; ties are broken by ID so pages
+// stay stable between requests.
+type FilterOpts struct {
+	Token         string
+	KOL           string
+	Signal        string
+	Since         time.Time
+	Until         time.Time
+	MinConfidence float64
+
+	SortBy    string
+	Ascending bool
+
+	Offset int
+	Limit  int // 0 = no limit
+}
+
+// Validate reports an unknown sort key or negative paging values.
+func (o FilterOpts) Validate() error {
+	switch o.SortBy {
+	case "", SortByTimestamp, SortByConfidence:
+	default:
+		return fmt.Errorf("unknown sort %q (want timestamp or confidence)", o.SortBy)
+	}
+	if o.Offset < 0 || o.Limit < 0 {
+		return fmt.Errorf("offset and limit must not be negative")
+	}
+	return nil
+}
+
+// FilterDetections returns the detections matching opts, sorted and paged as
+// FilterOpts describes, plus the number of matches before paging. dets is
+// not modified.
+func FilterDetections(dets []Detection, opts FilterOpts) ([]Detection, int) {
+	token := ""
+	if opts.Token != "" {
+		token = canonicalSymbol(opts.Token)
+	}
+	var out []Detection
+	for _, d := range dets {
+		switch {
+		case token != "" && canonicalSymbol(d.Token) != token,
+			opts.KOL != "" && !strings.EqualFold(d.KOL, opts.KOL),
+			opts.Signal != "" && d.Signal != opts.Signal,
+			!opts.Since.IsZero() && d.Timestamp.Before(opts.Since),
+			!opts.Until.IsZero() && !d.Timestamp.Before(opts.Until),
+			d.Confidence < opts.MinConfidence:
+			continue
+		}
+		out = append(out, d)
+	}
+
+	slices.SortStableFunc(out, func(a, b Detection) int {
+		var c int
+		if opts.SortBy == SortByConfidence {
+			c = cmp.Compare(a.Confidence, b.Confidence)
+		}
+		if c == 0 {
+			c = a.Timestamp.Compare(b.Timestamp)
+		}
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+		if !opts.Ascending {
+			c = -c
+		}
+		return c
+	})
+
+	total := len(out)
+	out = out[min(opts.Offset, total):]
+	if opts.Limit > 0 && len(out) > opts.Limit {
+		out = out[:opts.Limit]
+	}
+	return out, total
+}
@@ -0,0 +1,47 @@
+package modules
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterDetectionsOrder(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dets := []Detection{
+		{ID: "a", Token: "SOL", Confidence: 0.9, Timestamp: t0},
+		{ID: "b", Token: "BTC", Confidence: 0.5, Timestamp: t0.Add(2 * time.Hour)},
+		{ID: "c", Token: "sol", Confidence: 0.7, Timestamp: t0.Add(time.Hour)},
+		{ID: "d", Token: "SOL", Confidence: 0.7, Timestamp: t0.Add(time.Hour)},
+	}
+	ids := func(ds []Detection) string {
+		s := ""
+		for _, d := range ds {
+			s += d.ID
+		}
+		return s
+	}
+
+	for _, tc := range []struct {
+		opts      FilterOpts
+		want      string
+		wantTotal int
+	}{
+		{FilterOpts{}, "bdca", 4},
+		{FilterOpts{Ascending: true}, "acdb", 4},
+		{FilterOpts{SortBy: SortByConfidence}, "adcb", 4},
+		{FilterOpts{Token: "$sol"}, "dca", 3},
+		{FilterOpts{Offset: 1, Limit: 2}, "dc", 4},
+		{FilterOpts{Offset: 9}, "", 4},
+	} {
+		got, total := FilterDetections(dets, tc.opts)
+		if ids(got) != tc.want || total != tc.wantTotal {
+			t.Errorf("%+v: got %q (total %d), want %q (total %d)", tc.opts, ids(got), total, tc.want, tc.wantTotal)
+		}
+	}
+	if ids(dets) != "abcd" {
+		t.Errorf("input reordered to %q", ids(dets))
+	}
+	if err := (FilterOpts{SortBy: "hype"}).Validate(); err == nil {
+		t.Error("unknown sort key accepted")
+	}
+}
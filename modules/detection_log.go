@@ -0,0 +1,147 @@
+package modules
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultDetectionLog = "detections.jsonl"
+
+// DetectionLogPath returns the append-only detection history file
+// (DETECTION_LOG_FILE, default detections.jsonl).
+func DetectionLogPath() string {
+	return cfg().DetectionLogFile
+}
+
+// AppendDetection appends d as one JSON line to filename, keeping history
+// (unlike SaveDetection which overwrites).
+func AppendDetection(filename string, d Detection) error {
+	d.EnsureID()
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	detectionLogMu.Lock()
+	defer detectionLogMu.Unlock()
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+// AttachSummary stores an AI summary for d by appending a follow-up record;
+// LoadDetections merges it into the original detection.
+func AttachSummary(filename string, d Detection, summary string) error {
+	d.Summary = summary
+	d.SummarizedAt = TimeNowUTC()
+	return AppendDetection(filename, d)
+}
+
+// LoadDetections reads detections from filename with Timestamp >= since,
+// including rotated (and gzipped) siblings that may hold records that recent.
+// Follow-up summary records are merged into the detection they refer to, even
+// when the original was rotated away. Missing files are not an error;
+// malformed lines are skipped.
+func LoadDetections(filename string, since time.Time) ([]Detection, error) {
+	rotated, err := rotatedFiles(filename)
+	if err != nil {
+		return nil, err
+	}
+	l := detectionLoader{since: since, index: map[string]int{}}
+	for _, path := range rotated {
+		// a rotated file only holds records written before its rotation time
+		if at, ok := rotationTime(filename, path); ok && at.Before(since) {
+			continue
+		}
+		if err := l.readFile(path); err != nil {
+			return l.out, err
+		}
+	}
+	return l.out, l.readFile(filename)
+}
+
+// LoadKOLMentions is LoadDetections without the agent's own monitor and alert
+// events; use it wherever detections are counted as KOL mentions.
+func LoadKOLMentions(filename string, since time.Time) ([]Detection, error) {
+	dets, err := LoadDetections(filename, since)
+	return KOLMentions(dets), err
+}
+
+// detectionLoader accumulates records across the files LoadDetections reads,
+// oldest first, so later follow-ups find their originals.
+type detectionLoader struct {
+	since time.Time
+	out   []Detection
+	index map[string]int
+}
+
+func (l *detectionLoader) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var d Detection
+		if err := json.Unmarshal(sc.Bytes(), &d); err != nil {
+			continue
+		}
+		if d.Timestamp.Before(l.since) {
+			continue
+		}
+		// records written before IDs existed get theirs derived on load
+		d.EnsureID()
+		if i, ok := l.index[d.ID]; ok {
+			if d.Summary != "" {
+				l.out[i].Summary = d.Summary
+				l.out[i].SummarizedAt = d.SummarizedAt
+			}
+			continue
+		}
+		l.index[d.ID] = len(l.out)
+		l.out = append(l.out, d)
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	return nil
+}
+
+// FindDetection returns the detection with the given ID from filename.
+func FindDetection(filename, id string) (Detection, bool, error) {
+	dets, err := LoadDetections(filename, time.Time{})
+	if err != nil {
+		return Detection{}, false, err
+	}
+	for _, d := range dets {
+		if d.ID == id {
+			return d, true, nil
+		}
+	}
+	return Detection{}, false, nil
+}
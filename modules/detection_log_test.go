@@ -0,0 +1,105 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadDetectionsReadsRotatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "detections.jsonl")
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return day.Add(time.Duration(h) * time.Hour) }
+	rotate := func(now time.Time, compress bool) {
+		t.Helper()
+		got, err := RotateLog(logFile, RotationPolicy{MaxBytes: 1, Keep: 5, Compress: compress}, now)
+		if err != nil || got == "" {
+			t.Fatalf("RotateLog = %q, %v", got, err)
+		}
+	}
+	appendDet := func(d Detection) {
+		t.Helper()
+		if err := AppendDetection(logFile, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ancient := Detection{KOL: "a", Token: "OLD", Timestamp: at(-48)}
+	early := Detection{KOL: "a", Token: "SOL", Timestamp: at(1)}
+	mid := Detection{KOL: "b", Token: "PEPE", Timestamp: at(5)}
+	late := Detection{KOL: "c", Token: "BTC", Timestamp: at(9)}
+
+	appendDet(ancient)
+	rotate(at(-47), false)
+	appendDet(early)
+	rotate(at(2), true)
+	appendDet(mid)
+	rotate(at(6), false)
+	appendDet(late)
+	if err := AttachSummary(logFile, early, "rotated-away original"); err != nil {
+		t.Fatal(err)
+	}
+
+	gz, _ := filepath.Glob(filepath.Join(dir, "*.gz"))
+	if len(gz) != 1 {
+		t.Fatalf("want one gzipped rotation, got %v", gz)
+	}
+
+	dets, err := LoadDetections(logFile, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for _, d := range dets {
+		tokens = append(tokens, d.Token)
+	}
+	if len(dets) != 3 || tokens[0] != "SOL" || tokens[1] != "PEPE" || tokens[2] != "BTC" {
+		t.Fatalf("tokens = %v, want [SOL PEPE BTC]", tokens)
+	}
+	if dets[0].Summary != "rotated-away original" {
+		t.Errorf("summary follow-up not merged across files: %+v", dets[0])
+	}
+
+	all, err := LoadDetections(logFile, time.Time{})
+	if err != nil || len(all) != 4 {
+		t.Fatalf("LoadDetections(zero) = %d detections, %v; want 4", len(all), err)
+	}
+
+	// a rotation stamped before since is skipped without being read, even if
+	// a (mis-dated) record inside it would pass the filter
+	stale := filepath.Join(dir, "detections-20260220T000000Z.jsonl")
+	if err := os.WriteFile(stale, []byte(`{"kol":"x","token":"SKIP","timestamp":"2026-03-01T03:00:00Z"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dets, err = LoadDetections(logFile, day)
+	if err != nil || len(dets) != 3 {
+		t.Errorf("LoadDetections read a rotation older than since: %d detections, %v", len(dets), err)
+	}
+}
+
+func TestMonitorAndAlertEventsAreNotMentions(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "detections.jsonl")
+	t.Setenv("DETECTION_LOG_FILE", logFile)
+	now := TimeNowUTC()
+	for i, d := range []Detection{
+		{KOL: "ansem", Token: "SOL", Source: "x"},
+		{Token: "SOL", Source: SourceMonitor, Text: "price above 200"},
+		{Token: "SOL", Source: SourceMonitor, Text: "price above 200"},
+		{Token: "SOL", Source: SourceAlert, Text: "SOL price>200", Confidence: 1},
+	} {
+		d.Timestamp = now.Add(-time.Duration(i+1) * time.Minute)
+		if err := AppendDetection(logFile, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mentions, err := LoadKOLMentions(logFile, now.Add(-time.Hour))
+	if err != nil || len(mentions) != 1 || mentions[0].KOL != "ansem" {
+		t.Fatalf("LoadKOLMentions = %+v, %v; want only the KOL post", mentions, err)
+	}
+	if cur, _, _ := ComputeMentionVelocity("sol", time.Hour); cur != 1 {
+		t.Errorf("mention velocity counts %v mentions in the last hour, want 1", cur)
+	}
+}
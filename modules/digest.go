@@ -0,0 +1,285 @@
+package modules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	digestTopN     = 5
+	digestMovers   = 3
+	digestNotables = 5
+)
+
+// GenerateDigest summarizes the detections of the last period: top tokens,
+// top KOLs, the biggest 24h movers among the mentioned tokens and notable
+// alert/monitor hits. With DIGEST_AI=true an AI narrative is appended; if the
+// AI call fails the plain report is returned.
+func GenerateDigest(period time.Duration) (string, error) {
+	if period <= 0 {
+		return "", fmt.Errorf("digest period must be positive")
+	}
+	now := TimeNowUTC()
+	dets, err := LoadDetections(DetectionLogPath(), now.Add(-period))
+	if err != nil {
+		return "", err
+	}
+	label := formatPeriod(period)
+	if len(dets) == 0 {
+		return fmt.Sprintf("Digest (%s): no detections.", label), nil
+	}
+
+	tokens := map[string]int{}
+	kols := map[string]int{}
+	var notable []Detection
+	for _, d := range dets {
+		// the agent's own monitor/alert events are reported, not counted as mentions
+		if !d.IsKOLMention() {
+			notable = append(notable, d)
+			continue
+		}
+		if tok := strings.ToUpper(d.Token); tok != "" {
+			tokens[tok]++
+		}
+		if d.KOL != "" {
+			kols[d.KOL]++
+		}
+	}
+	topTokens := topCounts(tokens, digestTopN)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Digest (%s): %d detections across %d tokens from %d KOLs.", label, len(dets)-len(notable), len(tokens), len(kols))
+	if len(topTokens) > 0 {
+		b.WriteString("\nTop tokens:")
+		for i, t := range topTokens {
+			fmt.Fprintf(&b, "\n%d. $%s – %d mentions", i+1, t.key, t.n)
+		}
+	}
+	if top := topCounts(kols, digestTopN); len(top) > 0 {
+		b.WriteString("\nTop KOLs:")
+		for i, k := range top {
+			fmt.Fprintf(&b, "\n%d. %s – %d calls", i+1, k.key, k.n)
+		}
+	}
+	if movers := digestMoversFor(topTokens); len(movers) > 0 {
+		b.WriteString("\nBiggest movers (24h):")
+		for _, md := range movers {
+			fmt.Fprintf(&b, "\n- $%s %+.2f%% at %s", strings.ToUpper(md.Symbol), md.Change24h, FormatPrice(md.PriceUSD))
+		}
+	}
+	if len(notable) > 0 {
+		b.WriteString("\nNotable alerts:")
+		// newest first
+		for i := len(notable) - 1; i >= 0 && i >= len(notable)-digestNotables; i-- {
+			d := notable[i]
+			fmt.Fprintf(&b, "\n- %s $%s: %s", FormatReplyTime(d.Timestamp), strings.ToUpper(d.Token), d.Text)
+		}
+	}
+
+	report := b.String()
+	if cfg().DigestAI {
+		narrative, err := ForwardWithOptions("Write a short narrative (3-4 sentences) of this crypto signal digest for traders. No financial advice.\n\n"+report, SummaryAIOptions())
+		if err != nil {
+			log.Printf("[digest] AI narrative skipped: %v", err)
+		} else if narrative != "" {
+			report += "\n\n" + narrative
+		}
+	}
+	return report, nil
+}
+
+type keyCount struct {
+	key string
+	n   int
+}
+
+// topCounts returns the n largest counts, ties broken alphabetically.
+func topCounts(m map[string]int, n int) []keyCount {
+	out := make([]keyCount, 0, len(m))
+	for k, v := range m {
+		out = append(out, keyCount{k, v})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].n != out[j].n {
+			return out[i].n > out[j].n
+		}
+		return out[i].key < out[j].key
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// digestMoversFor fetches market data for the top tokens and returns the
+// largest absolute 24h changes. Market errors just leave tokens out.
+func digestMoversFor(top []keyCount) []MarketData {
+	if len(top) == 0 || cfg().MockMode {
+		return nil
+	}
+	syms := make([]string, 0, len(top))
+	for _, t := range top {
+		syms = append(syms, t.key)
+	}
+	data, err := GetMarketDataBatchContext(context.Background(), syms, BatchOptions{Timeout: 15 * time.Second})
+	if err != nil {
+		log.Printf("[digest] market data: %v", err)
+	}
+	movers := make([]MarketData, 0, len(data))
+	for _, md := range data {
+		movers = append(movers, md)
+	}
+	sort.Slice(movers, func(i, j int) bool {
+		return math.Abs(movers[i].Change24h) > math.Abs(movers[j].Change24h)
+	})
+	if len(movers) > digestMovers {
+		movers = movers[:digestMovers]
+	}
+	return movers
+}
+
+// formatPeriod renders whole days as "7d" and anything else as a duration.
+func formatPeriod(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// ParsePeriod parses a digest period: a Go duration ("12h") or whole days ("7d").
+func ParsePeriod(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid period %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period %q", s)
+	}
+	return d, nil
+}
+
+// RunDigest implements "digest [period]", e.g. "digest 7d". Default 24h.
+func RunDigest(ctx context.Context, args []string) (string, error) {
+	period := 24 * time.Hour
+	if len(args) > 0 {
+		d, err := ParsePeriod(args[0])
+		if err != nil {
+			return "Usage: digest [period]. Examples: digest 24h, digest 7d", nil
+		}
+		period = d
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return GenerateDigest(period)
+}
+
+// DigestSink receives scheduled digests.
+type DigestSink interface {
+	SendDigest(ctx context.Context, report string) error
+}
+
+// LogDigestSink writes digests to the standard logger.
+type LogDigestSink struct{}
+
+// SendDigest implements DigestSink.
+func (LogDigestSink) SendDigest(ctx context.Context, report string) error {
+	log.Printf("[digest]\n%s", report)
+	return nil
+}
+
+// WebhookDigestSink POSTs digests as JSON rendered by Formatter. A nil
+// Formatter sends plain text with both "text" and "content" set, which Slack-
+// and Discord-style incoming webhooks accept.
+type WebhookDigestSink struct {
+	URL       string
+	Formatter NotificationFormatter
+}
+
+func (s WebhookDigestSink) formatter() NotificationFormatter {
+	if s.Formatter == nil {
+		return PlainFormatter{}
+	}
+	return s.Formatter
+}
+
+// SendDigest implements DigestSink.
+func (s WebhookDigestSink) SendDigest(ctx context.Context, report string) error {
+	return postWebhook(ctx, s.URL, s.formatter().FormatDigest(report))
+}
+
+// SendDetection posts a single detection through the same formatter.
+func (s WebhookDigestSink) SendDetection(ctx context.Context, d Detection) error {
+	return postWebhook(ctx, s.URL, s.formatter().FormatDetection(d))
+}
+
+// postWebhook POSTs payload as JSON to url.
+func postWebhook(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("webhook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := newHTTPClient(15 * time.Second).Do(req)
+	if err != nil {
+		return networkError("webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return statusError(resp.StatusCode, "webhook status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// DigestSchedule reads DIGEST_INTERVAL (e.g. "24h" or "7d"; empty disables)
+// and picks the sink: DIGEST_WEBHOOK_URL if set, formatted per
+// DIGEST_WEBHOOK_FORMAT, otherwise the log.
+func DigestSchedule() (time.Duration, DigestSink, bool) {
+	c := cfg()
+	period := c.DigestInterval
+	if period <= 0 {
+		return 0, nil, false
+	}
+	if c.DigestWebhookURL != "" {
+		f, err := NotificationFormatterFor(c.DigestWebhookFormat)
+		if err != nil {
+			log.Printf("[digest] %v; using plain", err)
+			f = PlainFormatter{}
+		}
+		return period, WebhookDigestSink{URL: c.DigestWebhookURL, Formatter: f}, true
+	}
+	return period, LogDigestSink{}, true
+}
+
+// DigestRunner returns a supervisor-compatible loop that generates a digest
+// covering each period and sends it to sink.
+func DigestRunner(period time.Duration, sink DigestSink) func(ctx context.Context) error {
+	return NewPoller("digest", period, func(ctx context.Context) error {
+		report, err := GenerateDigest(period)
+		if err != nil {
+			return fmt.Errorf("generate: %w", err)
+		}
+		if err := sink.SendDigest(ctx, report); err != nil {
+			return fmt.Errorf("send: %w", err)
+		}
+		return nil
+	}, WithMaxBackoff(time.Hour)).Run
+}
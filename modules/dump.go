@@ -0,0 +1,149 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DumpBand is one market-cap band of the dump-alert curve: tokens with a market
+// cap of at least MinCap (USD) count as dumping once their 24h change falls to
+// -DropPct or below.
+type DumpBand struct {
+	MinCap  float64
+	DropPct float64
+}
+
+// Large caps dump at small moves, microcaps need a lot more to stand out from
+// their usual volatility.
+var defaultDumpBands = []DumpBand{
+	{MinCap: 10e9, DropPct: 6},
+	{MinCap: 1e9, DropPct: 8},
+	{MinCap: 100e6, DropPct: 12},
+	{MinCap: 10e6, DropPct: 18},
+	{MinCap: 0, DropPct: 25},
+}
+
+// ParseDumpBands parses "mincap:drop" pairs, e.g. "1e9:8,1e8:12,0:25". The
+// result is sorted by MinCap, largest first; a 0 band is added if missing so
+// every token (including unknown market cap) has a threshold.
+func ParseDumpBands(s string) ([]DumpBand, error) {
+	var bands []DumpBand
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		capStr, dropStr, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("dump band %q: want mincap:drop", part)
+		}
+		minCap, err := strconv.ParseFloat(strings.TrimSpace(capStr), 64)
+		if err != nil || minCap < 0 {
+			return nil, fmt.Errorf("dump band %q: invalid market cap", part)
+		}
+		drop, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(dropStr), "%"), 64)
+		if err != nil || drop <= 0 || drop >= 100 {
+			return nil, fmt.Errorf("dump band %q: drop must be a percentage between 0 and 100", part)
+		}
+		bands = append(bands, DumpBand{MinCap: minCap, DropPct: drop})
+	}
+	if len(bands) == 0 {
+		return nil, fmt.Errorf("no dump bands")
+	}
+	sort.Slice(bands, func(i, j int) bool { return bands[i].MinCap > bands[j].MinCap })
+	if last := bands[len(bands)-1]; last.MinCap > 0 {
+		bands = append(bands, DumpBand{MinCap: 0, DropPct: last.DropPct})
+	}
+	return bands, nil
+}
+
+// DumpBands returns the curve from DUMP_BANDS, or the defaults
+// (>=10B: 6%, >=1B: 8%, >=100M: 12%, >=10M: 18%, smaller: 25%).
+func DumpBands() []DumpBand {
+	return cfg().DumpBands
+}
+
+// DumpThreshold returns the 24h drop (positive percentage) that counts as a
+// dump for a token of the given market cap. Unknown caps (0) use the
+// smallest-cap band.
+func DumpThreshold(marketCap float64, bands []DumpBand) float64 {
+	for _, b := range bands {
+		if marketCap >= b.MinCap {
+			return b.DropPct
+		}
+	}
+	return bands[len(bands)-1].DropPct
+}
+
+// DumpSeverity is the 24h drop relative to the token's threshold: 1 means the
+// drop is exactly at the threshold, 2 twice as deep. 0 for flat or rising tokens.
+func DumpSeverity(md MarketData, bands []DumpBand) float64 {
+	if md.Change24h >= 0 {
+		return 0
+	}
+	return -md.Change24h / DumpThreshold(md.MarketCapUSD, bands)
+}
+
+// RunDumpAlert implements "dumpalert [token...]": checks the tokens (default:
+// the watchlist) against the market-cap-aware dump curve.
+func RunDumpAlert(ctx context.Context, args []string) (string, error) {
+	tokens := args
+	if len(tokens) == 0 {
+		tokens = Watchlist()
+	}
+	if cfg().MockMode {
+		return "Dump alert check: no immediate dump signals detected (mock).", nil
+	}
+
+	data, err := GetMarketDataBatchContext(ctx, tokens, BatchOptions{})
+	if err != nil && len(data) == 0 {
+		return "", fmt.Errorf("dump alert check: %w", err)
+	}
+
+	bands := DumpBands()
+	type hit struct {
+		md       MarketData
+		severity float64
+	}
+	var hits []hit
+	var missing []string
+	for _, t := range tokens {
+		md, ok := data[canonicalSymbol(t)]
+		if !ok {
+			missing = append(missing, strings.ToUpper(strings.TrimLeft(t, "$")))
+			continue
+		}
+		if sev := DumpSeverity(md, bands); sev >= 1 {
+			hits = append(hits, hit{md, sev})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].severity > hits[j].severity })
+
+	var b strings.Builder
+	if len(hits) == 0 {
+		fmt.Fprintf(&b, "Dump alert check: no dump signals across %d tokens.", len(tokens)-len(missing))
+	} else {
+		b.WriteString("Dump alert check:")
+		for _, h := range hits {
+			fmt.Fprintf(&b, "\n⚠️ $%s %.2f%% in 24h (threshold -%.0f%% for a %s cap) • severity %.1fx",
+				strings.ToUpper(h.md.Symbol), h.md.Change24h, DumpThreshold(h.md.MarketCapUSD, bands),
+				formatCap(h.md.MarketCapUSD), math.Round(h.severity*10)/10)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(&b, "\nNo data: %s", strings.Join(missing, ", "))
+	}
+	return b.String(), nil
+}
+
+// formatCap is FormatLargeUSD, or "unknown" when CoinGecko has no market cap.
+func formatCap(v float64) string {
+	if v <= 0 {
+		return "unknown"
+	}
+	return FormatLargeUSD(v)
+}
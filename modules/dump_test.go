@@ -0,0 +1,73 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDumpThreshold(t *testing.T) {
+	tests := []struct {
+		mcap float64
+		want float64
+	}{
+		{50e9, 6},
+		{2e9, 8},
+		{500e6, 12},
+		{20e6, 18},
+		{500e3, 25},
+		{0, 25}, // unknown cap is treated as a microcap
+	}
+	for _, tt := range tests {
+		if got := DumpThreshold(tt.mcap, defaultDumpBands); got != tt.want {
+			t.Errorf("DumpThreshold(%g) = %g, want %g", tt.mcap, got, tt.want)
+		}
+	}
+}
+
+func TestDumpSeverity(t *testing.T) {
+	// the same -15% move is a dump for a large cap but noise for a microcap
+	large := MarketData{Change24h: -15, MarketCapUSD: 5e9}
+	micro := MarketData{Change24h: -15, MarketCapUSD: 2e6}
+	if sev := DumpSeverity(large, defaultDumpBands); sev < 1 {
+		t.Errorf("large cap -15%% severity = %.2f, want >= 1", sev)
+	}
+	if sev := DumpSeverity(micro, defaultDumpBands); sev >= 1 {
+		t.Errorf("microcap -15%% severity = %.2f, want < 1", sev)
+	}
+}
+
+func TestParseDumpBands(t *testing.T) {
+	bands, err := ParseDumpBands("1e8:12%, 1e9:8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DumpBand{{1e9, 8}, {1e8, 12}, {0, 12}}
+	if len(bands) != len(want) {
+		t.Fatalf("ParseDumpBands = %v, want %v", bands, want)
+	}
+	for i := range want {
+		if bands[i] != want[i] {
+			t.Errorf("band %d = %v, want %v", i, bands[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"", "1e9", "x:8", "1e9:0", "1e9:120"} {
+		if _, err := ParseDumpBands(bad); err == nil {
+			t.Errorf("ParseDumpBands(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRunDumpAlertHonorsContext(t *testing.T) {
+	mock := newCoinGeckoMock(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // e.g. the command already timed out
+
+	if _, err := RunDumpAlert(ctx, []string{"btc"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("RunDumpAlert = %v, want context.Canceled", err)
+	}
+	if n := mock.requests.Load(); n != 0 {
+		t.Errorf("%d market requests after cancellation, want 0", n)
+	}
+}
@@ -0,0 +1,58 @@
+package modules
+
+import (
+	"log"
+	"time"
+)
+
+// DetectionMarket is the market state of a detection's token when it was
+// saved, so stored detections can be analyzed later without historical prices.
+type DetectionMarket struct {
+	PriceUSD     float64   `json:"price_usd"`
+	Change24h    float64   `json:"change_24h"`
+	MarketCapUSD float64   `json:"market_cap_usd"`
+	Hype         float64   `json:"hype"`
+	Risk         float64   `json:"risk"`
+	At           time.Time `json:"at"` // when the market data was retrieved
+}
+
+// DetectionEnrichEnabled reports whether DETECTION_ENRICH=true.
+func DetectionEnrichEnabled() bool {
+	return cfg().DetectionEnrich
+}
+
+// EnrichDetections attaches a DetectionMarket to each detection using one
+// batch market fetch. It fails open: detections whose token has no market
+// data (unknown token, API down) are returned unchanged.
+func EnrichDetections(dets []Detection) []Detection {
+	var tokens []string
+	for _, d := range dets {
+		// contract addresses aren't CoinGecko ids; looking them up only burns
+		// rate-limit budget on 404s
+		if d.Token != "" && !IsEVMAddress(d.Token) {
+			tokens = append(tokens, d.Token)
+		}
+	}
+	if len(tokens) == 0 {
+		return dets
+	}
+	data, err := GetMarketDataBatch(tokens)
+	if err != nil {
+		log.Printf("[enrich] market data partly unavailable, saving %d detections as-is where missing: %v", len(dets), summarizeErr(err))
+	}
+	for i, d := range dets {
+		md, ok := data[canonicalSymbol(d.Token)]
+		if !ok {
+			continue
+		}
+		dets[i].Market = &DetectionMarket{
+			PriceUSD:     md.PriceUSD,
+			Change24h:    md.Change24h,
+			MarketCapUSD: md.MarketCapUSD,
+			Hype:         ComputeHypeScore(md),
+			Risk:         ComputeRiskScore(md),
+			At:           md.RetrievedAt,
+		}
+	}
+	return dets
+}
@@ -0,0 +1,32 @@
+package modules
+
+import "testing"
+
+func TestEnrichDetections(t *testing.T) {
+	newCoinGeckoMock(t)
+	dets := EnrichDetections([]Detection{
+		{KOL: "GCR", Token: "BTC", Text: "btc"},
+		{KOL: "GCR", Token: "NOTACOIN", Text: "junk"},
+	})
+	if m := dets[0].Market; m == nil || m.PriceUSD <= 0 || m.At.IsZero() {
+		t.Errorf("BTC market = %+v, want a snapshot", m)
+	}
+	if dets[1].Market != nil {
+		t.Errorf("unknown token got market %+v, want none (fail open)", dets[1].Market)
+	}
+}
+
+func TestAddressTokensSkipMarketLookups(t *testing.T) {
+	m := newCoinGeckoMock(t)
+	const addr = "0x6982508145454ce325ddbe47a25d4ec3d2311933"
+	dets := EnrichDetections([]Detection{{KOL: "GCR", Token: addr, Text: "CA " + addr}})
+	if dets[0].Market != nil {
+		t.Errorf("address got market %+v", dets[0].Market)
+	}
+	if belowMinMarketCap(addr, 1e9) {
+		t.Error("address filtered by market cap, want kept")
+	}
+	if n := m.requests.Load(); n != 0 {
+		t.Errorf("%d CoinGecko requests for a contract address, want 0", n)
+	}
+}
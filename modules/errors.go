@@ -0,0 +1,90 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNotFound is wrapped by errors for 404 responses (unknown token / id).
+	ErrNotFound = errors.New("not found")
+	// ErrNoAIKey is returned by the AI layer when neither GOOGLE_API_KEY nor OPENAI_API_KEY is set.
+	ErrNoAIKey = errors.New("no AI API key configured (set GOOGLE_API_KEY or OPENAI_API_KEY)")
+	// ErrContentBlocked is returned when the AI provider withholds a reply for safety/policy reasons.
+	ErrContentBlocked = errors.New("ai response blocked by provider content filter")
+)
+
+// RetryableError marks an error from the AI or market layers as worth retrying
+// (rate limits, server errors, network failures).
+type RetryableError struct {
+	StatusCode int // HTTP status, 0 for network errors
+	Err        error
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether err is a RetryableError. 429/5xx and network
+// errors are retryable; other 4xx responses (bad request, auth, not found) are not.
+func IsRetryable(err error) bool {
+	var re *RetryableError
+	return errors.As(err, &re)
+}
+
+// statusError builds an error for a non-2xx HTTP response, marking it
+// retryable when the status is 429 or 5xx and wrapping ErrNotFound on 404.
+func statusError(status int, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	if status == http.StatusTooManyRequests || status >= 500 {
+		return &RetryableError{StatusCode: status, Err: err}
+	}
+	if status == http.StatusNotFound {
+		return fmt.Errorf("%w (%w)", err, ErrNotFound)
+	}
+	return err
+}
+
+// networkError wraps a transport-level failure as retryable.
+func networkError(format string, err error) error {
+	return &RetryableError{Err: fmt.Errorf(format, err)}
+}
+
+// UserFacingError maps err to a friendly reply with a suggested action. Unknown
+// errors get a generic message; callers should log err for the details.
+func UserFacingError(err error) string {
+	if err == nil {
+		return ""
+	}
+	var re *RetryableError
+	var ae *AIError
+	var ue *UnknownSymbolError
+	switch {
+	case errors.Is(err, ErrNoAIKey):
+		return "AI features aren't configured on this agent yet. Ask the operator to set GOOGLE_API_KEY or OPENAI_API_KEY."
+	case errors.Is(err, ErrContentBlocked):
+		return "The AI provider declined to answer that (content filter). Try rephrasing your request."
+	case errors.As(err, &ae) && ae.InvalidKey():
+		return "The AI provider rejected this agent's API key. Ask the operator to check GOOGLE_API_KEY / OPENAI_API_KEY."
+	case errors.As(err, &ae) && ae.ModelNotFound():
+		return "The configured AI model isn't available. Ask the operator to check GEMINI_MODEL / OPENAI_MODEL."
+	case errors.As(err, &ue) && len(ue.Suggestions) > 0:
+		return fmt.Sprintf("Couldn't find %q. Did you mean %s?", ue.Input, strings.Join(ue.Suggestions, ", "))
+	case errors.Is(err, ErrNotFound):
+		return "Couldn't find that token. Check the symbol (e.g. BTC, ETH, SOL) and try again."
+	case errors.As(err, &re) && re.StatusCode == http.StatusTooManyRequests:
+		return "The data provider is rate limiting us right now. Please try again in a minute."
+	case errors.As(err, &re) && re.StatusCode >= 500:
+		return "The data provider is having trouble right now. Please try again in a few minutes."
+	case errors.As(err, &re), errors.Is(err, context.DeadlineExceeded):
+		return "Couldn't reach the data provider. Please try again shortly."
+	}
+	return "Something went wrong handling your request. Please try again later."
+}
@@ -0,0 +1,110 @@
+package modules
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// defaultTokenPattern is the stricter cashtag rule ExtractTokens uses for
+// scanner detections: "$" + 2-10 capitals, not glued to a preceding word or
+// "$" (so "$100", "$5k" and prose like "$sol" are skipped). Override with
+// TOKEN_PATTERN.
+const defaultTokenPattern = `(?:^|[^\w$])\$([A-Z]{2,10})\b`
+
+var (
+	// $ + letter first (so "$100" / "$5k" are not cashtags), not glued to a preceding word or "$"
+	cashtagRe = regexp.MustCompile(`(?:^|[^\w$])\$([A-Za-z][A-Za-z0-9]{0,9})\b`)
+	// @ + X/Twitter-style handle (max 15 chars), not preceded by a word char or "." (skips emails)
+	handleRe = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9_]{1,15})\b`)
+	// EVM contract addresses; always extracted as tokens
+	evmAddressRe = regexp.MustCompile(`\b(0x[0-9a-fA-F]{40})\b`)
+)
+
+// extractor is one pattern fed to extractUnique and how to normalize its
+// matches.
+type extractor struct {
+	re   *regexp.Regexp
+	norm func(string) string
+}
+
+// ExtractCashtags returns the $TICKER cashtags in text, any case, uppercased
+// and de-duplicated in order of appearance.
+func ExtractCashtags(text string) []string {
+	return extractUnique(text, extractor{cashtagRe, strings.ToUpper})
+}
+
+// ExtractHandles returns the @handle mentions in text, lowercased (without "@") and de-duplicated.
+func ExtractHandles(text string) []string {
+	return extractUnique(text, extractor{handleRe, strings.ToLower})
+}
+
+// ExtractTokens returns the tokens a post mentions, de-duplicated in order of
+// appearance: cashtags matching TOKEN_PATTERN (by default upper-case only)
+// and EVM contract addresses (lower-cased).
+func ExtractTokens(text string) []string {
+	return extractUnique(text, tickerExtractor(), extractor{evmAddressRe, strings.ToLower})
+}
+
+// IsEVMAddress reports whether token is a 0x contract address rather than a
+// ticker.
+func IsEVMAddress(token string) bool {
+	return len(token) == 42 && evmAddressRe.MatchString(token)
+}
+
+// extractUnique runs every extractor over text and returns the normalized
+// matches, de-duplicated, in order of position in text. A pattern's first
+// capture group is the match if it has one, otherwise the whole match.
+func extractUnique(text string, exs ...extractor) []string {
+	type hit struct {
+		at int
+		v  string
+	}
+	var hits []hit
+	for _, ex := range exs {
+		for _, m := range ex.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[0], m[1]
+			if len(m) > 2 && m[2] >= 0 {
+				start, end = m[2], m[3]
+			}
+			hits = append(hits, hit{start, ex.norm(text[start:end])})
+		}
+	}
+	slices.SortStableFunc(hits, func(a, b hit) int { return cmp.Compare(a.at, b.at) })
+
+	seen := map[string]bool{}
+	out := []string{}
+	for _, h := range hits {
+		if h.v == "" || seen[h.v] {
+			continue
+		}
+		seen[h.v] = true
+		out = append(out, h.v)
+	}
+	return out
+}
+
+var (
+	tokenReMu  sync.Mutex
+	tokenReSrc string
+	tokenRe    *regexp.Regexp
+)
+
+// tickerExtractor returns the TOKEN_PATTERN extractor, compiled once per
+// distinct value and falling back to the default when it doesn't compile
+// (ValidateConfig reports that).
+func tickerExtractor() extractor {
+	src := cfg().TokenPattern
+	tokenReMu.Lock()
+	defer tokenReMu.Unlock()
+	if tokenRe == nil || src != tokenReSrc {
+		re, err := regexp.Compile(src)
+		if err != nil {
+			re = regexp.MustCompile(defaultTokenPattern)
+		}
+		tokenReSrc, tokenRe = src, re
+	}
+	return extractor{tokenRe, func(s string) string { return strings.TrimPrefix(s, "$") }}
+}
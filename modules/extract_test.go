@@ -0,0 +1,62 @@
+package modules
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractTokens(t *testing.T) {
+	const addr = "0x6982508145454Ce325dDbE47a25d4ec3d2311933"
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"aping $PEPE, more $PEPE", []string{"PEPE"}},
+		{"$sol and $Wif are lowercase, $X too short", []string{}},
+		{"CA " + addr + " is $PEPE, again " + strings.ToLower(addr), []string{strings.ToLower(addr), "PEPE"}},
+		{"$BTC to $100k, $ETH next", []string{"BTC", "ETH"}},
+		{"not an address: 0x1234 or " + addr + "ff", []string{}},
+	}
+	for _, tt := range tests {
+		if got := ExtractTokens(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("ExtractTokens(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	t.Setenv("TOKEN_PATTERN", `#([A-Z]{3,5})\b`)
+	if got := ExtractTokens("#DOGE over $PEPE"); !slices.Equal(got, []string{"DOGE"}) {
+		t.Errorf("custom TOKEN_PATTERN: got %q, want [DOGE]", got)
+	}
+}
+
+func TestExtractCashtags(t *testing.T) {
+	const addr = "0x6982508145454Ce325dDbE47a25d4ec3d2311933"
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"$sol, $SOL and $Sol", []string{"SOL"}},
+		{"$wif then $BONK1", []string{"WIF", "BONK1"}},
+		{"$100, $5k, US$20 and a$b", []string{}},
+		{"CA " + addr + " is $PEPE", []string{"PEPE"}},
+	}
+	for _, tt := range tests {
+		if got := ExtractCashtags(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("ExtractCashtags(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	// TOKEN_PATTERN only governs ExtractTokens
+	t.Setenv("TOKEN_PATTERN", `#([A-Z]{3,5})\b`)
+	if got := ExtractCashtags("#DOGE over $pepe"); !slices.Equal(got, []string{"PEPE"}) {
+		t.Errorf("ExtractCashtags with TOKEN_PATTERN set = %q, want [PEPE]", got)
+	}
+}
+
+func TestExtractHandles(t *testing.T) {
+	got := ExtractHandles("cc @CryptoKaleo, @cryptokaleo and @GCRClassic; mail me at a@b.com")
+	if want := []string{"cryptokaleo", "gcrclassic"}; !slices.Equal(got, want) {
+		t.Errorf("ExtractHandles = %q, want %q", got, want)
+	}
+}
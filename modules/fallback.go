@@ -0,0 +1,101 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// FallbackMockOnError reports whether FALLBACK_MOCK_ON_ERROR is enabled: when
+// the provider is down, handlers answer with last-known values labeled as such
+// instead of "(data unavailable)".
+func FallbackMockOnError() bool {
+	return cfg().FallbackMockOnError
+}
+
+// isOutage reports whether err means the provider is down or unreachable
+// (rate limits, 5xx, network failures) rather than that the request was bad,
+// e.g. an unknown symbol. Only outages fall back to last-known data.
+func isOutage(err error) bool {
+	var ne net.Error
+	return IsRetryable(err) || errors.As(err, &ne)
+}
+
+// lastKnownMarketData returns the cached entry for symbol even if its TTL has
+// expired, as long as it is no older than MaxStale.
+func lastKnownMarketData(symbol string) (MarketData, bool) {
+	sym := canonicalSymbol(symbol)
+	cgCacheMu.Lock()
+	defer cgCacheMu.Unlock()
+	e, ok := cgCache[sym]
+	if !ok || time.Since(e.data.RetrievedAt) > MaxStale() {
+		return MarketData{}, false
+	}
+	return e.data, true
+}
+
+// marketDataOrFallback is GetMarketData plus the FALLBACK_MOCK_ON_ERROR path.
+// label is empty for live data, otherwise a "[stale] ..." note that must be
+// shown to the user. Errors that aren't outages (unknown symbol) and outages
+// with no recent cached value are returned, never scored as placeholder zeros.
+func marketDataOrFallback(ctx context.Context, symbol string) (md MarketData, label string, err error) {
+	md, stale, err := GetMarketDataStaleContext(ctx, symbol)
+	if err == nil {
+		if stale {
+			label = staleLabel(md.RetrievedAt)
+		}
+		return md, label, nil
+	}
+	if !FallbackMockOnError() || !isOutage(err) {
+		return md, "", err
+	}
+	if last, ok := lastKnownMarketData(symbol); ok {
+		return last, staleLabel(last.RetrievedAt), nil
+	}
+	return MarketData{}, "", fmt.Errorf("live data unavailable and nothing cached in the last %s: %w", formatAge(MaxStale()), err)
+}
+
+// staleLabel renders "[stale] as of 5m ago".
+func staleLabel(at time.Time) string {
+	return fmt.Sprintf("[stale] as of %s ago", formatAge(time.Since(at)))
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// withLabel prefixes reply with the fallback label, if any.
+func withLabel(label, reply string) string {
+	if label == "" {
+		return reply
+	}
+	return label + "\n" + reply
+}
+
+// fallbackReply builds a one-line "[stale] price: $X as of 5m ago" reply from
+// the last known value of field, or says the field is unavailable. ok is false
+// when FALLBACK_MOCK_ON_ERROR is off or err isn't an outage, and the caller
+// should return err.
+func fallbackReply(symbol, field string, err error, value func(MarketData) string) (reply string, ok bool) {
+	if !FallbackMockOnError() || !isOutage(err) {
+		return "", false
+	}
+	if last, found := lastKnownMarketData(symbol); found {
+		return staleValueReply(field, value(last), last.RetrievedAt), true
+	}
+	return fmt.Sprintf("[mock] %s: unavailable (live data down)", field), true
+}
+
+// staleValueReply renders "[stale] price: $X as of 5m ago".
+func staleValueReply(field, value string, at time.Time) string {
+	return fmt.Sprintf("[stale] %s: %s as of %s ago", field, value, formatAge(time.Since(at)))
+}
@@ -0,0 +1,122 @@
+package modules
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// priceSigFigs is how many significant figures sub-dollar prices keep.
+const priceSigFigs = 4
+
+// FormatPrice renders a USD price with precision that fits its magnitude and
+// thousands separators: "$43,012.55", "$12.3456", "$0.0001234".
+func FormatPrice(p float64) string {
+	sign := ""
+	if p < 0 {
+		sign, p = "-", -p
+	}
+	var decimals int
+	switch {
+	case p == 0:
+		decimals = 2
+	case p >= 100:
+		decimals = 2
+	case p >= 1:
+		decimals = 4
+	default:
+		// keep priceSigFigs significant digits, e.g. 0.000001234 -> 9 decimals
+		decimals = min(priceSigFigs-1-int(math.Floor(math.Log10(p))), 12)
+	}
+	return sign + "$" + groupThousands(strconv.FormatFloat(p, 'f', decimals, 64))
+}
+
+// groupThousands inserts commas into the integer part of a plain decimal string.
+func groupThousands(s string) string {
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	if len(intPart) <= 3 {
+		return s
+	}
+	var b strings.Builder
+	lead := len(intPart) % 3
+	if lead > 0 {
+		b.WriteString(intPart[:lead])
+	}
+	for i := lead; i < len(intPart); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(intPart[i : i+3])
+	}
+	if hasFrac {
+		b.WriteByte('.')
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// FormatLargeUSD renders volumes and market caps compactly: "$1.43T",
+// "$892.4M", "$12.3K". With REPLY_RAW_NUMBERS=true it returns the full figure
+// ("$1,430,000,000,000") for users who need the exact value.
+func FormatLargeUSD(v float64) string {
+	sign := ""
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	if cfg().ReplyRawNumbers {
+		return sign + "$" + groupThousands(strconv.FormatFloat(v, 'f', 0, 64))
+	}
+	units := []struct {
+		size   float64
+		suffix string
+	}{{1e12, "T"}, {1e9, "B"}, {1e6, "M"}, {1e3, "K"}}
+	for _, u := range units {
+		// 999.96M would round to "1000.0M"; show it as "1.00B" instead
+		if v >= u.size*0.99995 {
+			n := v / u.size
+			decimals := 1
+			if n < 10 {
+				decimals = 2
+			}
+			return sign + "$" + strconv.FormatFloat(n, 'f', decimals, 64) + u.suffix
+		}
+	}
+	return sign + "$" + strconv.FormatFloat(v, 'f', 0, 64)
+}
+
+// sparkBlocks are the sparkline levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a one-line chart of at most width characters,
+// averaging neighbouring points when there are more values than width. A
+// flat series renders at mid height; fewer than two values render as "".
+func Sparkline(values []float64, width int) string {
+	if len(values) < 2 || width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		buckets := make([]float64, width)
+		for i := range buckets {
+			lo, hi := i*len(values)/width, (i+1)*len(values)/width
+			sum := 0.0
+			for _, v := range values[lo:hi] {
+				sum += v
+			}
+			buckets[i] = sum / float64(hi-lo)
+		}
+		values = buckets
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := len(sparkBlocks) / 2
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package modules
+
+import "testing"
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{43012.5512, "$43,012.55"},
+		{1234567.891, "$1,234,567.89"},
+		{150.126, "$150.13"},
+		{1.0001, "$1.0001"},
+		{0.5, "$0.5000"},
+		{0.000001234, "$0.000001234"},
+		{0, "$0.00"},
+		{-2.5, "-$2.5000"},
+	}
+	for _, tt := range tests {
+		if got := FormatPrice(tt.in); got != tt.want {
+			t.Errorf("FormatPrice(%g) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatLargeUSD(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{1.43e12, "$1.43T"},
+		{892.4e6, "$892.4M"},
+		{12.3e3, "$12.3K"},
+		{999.96e6, "$1.00B"},
+		{950, "$950"},
+		{0, "$0"},
+	}
+	for _, tt := range tests {
+		if got := FormatLargeUSD(tt.in); got != tt.want {
+			t.Errorf("FormatLargeUSD(%g) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	t.Setenv("REPLY_RAW_NUMBERS", "true")
+	if got := FormatLargeUSD(1.43e12); got != "$1,430,000,000,000" {
+		t.Errorf("FormatLargeUSD raw = %q", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]float64{1, 2, 3, 4, 5, 6, 7, 8}, 8); got != "▁▂▃▄▅▆▇█" {
+		t.Errorf("rising = %q", got)
+	}
+	if got := Sparkline([]float64{5, 5, 5}, 8); got != "▅▅▅" {
+		t.Errorf("flat = %q", got)
+	}
+	if got := []rune(Sparkline(make([]float64, 168), 24)); len(got) != 24 {
+		t.Errorf("168 points at width 24 = %d chars", len(got))
+	}
+	if got := Sparkline([]float64{1}, 8); got != "" {
+		t.Errorf("single point = %q, want empty", got)
+	}
+}
@@ -2,121 +2,233 @@ package modules
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 )
 
-// ForwardToOpenAI sends prompt to Google Gemini (preferred) or OpenAI (fallback).
-// Important fix: always normalize GOOGLE_MODEL by STRIPPING leading "models/" if present,
-// then build endpoint: /v1beta/models/{modelName}:generateContent
+// ForwardToOpenAI sends prompt to Google Gemini (preferred) or OpenAI (fallback),
+// each with its own configured model (see AIModel).
 func ForwardToOpenAI(prompt string) (string, error) {
-	prompt = strings.TrimSpace(prompt)
-	if prompt == "" {
-		return "", fmt.Errorf("empty prompt")
+	return ForwardWithOptions(prompt, AIOptions{})
+}
+
+// AIOptions tunes a single AI call.
+type AIOptions struct {
+	// SystemPrompt is the persona/instruction sent as the system role (OpenAI) or
+	// systemInstruction (Gemini). Empty falls back to the SYSTEM_PROMPT env.
+	SystemPrompt string
+	// JSONMode asks the model for a JSON object (Gemini responseMimeType / OpenAI response_format).
+	JSONMode bool
+	// MaxTokens caps the reply length; 0 uses the default (256).
+	MaxTokens int
+	// Temperature sets sampling randomness; nil uses the default (0.2).
+	Temperature *float64
+	// NoCache forces a fresh reply instead of a cached one (see AI_CACHE_TTL).
+	NoCache bool
+}
+
+const (
+	defaultAIMaxTokens       = 256
+	defaultAITemperature     = 0.2
+	defaultAITimeoutBase     = 12 * time.Second
+	defaultAITimeoutPerToken = 30 * time.Millisecond
+)
+
+// SummaryAIOptions are the generation settings for detection summaries: short
+// and deterministic. SUMMARY_MAX_TOKENS (default 128) and SUMMARY_TEMPERATURE
+// (default 0.1) override them.
+func SummaryAIOptions() AIOptions {
+	c := cfg()
+	temperature := c.SummaryTemperature
+	return AIOptions{MaxTokens: c.SummaryMaxTokens, Temperature: &temperature}
+}
+
+// ChatAIOptions are the generation settings for the ai command: room for a
+// fuller answer. AI_MAX_TOKENS (default 512) and AI_TEMPERATURE (default 0.4)
+// override them.
+func ChatAIOptions() AIOptions {
+	c := cfg()
+	temperature := c.ChatTemperature
+	return AIOptions{MaxTokens: c.ChatMaxTokens, Temperature: &temperature}
+}
+
+// ForwardWithOptions is ForwardToOpenAI with per-call options.
+func ForwardWithOptions(prompt string, opts AIOptions) (string, error) {
+	return ForwardWithOptionsContext(context.Background(), prompt, opts)
+}
+
+// ForwardWithOptionsContext is ForwardWithOptions that gives up when ctx is done.
+func ForwardWithOptionsContext(ctx context.Context, prompt string, opts AIOptions) (string, error) {
+	req, err := newAIRequest(prompt, opts)
+	if err != nil {
+		return "", err
 	}
 
-	googleKey := strings.TrimSpace(os.Getenv("GOOGLE_API_KEY"))
-	openaiKey := strings.TrimSpace(os.Getenv("OPENAI_API_KEY"))
+	c := cfg()
+	googleKey, openaiKey := c.GoogleAPIKey, c.OpenAIAPIKey
+	if googleKey == "" && openaiKey == "" {
+		return "", ErrNoAIKey
+	}
 
-	shortKey := func(k string) string {
-		if k == "" {
-			return ""
-		}
-		if len(k) <= 8 {
-			return k
+	resp, _, err := cachedAI(ctx, req, opts.NoCache, func() (string, error) {
+		// Prefer Google Gemini if key present
+		if googleKey != "" {
+			return callGemini(ctx, googleKey, req)
 		}
-		return k[:8] + "..."
+		// fallback: OpenAI
+		return callOpenAI(ctx, openaiKey, req)
+	})
+	return resp, err
+}
+
+// Default models per provider (GEMINI_MODEL, OPENAI_MODEL).
+const (
+	defaultGeminiModel = "gemini-2.5-flash"
+	defaultOpenAIModel = "gpt-4o-mini"
+)
+
+// AIModel returns the configured model for provider ("google" or "openai").
+func AIModel(provider string) string {
+	c := cfg()
+	if provider == "openai" {
+		return c.OpenAIModel
 	}
+	return c.GeminiModel
+}
 
-	// Prefer Google Gemini if key present
-	if googleKey != "" {
-		modelEnv := strings.TrimSpace(os.Getenv("GOOGLE_MODEL"))
-		if modelEnv == "" {
-			modelEnv = "gemini-2.5-flash"
-		}
+// Bounds for aiTimeout whatever AI_TIMEOUT_BASE / AI_TIMEOUT_PER_TOKEN say.
+const (
+	minAITimeout = 10 * time.Second
+	maxAITimeout = 3 * time.Minute
+)
 
-		// **NORMALIZE**: strip any leading "models/" if present
-		if strings.HasPrefix(modelEnv, "models/") {
-			modelEnv = strings.TrimPrefix(modelEnv, "models/")
-		}
+// aiTimeout is the HTTP timeout for a call generating up to maxTokens:
+// AI_TIMEOUT_BASE (default 12s) plus AI_TIMEOUT_PER_TOKEN (default 30ms) per
+// token, clamped to [10s, 3m]. 256 tokens get ~20s, 2000 tokens ~72s.
+func aiTimeout(maxTokens int) time.Duration {
+	c := cfg()
+	d := c.AITimeoutBase + time.Duration(maxTokens)*c.AITimeoutPerToken
+	return min(max(d, minAITimeout), maxAITimeout)
+}
 
-		// Now modelEnv is plain model name (e.g. "gemini-2.5-flash")
-		// Construct endpoint: /v1beta/models/{modelEnv}:generateContent
-		url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", modelEnv, googleKey)
-
-		// Build request body per Gemini docs
-		reqBody := map[string]interface{}{
-			"contents": []interface{}{
-				map[string]interface{}{
-					"parts": []interface{}{
-						map[string]interface{}{"text": prompt},
-					},
+// aiRequest is one AI call with the options resolved against the defaults.
+type aiRequest struct {
+	prompt       string
+	systemPrompt string
+	maxTokens    int
+	temperature  float64
+	jsonMode     bool
+}
+
+func newAIRequest(prompt string, opts AIOptions) (aiRequest, error) {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return aiRequest{}, fmt.Errorf("empty prompt")
+	}
+	req := aiRequest{
+		prompt:       prompt,
+		systemPrompt: strings.TrimSpace(opts.SystemPrompt),
+		maxTokens:    defaultAIMaxTokens,
+		temperature:  defaultAITemperature,
+		jsonMode:     opts.JSONMode,
+	}
+	if req.systemPrompt == "" {
+		req.systemPrompt = cfg().SystemPrompt
+	}
+	if opts.MaxTokens > 0 {
+		req.maxTokens = opts.MaxTokens
+	}
+	if opts.Temperature != nil {
+		req.temperature = *opts.Temperature
+	}
+	return req, nil
+}
+
+func shortKey(k string) string {
+	if k == "" {
+		return ""
+	}
+	if len(k) <= 8 {
+		return k
+	}
+	return k[:8] + "..."
+}
+
+// callGemini sends req to Google Gemini and extracts the reply text.
+func callGemini(ctx context.Context, googleKey string, req aiRequest) (string, error) {
+	// plain model name (e.g. "gemini-2.5-flash"); endpoint: /v1beta/models/{model}:generateContent
+	model := AIModel("google")
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", model, googleKey)
+
+	// Build request body per Gemini docs
+	reqBody := map[string]interface{}{
+		"contents": []interface{}{
+			map[string]interface{}{
+				"parts": []interface{}{
+					map[string]interface{}{"text": req.prompt},
 				},
 			},
-			"generationConfig": map[string]interface{}{
-				"maxOutputTokens": 256,
-				"temperature":     0.2,
+		},
+		"generationConfig": map[string]interface{}{
+			"maxOutputTokens": req.maxTokens,
+			"temperature":     req.temperature,
+		},
+	}
+	if req.jsonMode {
+		reqBody["generationConfig"].(map[string]interface{})["responseMimeType"] = "application/json"
+	}
+	if req.systemPrompt != "" {
+		reqBody["systemInstruction"] = map[string]interface{}{
+			"parts": []interface{}{
+				map[string]interface{}{"text": req.systemPrompt},
 			},
 		}
-		b, _ := json.Marshal(reqBody)
-
-		log.Printf("ForwardToOpenAI: Google request -> model=%s key_preview=%s prompt_len=%d",
-			modelEnv, shortKey(googleKey), len(prompt))
+	}
+	b, _ := json.Marshal(reqBody)
 
-		req, err := http.NewRequest("POST", url, bytes.NewReader(b))
-		if err != nil {
-			return "", fmt.Errorf("failed build request: %w", err)
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("x-goog-api-key", googleKey)
+	log.Printf("ForwardToOpenAI: Google request -> model=%s key_preview=%s prompt_len=%d",
+		model, shortKey(googleKey), len(req.prompt))
 
-		client := &http.Client{Timeout: 25 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			return "", fmt.Errorf("google http err: %w", err)
-		}
-		defer resp.Body.Close()
-		respBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 200*1024))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(b))
+	if err != nil {
+		return "", fmt.Errorf("failed build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", googleKey)
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			log.Printf("ForwardToOpenAI: Google response status=%d body_preview=%s", resp.StatusCode, sanitizeForLog(string(respBytes)))
-			return "", fmt.Errorf("google api error: status %d: %s", resp.StatusCode, sanitizeForLog(string(respBytes)))
-		}
+	client := newHTTPClient(aiTimeout(req.maxTokens))
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", networkError("google http err: %w", err)
+	}
+	defer resp.Body.Close()
+	respBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 200*1024))
 
-		// parse response and extract candidate text
-		var parsed map[string]interface{}
-		if err := json.Unmarshal(respBytes, &parsed); err != nil {
-			log.Printf("ForwardToOpenAI: google parse json err: %v", err)
-			return strings.TrimSpace(string(respBytes)), nil
-		}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("ForwardToOpenAI: Google response status=%d body_preview=%s", resp.StatusCode, sanitizeForLog(string(respBytes)))
+		return "", parseAIError("google", resp.StatusCode, respBytes)
+	}
 
-		// Typical path: candidates[0].content.parts[0].text
-		if cands, ok := parsed["candidates"].([]interface{}); ok && len(cands) > 0 {
-			if cand0, ok := cands[0].(map[string]interface{}); ok {
-				if content, ok := cand0["content"].(map[string]interface{}); ok {
-					if parts, ok := content["parts"].([]interface{}); ok && len(parts) > 0 {
-						if p0, ok := parts[0].(map[string]interface{}); ok {
-							if txt, ok := p0["text"].(string); ok && txt != "" {
-								return strings.TrimSpace(txt), nil
-							}
-						}
-					}
-				}
-				if txt, ok := cand0["text"].(string); ok && txt != "" {
-					return strings.TrimSpace(txt), nil
-				}
-			}
-		}
+	// parse response and extract candidate text
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(respBytes, &parsed); err != nil {
+		log.Printf("ForwardToOpenAI: google parse json err: %v", err)
+		return strings.TrimSpace(string(respBytes)), nil
+	}
+	if err := geminiBlocked(parsed); err != nil {
+		return "", err
+	}
 
-		// fallback path: output.content.parts[0].text
-		if out, ok := parsed["output"].(map[string]interface{}); ok {
-			if content, ok := out["content"].(map[string]interface{}); ok {
+	// Typical path: candidates[0].content.parts[0].text
+	if cands, ok := parsed["candidates"].([]interface{}); ok && len(cands) > 0 {
+		if cand0, ok := cands[0].(map[string]interface{}); ok {
+			if content, ok := cand0["content"].(map[string]interface{}); ok {
 				if parts, ok := content["parts"].([]interface{}); ok && len(parts) > 0 {
 					if p0, ok := parts[0].(map[string]interface{}); ok {
 						if txt, ok := p0["text"].(string); ok && txt != "" {
@@ -125,62 +237,88 @@ func ForwardToOpenAI(prompt string) (string, error) {
 					}
 				}
 			}
+			if txt, ok := cand0["text"].(string); ok && txt != "" {
+				return strings.TrimSpace(txt), nil
+			}
 		}
-
-		// final fallback: first string leaf
-		if s := findFirstString(parsed); s != "" {
-			return strings.TrimSpace(s), nil
-		}
-		return strings.TrimSpace(string(respBytes)), nil
 	}
 
-	// fallback: OpenAI
-	if openaiKey != "" {
-		reqURL := "https://api.openai.com/v1/chat/completions"
-		reqBodyMap := map[string]interface{}{
-			"model": "gpt-4o-mini",
-			"messages": []map[string]interface{}{
-				{"role": "user", "content": prompt},
-			},
-			"max_tokens": 256,
-			"temperature": 0.2,
-		}
-		reqB, _ := json.Marshal(reqBodyMap)
-		req, _ := http.NewRequest("POST", reqURL, bytes.NewReader(reqB))
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+openaiKey)
-
-		client := &http.Client{Timeout: 20 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			return "", fmt.Errorf("openai http err: %w", err)
-		}
-		defer resp.Body.Close()
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 200*1024))
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			log.Printf("ForwardToOpenAI: OpenAI response status=%d body_preview=%s", resp.StatusCode, sanitizeForLog(string(b)))
-			return "", fmt.Errorf("openai api error: status %d: %s", resp.StatusCode, sanitizeForLog(string(b)))
-		}
-		var parsed map[string]interface{}
-		if err := json.Unmarshal(b, &parsed); err != nil {
-			return strings.TrimSpace(string(b)), nil
-		}
-		if choices, ok := parsed["choices"].([]interface{}); ok && len(choices) > 0 {
-			if ch0, ok := choices[0].(map[string]interface{}); ok {
-				if msg, ok := ch0["message"].(map[string]interface{}); ok {
-					if content, ok := msg["content"].(string); ok {
-						return strings.TrimSpace(content), nil
+	// fallback path: output.content.parts[0].text
+	if out, ok := parsed["output"].(map[string]interface{}); ok {
+		if content, ok := out["content"].(map[string]interface{}); ok {
+			if parts, ok := content["parts"].([]interface{}); ok && len(parts) > 0 {
+				if p0, ok := parts[0].(map[string]interface{}); ok {
+					if txt, ok := p0["text"].(string); ok && txt != "" {
+						return strings.TrimSpace(txt), nil
 					}
 				}
-				if txt, ok := ch0["text"].(string); ok {
-					return strings.TrimSpace(txt), nil
-				}
 			}
 		}
-		return strings.TrimSpace(string(b)), nil
 	}
 
-	return "", fmt.Errorf("no AI API key configured (set GOOGLE_API_KEY or OPENAI_API_KEY)")
+	// final fallback: first string leaf
+	if s := findFirstString(parsed); s != "" {
+		return strings.TrimSpace(s), nil
+	}
+	return strings.TrimSpace(string(respBytes)), nil
+}
+
+// callOpenAI sends req to the OpenAI chat completions API.
+func callOpenAI(ctx context.Context, openaiKey string, req aiRequest) (string, error) {
+	reqURL := "https://api.openai.com/v1/chat/completions"
+	messages := []map[string]interface{}{}
+	if req.systemPrompt != "" {
+		messages = append(messages, map[string]interface{}{"role": "system", "content": req.systemPrompt})
+	}
+	messages = append(messages, map[string]interface{}{"role": "user", "content": req.prompt})
+	reqBodyMap := map[string]interface{}{
+		"model":    AIModel("openai"),
+		"messages": messages,
+		"max_tokens": req.maxTokens,
+		"temperature": req.temperature,
+	}
+	if req.jsonMode {
+		reqBodyMap["response_format"] = map[string]string{"type": "json_object"}
+	}
+	reqB, _ := json.Marshal(reqBodyMap)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(reqB))
+	if err != nil {
+		return "", fmt.Errorf("failed build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+openaiKey)
+
+	client := newHTTPClient(aiTimeout(req.maxTokens))
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", networkError("openai http err: %w", err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 200*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("ForwardToOpenAI: OpenAI response status=%d body_preview=%s", resp.StatusCode, sanitizeForLog(string(b)))
+		return "", parseAIError("openai", resp.StatusCode, b)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		return strings.TrimSpace(string(b)), nil
+	}
+	if err := openAIBlocked(parsed); err != nil {
+		return "", err
+	}
+	if choices, ok := parsed["choices"].([]interface{}); ok && len(choices) > 0 {
+		if ch0, ok := choices[0].(map[string]interface{}); ok {
+			if msg, ok := ch0["message"].(map[string]interface{}); ok {
+				if content, ok := msg["content"].(string); ok {
+					return strings.TrimSpace(content), nil
+				}
+			}
+			if txt, ok := ch0["text"].(string); ok {
+				return strings.TrimSpace(txt), nil
+			}
+		}
+	}
+	return strings.TrimSpace(string(b)), nil
 }
 
 // helper functions
@@ -194,22 +332,46 @@ func sanitizeForLog(s string) string {
 	return s[:800] + "...[truncated]"
 }
 
+// Bounds for findFirstString so a deeply nested or huge provider payload
+// can't make the fallback parser burn stack or CPU.
+const (
+	findStringMaxDepth = 32
+	findStringMaxNodes = 10000
+)
+
+// findFirstString returns the first non-empty string leaf in v, or "" if none
+// is found within findStringMaxDepth levels and findStringMaxNodes values.
 func findFirstString(v interface{}) string {
-	switch t := v.(type) {
-	case string:
-		return t
-	case []interface{}:
-		for _, e := range t {
-			if s := findFirstString(e); s != "" {
-				return s
-			}
+	nodes := 0
+	var walk func(v interface{}, depth int) string
+	walk = func(v interface{}, depth int) string {
+		nodes++
+		if depth > findStringMaxDepth || nodes > findStringMaxNodes {
+			return ""
 		}
-	case map[string]interface{}:
-		for _, val := range t {
-			if s := findFirstString(val); s != "" {
-				return s
+		switch t := v.(type) {
+		case string:
+			return t
+		case []interface{}:
+			for _, e := range t {
+				if s := walk(e, depth+1); s != "" {
+					return s
+				}
+				if nodes > findStringMaxNodes {
+					return ""
+				}
+			}
+		case map[string]interface{}:
+			for _, val := range t {
+				if s := walk(val, depth+1); s != "" {
+					return s
+				}
+				if nodes > findStringMaxNodes {
+					return ""
+				}
 			}
 		}
+		return ""
 	}
-	return ""
+	return walk(v, 0)
 }
@@ -0,0 +1,29 @@
+package modules
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// sharedTransport is used by every outbound client in modules (CoinGecko, AI)
+// so proxy settings and connection pooling apply everywhere.
+var sharedTransport = func() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxyFromEnv
+	return t
+}()
+
+// proxyFromEnv routes requests through PROXY_URL when set, otherwise honors
+// HTTP_PROXY / HTTPS_PROXY / NO_PROXY. Read per request so .env loaded at startup applies.
+func proxyFromEnv(req *http.Request) (*url.URL, error) {
+	if p := cfg().ProxyURL; p != "" {
+		return url.Parse(p)
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// newHTTPClient returns a client with the given timeout on the shared transport.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: sharedTransport}
+}
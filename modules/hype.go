@@ -1,11 +1,13 @@
 package modules
 
 import (
+	"context"
 	"strings"
+	"time"
 )
 
 // RunHype is the public entry used by the agent to get a hype reply.
-func RunHype(args []string) (string, error) {
+func RunHype(ctx context.Context, args []string) (string, error) {
 	if len(args) == 0 {
 		return "Usage: hype [token]. Example: hype sol", nil
 	}
@@ -14,6 +16,13 @@ func RunHype(args []string) (string, error) {
 		return "Usage: hype [token]. Example: hype sol", nil
 	}
 
-	reply := BuildHypeReply(token)
+	reply, err := BuildHypeReply(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	// surface KOL attention spikes when the detection log has recent mentions
+	if cur, _, ratio := ComputeMentionVelocity(token, time.Hour); cur > 0 {
+		reply += "\n" + formatVelocity(cur, ratio)
+	}
 	return reply, nil
 }
@@ -0,0 +1,209 @@
+package modules
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotationPolicy decides when the detection log is rotated and how many old
+// files are kept. A zero MaxBytes or MaxAge disables that trigger.
+type RotationPolicy struct {
+	MaxBytes int64
+	MaxAge   time.Duration
+	Keep     int
+	Compress bool
+}
+
+const (
+	defaultDetectionLogMaxMB = 50
+	defaultDetectionLogKeep  = 5
+	detectionRotateInterval  = 10 * time.Minute
+
+	// rotationStamp is the UTC time suffix RotateLog gives rotated files.
+	rotationStamp = "20060102T150405Z"
+)
+
+// detectionLogMu serializes appends with rotation so no record lands in a
+// file that is being renamed away.
+var detectionLogMu sync.Mutex
+
+// DetectionRotationPolicy reads the policy from DETECTION_LOG_MAX_MB (default
+// 50), DETECTION_LOG_MAX_AGE (e.g. "168h", default off), DETECTION_LOG_KEEP
+// (default 5) and DETECTION_LOG_COMPRESS (gzip rotated files, default false).
+func DetectionRotationPolicy() RotationPolicy {
+	return cfg().DetectionRotation
+}
+
+// RotateDetectionLog rotates the detection log if the policy says it is due and
+// returns the rotated file's path ("" if nothing was rotated). LoadDetections
+// still reads rotated files until they are pruned beyond Keep.
+func RotateDetectionLog() (string, error) {
+	return RotateLog(DetectionLogPath(), DetectionRotationPolicy(), TimeNowUTC())
+}
+
+// RotateLog renames filename to a timestamped sibling when it is larger than
+// p.MaxBytes or its first record is older than p.MaxAge, gzips it if asked,
+// and prunes rotated files beyond p.Keep.
+func RotateLog(filename string, p RotationPolicy, now time.Time) (string, error) {
+	detectionLogMu.Lock()
+	defer detectionLogMu.Unlock()
+
+	fi, err := os.Stat(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if fi.Size() == 0 || !rotationDue(filename, fi.Size(), p, now) {
+		return "", nil
+	}
+
+	base, ext := rotationParts(filename)
+	rotated := fmt.Sprintf("%s-%s%s", base, now.UTC().Format(rotationStamp), ext)
+	if err := os.Rename(filename, rotated); err != nil {
+		return "", fmt.Errorf("rotate %s: %w", filename, err)
+	}
+	if p.Compress {
+		gz, err := gzipFile(rotated)
+		if err != nil {
+			log.Printf("[rotation] gzip %s: %v (kept uncompressed)", rotated, err)
+		} else {
+			rotated = gz
+		}
+	}
+	if err := pruneRotated(filename, p.Keep); err != nil {
+		log.Printf("[rotation] prune %s: %v", filename, err)
+	}
+	return rotated, nil
+}
+
+func rotationDue(filename string, size int64, p RotationPolicy, now time.Time) bool {
+	if p.MaxBytes > 0 && size > p.MaxBytes {
+		return true
+	}
+	if p.MaxAge > 0 {
+		if first, ok := firstDetectionTime(filename); ok && now.Sub(first) > p.MaxAge {
+			return true
+		}
+	}
+	return false
+}
+
+// firstDetectionTime returns the timestamp of the first parseable record.
+func firstDetectionTime(filename string) (time.Time, bool) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var d Detection
+		if err := json.Unmarshal(sc.Bytes(), &d); err == nil && !d.Timestamp.IsZero() {
+			return d.Timestamp, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// rotationParts splits "dir/detections.jsonl" into "dir/detections" and ".jsonl".
+func rotationParts(filename string) (base, ext string) {
+	ext = filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext), ext
+}
+
+// rotatedFiles lists rotated copies of filename, oldest first (the timestamp
+// suffix sorts lexically).
+func rotatedFiles(filename string) ([]string, error) {
+	base, ext := rotationParts(filename)
+	matches, err := filepath.Glob(base + "-*" + ext + "*")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// rotationTime parses the time RotateLog stamped on rotated, a rotated copy
+// of filename ("detections-20060102T150405Z.jsonl[.gz]").
+func rotationTime(filename, rotated string) (time.Time, bool) {
+	base, ext := rotationParts(filename)
+	stamp := strings.TrimSuffix(strings.TrimSuffix(rotated, ".gz"), ext)
+	stamp, ok := strings.CutPrefix(stamp, base+"-")
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(rotationStamp, stamp)
+	return t, err == nil
+}
+
+func pruneRotated(filename string, keep int) error {
+	files, err := rotatedFiles(filename)
+	if err != nil || len(files) <= keep {
+		return err
+	}
+	for _, f := range files[:len(files)-keep] {
+		if err := os.Remove(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gzipFile(path string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	gzPath := path + ".gz"
+	out, err := os.OpenFile(gzPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		os.Remove(gzPath)
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		os.Remove(gzPath)
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(gzPath)
+		return "", err
+	}
+	in.Close()
+	return gzPath, os.Remove(path)
+}
+
+// RunDetectionLogRotation checks the detection log every 10 minutes and
+// rotates it when due. It is meant to run under the goroutine supervisor.
+func RunDetectionLogRotation(ctx context.Context) error {
+	return NewPoller("rotation", detectionRotateInterval, func(ctx context.Context) error {
+		rotated, err := RotateDetectionLog()
+		if err != nil {
+			return err
+		}
+		if rotated != "" {
+			log.Printf("[rotation] detection log rotated to %s", rotated)
+		}
+		return nil
+	}).Run(ctx)
+}
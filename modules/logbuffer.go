@@ -0,0 +1,94 @@
+package modules
+
+import (
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LogBuffer keeps the last N log lines in memory. It is an io.Writer, so it
+// can sit next to stderr behind the standard logger.
+type LogBuffer struct {
+	mu      sync.Mutex
+	lines   []string // ring storage
+	next    int      // slot the next line goes to
+	full    bool
+	partial string // text after the last newline, completed by the next Write
+}
+
+// NewLogBuffer creates a buffer holding up to size lines.
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{lines: make([]string, max(size, 1))}
+}
+
+// Write implements io.Writer, splitting p into lines.
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	text := b.partial + string(p)
+	for {
+		line, rest, ok := strings.Cut(text, "\n")
+		if !ok {
+			b.partial = text
+			break
+		}
+		b.lines[b.next] = line
+		b.next = (b.next + 1) % len(b.lines)
+		if b.next == 0 {
+			b.full = true
+		}
+		text = rest
+	}
+	return len(p), nil
+}
+
+// Lines returns up to limit buffered lines, newest first (limit <= 0: all).
+func (b *LogBuffer) Lines(limit int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := b.next
+	if b.full {
+		n = len(b.lines)
+	}
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	out := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, b.lines[(b.next-i+len(b.lines))%len(b.lines)])
+	}
+	return out
+}
+
+var (
+	logBufferMu sync.RWMutex
+	logBuffer   *LogBuffer
+)
+
+// InstallLogBuffer tees the standard logger into a buffer of size lines
+// (LOG_BUFFER_SIZE) so RecentLogs can serve them. size <= 0 leaves logging
+// untouched.
+func InstallLogBuffer(size int) {
+	if size <= 0 {
+		return
+	}
+	b := NewLogBuffer(size)
+	logBufferMu.Lock()
+	logBuffer = b
+	logBufferMu.Unlock()
+	log.SetOutput(io.MultiWriter(os.Stderr, b))
+}
+
+// RecentLogs returns up to limit captured log lines, newest first; ok is
+// false when no buffer is installed.
+func RecentLogs(limit int) (lines []string, ok bool) {
+	logBufferMu.RLock()
+	b := logBuffer
+	logBufferMu.RUnlock()
+	if b == nil {
+		return nil, false
+	}
+	return b.Lines(limit), true
+}
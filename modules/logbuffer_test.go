@@ -0,0 +1,26 @@
+package modules
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestLogBuffer(t *testing.T) {
+	b := NewLogBuffer(3)
+	if got := b.Lines(0); len(got) != 0 {
+		t.Fatalf("empty buffer has lines %q", got)
+	}
+	fmt.Fprint(b, "one\ntw")
+	fmt.Fprint(b, "o\n")
+	if got, want := b.Lines(0), []string{"two", "one"}; !slices.Equal(got, want) {
+		t.Errorf("lines = %q, want %q (newest first, partial writes joined)", got, want)
+	}
+	fmt.Fprint(b, "three\nfour\nfive\n")
+	if got, want := b.Lines(0), []string{"five", "four", "three"}; !slices.Equal(got, want) {
+		t.Errorf("after wrap: lines = %q, want %q", got, want)
+	}
+	if got, want := b.Lines(2), []string{"five", "four"}; !slices.Equal(got, want) {
+		t.Errorf("limit 2: lines = %q, want %q", got, want)
+	}
+}
@@ -1,26 +1,28 @@
 package modules
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"strings"
 )
 
-const replyTimeLayout = "2006-01-02 15:04:05 MST"
-
-// BuildHypeReply returns a human-friendly hype summary for a symbol.
-func BuildHypeReply(symbol string) string {
-	sym := strings.TrimSpace(symbol)
+// BuildHypeReply returns a human-friendly hype summary for a symbol, or the
+// market-data error when there is nothing to score.
+func BuildHypeReply(ctx context.Context, symbol string) (string, error) {
+	sym := canonicalSymbol(symbol)
 	if sym == "" {
-		return "Hype: unknown symbol"
+		return "Hype: unknown symbol", nil
+	}
+	if IsStablecoin(sym) {
+		return fmt.Sprintf("Hype score for $%s: n/a — stablecoin (pegged); momentum and hype don't apply", strings.ToUpper(sym)), nil
 	}
-	if strings.ToLower(os.Getenv("MOCK_MODE")) == "true" {
-		return fmt.Sprintf("Hype score for $%s: 0.00\nTrend: Trend snapshot for %s (mock): bullish momentum, strong volume spikes\n24h Move: 0.00%%", strings.ToUpper(sym), strings.ToUpper(sym))
+	if cfg().MockMode {
+		return fmt.Sprintf("Hype score for $%s: 0.00\nTrend: Trend snapshot for %s (mock): bullish momentum, strong volume spikes\n24h Move: 0.00%%", strings.ToUpper(sym), strings.ToUpper(sym)), nil
 	}
 
-	md, err := GetMarketData(sym)
+	md, label, err := marketDataOrFallback(ctx, sym)
 	if err != nil {
-		return fmt.Sprintf("Hype score for $%s: (data unavailable). Reason: %v", strings.ToUpper(sym), summarizeErr(err))
+		return "", fmt.Errorf("hype score for $%s: %w", strings.ToUpper(sym), err)
 	}
 
 	score := ComputeHypeScore(md)
@@ -33,89 +35,67 @@ func BuildHypeReply(symbol string) string {
 	}
 
 	reply := fmt.Sprintf(
-		"Hype score for $%s: %.2f\nTrend: %s (24h change: %.2f%%)\nPrice: $%0.6f • 24h Volume: $%.0f • MarketCap: $%.0f\nData as of: %s",
+		"Hype score for $%s: %.2f\nTrend: %s (24h change: %.2f%%)\nPrice: %s • 24h Volume: %s • MarketCap: %s\nData as of: %s",
 		strings.ToUpper(sym),
 		score,
 		strings.Title(trend),
 		md.Change24h,
-		md.PriceUSD,
-		md.Volume24h,
-		md.MarketCapUSD,
-		md.RetrievedAt.Format(replyTimeLayout),
+		FormatPrice(md.PriceUSD),
+		FormatLargeUSD(md.Volume24h),
+		FormatLargeUSD(md.MarketCapUSD),
+		FormatReplyTime(md.RetrievedAt),
 	)
-	return reply
+	return withLabel(label, reply), nil
 }
 
-// BuildSentimentReply returns a simple sentiment summary for a token.
-func BuildSentimentReply(symbol string) string {
-	sym := strings.TrimSpace(symbol)
+// BuildSentimentReply returns a simple sentiment summary for a token, or the
+// market-data error.
+func BuildSentimentReply(ctx context.Context, symbol string) (string, error) {
+	sym := canonicalSymbol(symbol)
 	if sym == "" {
-		return "Sentiment: unknown symbol"
+		return "Sentiment: unknown symbol", nil
 	}
-	if strings.ToLower(os.Getenv("MOCK_MODE")) == "true" {
-		return fmt.Sprintf("Sentiment for $%s:\n👍 0.0%% positive\n👎 0.0%% negative", strings.ToUpper(sym))
+	if cfg().MockMode {
+		return fmt.Sprintf("Sentiment for $%s:\n👍 0.0%% positive\n👎 0.0%% negative", strings.ToUpper(sym)), nil
 	}
 
-	md, err := GetMarketData(sym)
+	md, label, err := marketDataOrFallback(ctx, sym)
 	if err != nil {
-		return fmt.Sprintf("Sentiment for $%s: (data unavailable). Reason: %v", strings.ToUpper(sym), summarizeErr(err))
+		return "", fmt.Errorf("sentiment for $%s: %w", strings.ToUpper(sym), err)
 	}
 
-	pos := 0.0
-	neg := 0.0
-	if md.Change24h > 1.0 {
-		pos = 75.0
-		neg = 25.0
-	} else if md.Change24h < -1.0 {
-		pos = 25.0
-		neg = 75.0
-	} else {
-		pos = 50.0
-		neg = 50.0
-	}
+	pos, neg := sentimentSplit(md.Change24h)
 
-	return fmt.Sprintf("Sentiment for $%s:\n👍 %.1f%% positive\n👎 %.1f%% negative\nPrice: $%.6f (24h: %+0.2f%%)",
-		strings.ToUpper(sym), pos, neg, md.PriceUSD, md.Change24h)
+	return withLabel(label, fmt.Sprintf("Sentiment for $%s:\n👍 %.1f%% positive\n👎 %.1f%% negative\nPrice: %s (24h: %+0.2f%%)",
+		strings.ToUpper(sym), pos, neg, FormatPrice(md.PriceUSD), md.Change24h)), nil
 }
 
-// BuildRiskReply returns a small risk-check summary.
-func BuildRiskReply(symbol string) string {
-	sym := strings.TrimSpace(symbol)
+// sentimentSplit maps a 24h change to a positive/negative percentage split.
+func sentimentSplit(change24h float64) (pos, neg float64) {
+	if change24h > 1.0 {
+		return 75.0, 25.0
+	} else if change24h < -1.0 {
+		return 25.0, 75.0
+	}
+	return 50.0, 50.0
+}
+
+// BuildRiskReply returns a small risk-check summary, or the market-data error.
+func BuildRiskReply(ctx context.Context, symbol string) (string, error) {
+	sym := canonicalSymbol(symbol)
 	if sym == "" {
-		return "Risk: unknown symbol"
+		return "Risk: unknown symbol", nil
 	}
-	if strings.ToLower(os.Getenv("MOCK_MODE")) == "true" {
-		return fmt.Sprintf("Risk check for $%s:\n- RiskScore: 0.30\n- Indicators:\n - Very low market cap", strings.ToUpper(sym))
+	if cfg().MockMode {
+		return fmt.Sprintf("Risk check for $%s:\n- RiskScore: 0.30\n- Indicators:\n - Very low market cap", strings.ToUpper(sym)), nil
 	}
 
-	md, err := GetMarketData(sym)
+	md, label, err := marketDataOrFallback(ctx, sym)
 	if err != nil {
-		return fmt.Sprintf("Risk check for $%s: (data unavailable). Reason: %v", strings.ToUpper(sym), summarizeErr(err))
+		return "", fmt.Errorf("risk check for $%s: %w", strings.ToUpper(sym), err)
 	}
 
-	score := 0.0
-	if md.MarketCapUSD <= 0 {
-		score = 0.9
-	} else {
-		mc := md.MarketCapUSD
-		if mc < 1_000_000 {
-			score = 0.85
-		} else if mc < 10_000_000 {
-			score = 0.6
-		} else if mc < 100_000_000 {
-			score = 0.4
-		} else if mc < 1_000_000_000 {
-			score = 0.25
-		} else {
-			score = 0.12
-		}
-	}
-	if md.Change24h > 5 || md.Change24h < -5 {
-		score = score + 0.15
-	}
-	if score > 1 {
-		score = 1
-	}
+	score := ComputeRiskScore(md)
 
 	indicators := []string{}
 	if md.MarketCapUSD < 10_000_000 {
@@ -134,15 +114,15 @@ func BuildRiskReply(symbol string) string {
 		indicators = append(indicators, "No immediate red flags")
 	}
 
-	reply := fmt.Sprintf("Risk check for $%s:\n- RiskScore: %.2f\n- Indicators:\n - %s\nPrice: $%.6f • MarketCap: $%.0f • 24h: %+0.2f%%",
+	reply := fmt.Sprintf("Risk check for $%s:\n- RiskScore: %.2f\n- Indicators:\n - %s\nPrice: %s • MarketCap: %s • 24h: %+0.2f%%",
 		strings.ToUpper(sym),
 		score,
 		strings.Join(indicators, "\n - "),
-		md.PriceUSD,
-		md.MarketCapUSD,
+		FormatPrice(md.PriceUSD),
+		FormatLargeUSD(md.MarketCapUSD),
 		md.Change24h,
 	)
-	return reply
+	return withLabel(label, reply), nil
 }
 
 func summarizeErr(err error) string {
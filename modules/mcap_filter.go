@@ -0,0 +1,58 @@
+package modules
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// market caps move slowly, so the filter keeps them far longer than the price cache
+const mcapFilterTTL = time.Hour
+
+var (
+	mcapFilterMu    sync.Mutex
+	mcapFilterCache = map[string]mcapEntry{}
+)
+
+type mcapEntry struct {
+	marketCap float64
+	at        time.Time
+}
+
+// scannerMinMarketCap returns SCANNER_MIN_MARKETCAP in USD (default 0: filter off).
+func scannerMinMarketCap() float64 {
+	return cfg().ScannerMinMarketCap
+}
+
+// belowMinMarketCap reports whether token's market cap is known to be under
+// the floor. Unknown market data fails open (false) so an API outage doesn't
+// silence the scanner; contract addresses can't be looked up by symbol and
+// are always kept.
+func belowMinMarketCap(token string, floor float64) bool {
+	if floor <= 0 || IsEVMAddress(token) {
+		return false
+	}
+	sym := canonicalSymbol(token)
+	if sym == "" {
+		return false
+	}
+
+	mcapFilterMu.Lock()
+	e, ok := mcapFilterCache[sym]
+	mcapFilterMu.Unlock()
+	if !ok || time.Since(e.at) > mcapFilterTTL {
+		md, err := GetMarketData(sym)
+		if err != nil || md.MarketCapUSD <= 0 {
+			if err != nil {
+				log.Printf("[xscanner] market cap filter: %s unavailable, keeping detection: %v", strings.ToUpper(sym), summarizeErr(err))
+			}
+			return false
+		}
+		e = mcapEntry{marketCap: md.MarketCapUSD, at: time.Now()}
+		mcapFilterMu.Lock()
+		mcapFilterCache[sym] = e
+		mcapFilterMu.Unlock()
+	}
+	return e.marketCap < floor
+}
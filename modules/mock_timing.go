@@ -0,0 +1,98 @@
+package modules
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MockBurst is one outcome of a realistic mock tick: Count detections with
+// relative probability Weight. Count 0 is a quiet tick.
+type MockBurst struct {
+	Count  int
+	Weight float64
+}
+
+// Mostly zero or one mention per tick, with the odd burst when a token trends.
+var defaultMockBursts = []MockBurst{
+	{Count: 0, Weight: 35},
+	{Count: 1, Weight: 40},
+	{Count: 2, Weight: 15},
+	{Count: 3, Weight: 7},
+	{Count: 5, Weight: 3},
+}
+
+// mockRealisticJitter spreads realistic mock ticks by ±30% of the interval.
+const mockRealisticJitter = 0.3
+
+// defaultMockMaxPerTick caps a single burst so a bad distribution can't flood the pipeline.
+const defaultMockMaxPerTick = 10
+
+// MockRealistic reports whether MOCK_REALISTIC=true: mock mode then emits a
+// varying number of detections per tick on a jittered schedule instead of
+// exactly one per fixed tick.
+func MockRealistic() bool {
+	return cfg().MockRealistic
+}
+
+// ParseMockBursts parses "count:weight" pairs, e.g. "0:35,1:40,2:15,5:10".
+func ParseMockBursts(s string) ([]MockBurst, error) {
+	var bursts []MockBurst
+	total := 0.0
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		countStr, weightStr, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("mock burst %q: want count:weight", part)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(countStr))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("mock burst %q: invalid count", part)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("mock burst %q: invalid weight", part)
+		}
+		bursts = append(bursts, MockBurst{Count: count, Weight: weight})
+		total += weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("no mock bursts with positive weight")
+	}
+	sort.Slice(bursts, func(i, j int) bool { return bursts[i].Count < bursts[j].Count })
+	return bursts, nil
+}
+
+// MockBursts returns the distribution from MOCK_BURSTS, or the defaults
+// (quiet 35%, one 40%, two 15%, three 7%, five 3%).
+func MockBursts() []MockBurst {
+	return cfg().MockBursts
+}
+
+// mockMaxPerTick is MOCK_MAX_PER_TICK (default 10).
+func mockMaxPerTick() int {
+	return cfg().MockMaxPerTick
+}
+
+// drawMockCount picks how many detections a realistic mock tick emits.
+func drawMockCount(bursts []MockBurst, maxPerTick int) int {
+	total := 0.0
+	for _, b := range bursts {
+		total += b.Weight
+	}
+	r := rand.Float64() * total
+	count := 0
+	for _, b := range bursts {
+		count = b.Count
+		if r < b.Weight {
+			break
+		}
+		r -= b.Weight
+	}
+	return min(count, maxPerTick)
+}
@@ -0,0 +1,35 @@
+package modules
+
+import "testing"
+
+func TestParseMockBursts(t *testing.T) {
+	bursts, err := ParseMockBursts("2:10, 0:50,1:40")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bursts) != 3 || bursts[0].Count != 0 || bursts[2].Count != 2 {
+		t.Errorf("bursts = %+v, want sorted by count", bursts)
+	}
+	for _, bad := range []string{"", "1", "x:1", "-1:5", "1:-5", "0:0"} {
+		if _, err := ParseMockBursts(bad); err == nil {
+			t.Errorf("ParseMockBursts(%q) = nil error", bad)
+		}
+	}
+}
+
+func TestDrawMockCount(t *testing.T) {
+	seen := map[int]bool{}
+	for i := 0; i < 1000; i++ {
+		n := drawMockCount([]MockBurst{{0, 1}, {3, 1}, {20, 1}}, 5)
+		if n != 0 && n != 3 && n != 5 {
+			t.Fatalf("drawMockCount = %d, want 0, 3 or the cap 5", n)
+		}
+		seen[n] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("outcomes = %v, want all of 0, 3, 5", seen)
+	}
+	if n := drawMockCount([]MockBurst{{0, 0}, {2, 1}}, 10); n != 2 {
+		t.Errorf("zero-weight burst drawn: %d", n)
+	}
+}
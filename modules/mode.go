@@ -0,0 +1,81 @@
+package modules
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dependency marks which external services a command relies on, so the mode
+// banner only mentions outages that affect the reply.
+type Dependency int
+
+const (
+	NeedsMarket Dependency = 1 << iota // CoinGecko market data
+	NeedsAI                            // Gemini / OpenAI
+)
+
+// marketDownAfter is how long the market provider counts as down after a
+// failed live fetch with no success since.
+const marketDownAfter = 5 * time.Minute
+
+var (
+	marketHealthMu  sync.Mutex
+	marketFailingAt time.Time // first failure since the last success; zero when healthy
+)
+
+// noteMarketFetch records the outcome of a live market fetch. Only transport,
+// rate-limit and server errors count as an outage; unknown tokens don't.
+func noteMarketFetch(err error) {
+	marketHealthMu.Lock()
+	defer marketHealthMu.Unlock()
+	switch {
+	case err == nil:
+		marketFailingAt = time.Time{}
+	case IsRetryable(err) && marketFailingAt.IsZero():
+		marketFailingAt = time.Now()
+	}
+}
+
+// MarketDataDown reports whether live market fetches have been failing, i.e.
+// the last one failed and none succeeded since.
+func MarketDataDown() bool {
+	marketHealthMu.Lock()
+	defer marketHealthMu.Unlock()
+	return !marketFailingAt.IsZero() && time.Since(marketFailingAt) < marketDownAfter
+}
+
+// ModeBannerEnabled reports whether replies get a mode banner (REPLY_MODE_BANNER,
+// default true). UIs that show the agent's mode themselves can turn it off.
+func ModeBannerEnabled() bool {
+	return cfg().ReplyModeBanner
+}
+
+// ModeBanner describes why a reply for a command with the given dependencies
+// may be degraded, e.g. "[mock mode]" or "[offline: market data unavailable]".
+// It is empty when the agent is fully operational for that command.
+func ModeBanner(needs Dependency) string {
+	var notes []string
+	c := cfg()
+	if c.MockMode {
+		notes = append(notes, "[mock mode]")
+	} else if needs&NeedsMarket != 0 && MarketDataDown() {
+		notes = append(notes, "[offline: market data unavailable]")
+	}
+	if needs&NeedsAI != 0 && !c.AIConfigured() {
+		notes = append(notes, "[degraded: AI not configured]")
+	}
+	return strings.Join(notes, " ")
+}
+
+// WithModeBanner prepends ModeBanner(needs) to reply unless it is empty or
+// REPLY_MODE_BANNER=false.
+func WithModeBanner(needs Dependency, reply string) string {
+	if !ModeBannerEnabled() {
+		return reply
+	}
+	if banner := ModeBanner(needs); banner != "" {
+		return banner + "\n" + reply
+	}
+	return reply
+}
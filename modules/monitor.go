@@ -0,0 +1,475 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMonitorInterval = 60 * time.Second
+	minMonitorInterval     = 10 * time.Second
+	monitorTick            = 5 * time.Second
+	defaultMonitorMovePct  = 5.0 // alert on +/-5% from reference when no price thresholds are set
+	defaultMonitorHype     = 0.7
+)
+
+// MonitorConfig is the persisted configuration of one token monitor.
+type MonitorConfig struct {
+	Token       string    `json:"token"`
+	IntervalSec int       `json:"interval_sec"`
+	PriceAbove  float64   `json:"price_above,omitempty"` // 0 = unset
+	PriceBelow  float64   `json:"price_below,omitempty"` // 0 = unset
+	HypeAbove   float64   `json:"hype_above"`
+	CreatedAt   time.Time `json:"created_at"`
+	TTLSec      int       `json:"ttl_sec,omitempty"`   // 0 = never expires
+	ExpiresAt   time.Time `json:"expires_at,omitzero"` // auto-removed after this unless renewed
+}
+
+// monitorState is the runtime state of a monitor (not persisted).
+type monitorState struct {
+	cfg       MonitorConfig
+	nextCheck time.Time
+	lastPrice float64
+	lastHype  float64
+	refPrice  float64 // reference for the default % move alert
+}
+
+// MonitorManager keeps persistent token monitors and emits a Detection when a
+// threshold is crossed. Run is meant to be registered with a goroutine supervisor.
+type MonitorManager struct {
+	mu       sync.Mutex
+	monitors map[string]*monitorState
+	path     string
+	out      chan<- Detection
+}
+
+// MonitorStatePath returns the monitor persistence file (MONITOR_STATE_FILE, default monitors.json).
+func MonitorStatePath() string {
+	return cfg().MonitorStateFile
+}
+
+// NewMonitorManager creates a manager persisting to path and loads any saved monitors.
+func NewMonitorManager(path string, out chan<- Detection) *MonitorManager {
+	m := &MonitorManager{
+		monitors: map[string]*monitorState{},
+		path:     path,
+		out:      out,
+	}
+	if err := m.load(); err != nil {
+		log.Println("[monitor] Warning: could not load monitors:", err)
+	}
+	return m
+}
+
+func (m *MonitorManager) load() error {
+	b, err := os.ReadFile(m.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var cfgs []MonitorConfig
+	if err := json.Unmarshal(b, &cfgs); err != nil {
+		return err
+	}
+	for _, c := range cfgs {
+		m.monitors[c.Token] = &monitorState{cfg: c}
+	}
+	if len(cfgs) > 0 {
+		log.Printf("[monitor] Restored %d monitor(s) from %s", len(cfgs), m.path)
+	}
+	return nil
+}
+
+// save writes all monitor configs to disk. Caller must hold mu.
+func (m *MonitorManager) save() error {
+	cfgs := make([]MonitorConfig, 0, len(m.monitors))
+	for _, s := range m.monitors {
+		cfgs = append(cfgs, s.cfg)
+	}
+	sort.Slice(cfgs, func(i, j int) bool { return cfgs[i].Token < cfgs[j].Token })
+	b, err := json.MarshalIndent(cfgs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, b, 0644)
+}
+
+// Add registers (or replaces) a monitor, persists it and returns the
+// config with defaults applied.
+func (m *MonitorManager) Add(mc MonitorConfig) (MonitorConfig, error) {
+	mc, err := withMonitorDefaults(mc)
+	if err != nil {
+		return mc, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.monitors[mc.Token] = &monitorState{cfg: mc}
+	return mc, m.save()
+}
+
+// withMonitorDefaults normalizes the token and fills in interval, hype
+// threshold, creation and expiry times.
+func withMonitorDefaults(mc MonitorConfig) (MonitorConfig, error) {
+	mc.Token = strings.ToUpper(canonicalSymbol(mc.Token))
+	if mc.Token == "" {
+		return mc, fmt.Errorf("token is required")
+	}
+	if mc.IntervalSec <= 0 {
+		mc.IntervalSec = int(defaultMonitorInterval.Seconds())
+	}
+	if time.Duration(mc.IntervalSec)*time.Second < minMonitorInterval {
+		mc.IntervalSec = int(minMonitorInterval.Seconds())
+	}
+	if mc.HypeAbove <= 0 {
+		mc.HypeAbove = defaultMonitorHype
+	}
+	if mc.CreatedAt.IsZero() {
+		mc.CreatedAt = TimeNowUTC()
+	}
+	if mc.TTLSec > 0 && mc.ExpiresAt.IsZero() {
+		mc.ExpiresAt = TimeNowUTC().Add(time.Duration(mc.TTLSec) * time.Second)
+	}
+	return mc, nil
+}
+
+// Remove stops the monitor for token. Returns false if none was registered.
+func (m *MonitorManager) Remove(token string) (bool, error) {
+	token = strings.ToUpper(canonicalSymbol(token))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.monitors[token]; !ok {
+		return false, nil
+	}
+	delete(m.monitors, token)
+	return true, m.save()
+}
+
+// Renew pushes the expiry of token's monitor out by ttl from now, or by its
+// own TTL when ttl is 0. A monitor without a TTL gets ttl as its new TTL.
+func (m *MonitorManager) Renew(token string, ttl time.Duration) (MonitorConfig, bool, error) {
+	token = strings.ToUpper(canonicalSymbol(token))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.monitors[token]
+	if !ok {
+		return MonitorConfig{}, false, nil
+	}
+	if ttl > 0 {
+		s.cfg.TTLSec = int(ttl.Seconds())
+	}
+	if s.cfg.TTLSec <= 0 {
+		return s.cfg, true, fmt.Errorf("monitor for $%s has no TTL; give one, e.g. monitor renew %s 24h", token, strings.ToLower(token))
+	}
+	s.cfg.ExpiresAt = TimeNowUTC().Add(time.Duration(s.cfg.TTLSec) * time.Second)
+	return s.cfg, true, m.save()
+}
+
+// expire removes monitors whose TTL ran out. Caller must hold mu.
+func (m *MonitorManager) expire(now time.Time) {
+	removed := false
+	for tok, s := range m.monitors {
+		if !s.cfg.ExpiresAt.IsZero() && now.After(s.cfg.ExpiresAt) {
+			log.Printf("[monitor] $%s expired (TTL %ds not renewed since %s), removed", tok, s.cfg.TTLSec, s.cfg.ExpiresAt.Add(-time.Duration(s.cfg.TTLSec)*time.Second).Format(time.RFC3339))
+			delete(m.monitors, tok)
+			removed = true
+		}
+	}
+	if removed {
+		if err := m.save(); err != nil {
+			log.Println("[monitor] Warning: could not save monitors:", err)
+		}
+	}
+}
+
+// List returns the configured monitors sorted by token.
+func (m *MonitorManager) List() []MonitorConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]MonitorConfig, 0, len(m.monitors))
+	for _, s := range m.monitors {
+		out = append(out, s.cfg)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Token < out[j].Token })
+	return out
+}
+
+// Run checks due monitors until ctx is cancelled.
+func (m *MonitorManager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(monitorTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.checkDue(ctx)
+		}
+	}
+}
+
+func (m *MonitorManager) checkDue(ctx context.Context) {
+	now := TimeNowUTC()
+	m.mu.Lock()
+	m.expire(now)
+	due := []string{}
+	for tok, s := range m.monitors {
+		if !now.Before(s.nextCheck) {
+			s.nextCheck = now.Add(time.Duration(s.cfg.IntervalSec) * time.Second)
+			due = append(due, tok)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, tok := range due {
+		// fetch outside the lock; GetMarketData may block on the network
+		md, stale, err := GetMarketDataStale(tok)
+		if err != nil || stale {
+			// never alert on last-known data
+			log.Printf("[monitor] %s: live market data unavailable: %v", tok, summarizeErr(err))
+			continue
+		}
+		hype := ComputeHypeScore(md)
+
+		m.mu.Lock()
+		s, ok := m.monitors[tok]
+		var dets []Detection
+		if ok {
+			dets = s.evaluate(md.PriceUSD, hype, now)
+		}
+		m.mu.Unlock()
+
+		for _, d := range dets {
+			select {
+			case m.out <- d:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// evaluate compares the new reading against the thresholds and returns
+// detections for every crossing since the previous reading.
+func (s *monitorState) evaluate(price, hype float64, now time.Time) []Detection {
+	var dets []Detection
+	emit := func(signal string, conf float64, format string, args ...interface{}) {
+		d := Detection{
+			Token:      s.cfg.Token,
+			Signal:     signal,
+			Confidence: conf,
+			Source:     SourceMonitor,
+			Text:       fmt.Sprintf(format, args...),
+			Timestamp:  now,
+		}
+		d.EnsureID()
+		dets = append(dets, d)
+	}
+
+	first := s.lastPrice == 0
+	if !first {
+		if s.cfg.PriceAbove > 0 && s.lastPrice < s.cfg.PriceAbove && price >= s.cfg.PriceAbove {
+			emit("monitor_price_above", 1, "$%s crossed above $%g (now %s)", s.cfg.Token, s.cfg.PriceAbove, FormatPrice(price))
+		}
+		if s.cfg.PriceBelow > 0 && s.lastPrice > s.cfg.PriceBelow && price <= s.cfg.PriceBelow {
+			emit("monitor_price_below", 1, "$%s crossed below $%g (now %s)", s.cfg.Token, s.cfg.PriceBelow, FormatPrice(price))
+		}
+		if s.lastHype < s.cfg.HypeAbove && hype >= s.cfg.HypeAbove {
+			emit("monitor_hype", hype, "$%s hype score crossed %.2f (now %.2f)", s.cfg.Token, s.cfg.HypeAbove, hype)
+		}
+	}
+
+	// without explicit price thresholds, alert on a large move from the reference price
+	if s.cfg.PriceAbove == 0 && s.cfg.PriceBelow == 0 {
+		if s.refPrice == 0 {
+			s.refPrice = price
+		} else if move := (price - s.refPrice) / s.refPrice * 100; math.Abs(move) >= defaultMonitorMovePct {
+			emit("monitor_price_move", 1, "$%s moved %+.2f%% to %s", s.cfg.Token, move, FormatPrice(price))
+			s.refPrice = price
+		}
+	}
+
+	s.lastPrice = price
+	s.lastHype = hype
+	return dets
+}
+
+const monitorUsage = "Usage: monitor [token] [interval_sec] [above=price] [below=price] [hype=0..1] [ttl=24h] | monitor list | monitor stop [token] | monitor renew [token] [ttl]"
+
+// RunMonitor implements "monitor <token> [interval] [above=X] [below=Y] [hype=Z]",
+// "monitor list" and "monitor stop <token>".
+func RunMonitor(ctx context.Context, m *MonitorManager, args []string) (string, error) {
+	if m == nil {
+		return "Monitoring is not available.", nil
+	}
+	if len(args) == 0 {
+		return monitorUsage, nil
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list":
+		cfgs := m.List()
+		if len(cfgs) == 0 {
+			return "No active monitors.", nil
+		}
+		var b strings.Builder
+		b.WriteString("Active monitors:\n")
+		for _, c := range cfgs {
+			fmt.Fprintf(&b, "- $%s every %ds • %s%s\n", c.Token, c.IntervalSec, describeThresholds(c), describeExpiry(c))
+		}
+		return b.String(), nil
+	case "stop":
+		if len(args) < 2 {
+			return "Usage: monitor stop [token]", nil
+		}
+		removed, err := m.Remove(args[1])
+		if err != nil {
+			return "", err
+		}
+		if !removed {
+			return fmt.Sprintf("No monitor for $%s.", strings.ToUpper(args[1])), nil
+		}
+		return fmt.Sprintf("Stopped monitoring $%s.", strings.ToUpper(args[1])), nil
+	case "renew":
+		if len(args) < 2 {
+			return "Usage: monitor renew [token] [ttl]", nil
+		}
+		var ttl time.Duration
+		if len(args) > 2 {
+			d, err := time.ParseDuration(args[2])
+			if err != nil || d <= 0 {
+				return "TTL must be a duration like 30m or 24h.", nil
+			}
+			ttl = d
+		}
+		c, ok, err := m.Renew(args[1], ttl)
+		if !ok {
+			return fmt.Sprintf("No monitor for $%s.", strings.ToUpper(args[1])), nil
+		}
+		if err != nil {
+			return err.Error(), nil
+		}
+		return fmt.Sprintf("Renewed $%s monitor%s.", c.Token, describeExpiry(c)), nil
+	}
+
+	mc, msg := parseMonitorRequest(args)
+	if msg != "" {
+		return msg, nil
+	}
+	c, err := m.Add(mc)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Monitoring $%s every %ds • %s%s", c.Token, c.IntervalSec, describeThresholds(c), describeExpiry(c)), nil
+}
+
+// describeExpiry returns " • expires <time>" for monitors with a TTL.
+func describeExpiry(c MonitorConfig) string {
+	if c.ExpiresAt.IsZero() {
+		return ""
+	}
+	return " • expires " + FormatReplyTime(c.ExpiresAt) + " unless renewed"
+}
+
+func describeThresholds(c MonitorConfig) string {
+	parts := []string{}
+	if c.PriceAbove > 0 {
+		parts = append(parts, fmt.Sprintf("price above $%g", c.PriceAbove))
+	}
+	if c.PriceBelow > 0 {
+		parts = append(parts, fmt.Sprintf("price below $%g", c.PriceBelow))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("price move ±%.0f%%", defaultMonitorMovePct))
+	}
+	parts = append(parts, fmt.Sprintf("hype above %.2f", c.HypeAbove))
+	return strings.Join(parts, ", ")
+}
+
+// parseMonitorRequest parses "<token> [interval] [above=X] [below=Y] [hype=Z] [ttl=D]".
+// A non-empty msg is the reply to send instead (usage or a parse error).
+func parseMonitorRequest(args []string) (MonitorConfig, string) {
+	mc := MonitorConfig{Token: args[0]}
+	for _, a := range args[1:] {
+		key, val, hasKey := strings.Cut(a, "=")
+		if !hasKey {
+			n, err := strconv.Atoi(a)
+			if err != nil || n <= 0 {
+				return mc, monitorUsage
+			}
+			mc.IntervalSec = n
+			continue
+		}
+		if strings.ToLower(key) == "ttl" {
+			d, err := time.ParseDuration(val)
+			if err != nil || d <= 0 {
+				return mc, "TTL must be a duration like 30m or 24h."
+			}
+			mc.TTLSec = int(d.Seconds())
+			continue
+		}
+		v, err := strconv.ParseFloat(val, 64)
+		if err != nil || v < 0 {
+			return mc, monitorUsage
+		}
+		switch strings.ToLower(key) {
+		case "above":
+			mc.PriceAbove = v
+		case "below":
+			mc.PriceBelow = v
+		case "hype":
+			mc.HypeAbove = v
+		default:
+			return mc, monitorUsage
+		}
+	}
+
+	return mc, ""
+}
+
+// DryRunMonitor validates a monitor command and describes what it would do
+// without changing the persisted monitors.
+func DryRunMonitor(ctx context.Context, m *MonitorManager, args []string) (string, error) {
+	if m == nil {
+		return "Monitoring is not available.", nil
+	}
+	if len(args) == 0 {
+		return monitorUsage, nil
+	}
+	switch strings.ToLower(args[0]) {
+	case "list":
+		return RunMonitor(ctx, m, args)
+	case "stop", "renew":
+		if len(args) < 2 {
+			return fmt.Sprintf("Usage: monitor %s [token]", strings.ToLower(args[0])), nil
+		}
+		token := strings.ToUpper(args[1])
+		for _, c := range m.List() {
+			if c.Token == token {
+				return fmt.Sprintf("Dry run: would %s the $%s monitor. Nothing was changed.", strings.ToLower(args[0]), token), nil
+			}
+		}
+		return fmt.Sprintf("No monitor for $%s.", token), nil
+	}
+
+	mc, msg := parseMonitorRequest(args)
+	if msg != "" {
+		return msg, nil
+	}
+	c, err := withMonitorDefaults(mc)
+	if err != nil {
+		return err.Error(), nil
+	}
+	return fmt.Sprintf("Dry run: would monitor $%s every %ds • %s%s. Nothing was saved.", c.Token, c.IntervalSec, describeThresholds(c), describeExpiry(c)), nil
+}
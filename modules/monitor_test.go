@@ -0,0 +1,162 @@
+package modules
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMonitorEvaluate(t *testing.T) {
+	type reading struct {
+		price, hype float64
+		want        []string // signals emitted
+	}
+	tests := []struct {
+		name     string
+		cfg      MonitorConfig
+		readings []reading
+	}{
+		{
+			name: "first reading only sets the baseline",
+			cfg:  MonitorConfig{PriceAbove: 100, PriceBelow: 50, HypeAbove: 0.7},
+			readings: []reading{
+				{price: 120, hype: 0.9},
+				{price: 120, hype: 0.9},
+			},
+		},
+		{
+			name: "price crossings fire once per crossing",
+			cfg:  MonitorConfig{PriceAbove: 100, PriceBelow: 50, HypeAbove: 0.7},
+			readings: []reading{
+				{price: 90},
+				{price: 100, want: []string{"monitor_price_above"}},
+				{price: 110},
+				{price: 95},
+				{price: 101, want: []string{"monitor_price_above"}},
+				{price: 50, want: []string{"monitor_price_below"}},
+				{price: 40},
+			},
+		},
+		{
+			name: "hype crossing",
+			cfg:  MonitorConfig{PriceAbove: 100, HypeAbove: 0.7},
+			readings: []reading{
+				{price: 90, hype: 0.5},
+				{price: 90, hype: 0.7, want: []string{"monitor_hype"}},
+				{price: 90, hype: 0.8},
+				{price: 120, hype: 0.6, want: []string{"monitor_price_above"}},
+				{price: 120, hype: 0.75, want: []string{"monitor_hype"}},
+			},
+		},
+		{
+			name: "default move alert re-bases after firing",
+			cfg:  MonitorConfig{HypeAbove: 0.7},
+			readings: []reading{
+				{price: 100},
+				{price: 104.9},
+				{price: 95, want: []string{"monitor_price_move"}},
+				{price: 99},
+				{price: 100, want: []string{"monitor_price_move"}},
+			},
+		},
+		{
+			name: "explicit thresholds disable the move alert",
+			cfg:  MonitorConfig{PriceBelow: 10, HypeAbove: 0.7},
+			readings: []reading{
+				{price: 100},
+				{price: 150},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Token = "SOL"
+			s := &monitorState{cfg: tt.cfg}
+			now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+			for i, r := range tt.readings {
+				var got []string
+				for _, d := range s.evaluate(r.price, r.hype, now) {
+					if d.Token != "SOL" || d.Source != SourceMonitor || !d.Timestamp.Equal(now) {
+						t.Errorf("reading %d: detection %+v", i, d)
+					}
+					got = append(got, d.Signal)
+				}
+				if !slices.Equal(got, r.want) {
+					t.Fatalf("reading %d (%g, %g): signals %v, want %v", i, r.price, r.hype, got, r.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMonitorExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitors.json")
+	m := NewMonitorManager(path, nil)
+	short, err := m.Add(MonitorConfig{Token: "sol", TTLSec: 3600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Add(MonitorConfig{Token: "eth"}); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(short.ExpiresAt); d < 59*time.Minute || d > time.Hour {
+		t.Fatalf("ExpiresAt is %v away, want about 1h", d)
+	}
+
+	m.mu.Lock()
+	m.expire(short.ExpiresAt.Add(-time.Second))
+	m.mu.Unlock()
+	if got := len(m.List()); got != 2 {
+		t.Fatalf("%d monitors before the TTL ran out, want 2", got)
+	}
+
+	m.mu.Lock()
+	m.expire(short.ExpiresAt.Add(time.Second))
+	m.mu.Unlock()
+	// the removal is persisted; the monitor without a TTL never expires
+	for _, mm := range []*MonitorManager{m, NewMonitorManager(path, nil)} {
+		if got := mm.List(); len(got) != 1 || got[0].Token != "ETH" {
+			t.Errorf("monitors after expiry = %+v, want only ETH", got)
+		}
+	}
+}
+
+func TestMonitorRenew(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttlSec  int // of the existing monitor; 0 = none
+		token   string
+		ttl     time.Duration
+		wantOK  bool
+		wantErr bool
+		wantTTL time.Duration
+	}{
+		{name: "own TTL", ttlSec: 3600, token: "sol", wantOK: true, wantTTL: time.Hour},
+		{name: "new TTL replaces the old one", ttlSec: 3600, token: "SOL", ttl: 24 * time.Hour, wantOK: true, wantTTL: 24 * time.Hour},
+		{name: "TTL added to a monitor without one", token: "sol", ttl: 30 * time.Minute, wantOK: true, wantTTL: 30 * time.Minute},
+		{name: "no TTL to renew with", token: "sol", wantOK: true, wantErr: true},
+		{name: "unknown token", ttlSec: 3600, token: "eth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMonitorManager(filepath.Join(t.TempDir(), "monitors.json"), nil)
+			if _, err := m.Add(MonitorConfig{Token: "sol", TTLSec: tt.ttlSec}); err != nil {
+				t.Fatal(err)
+			}
+			got, ok, err := m.Renew(tt.token, tt.ttl)
+			if ok != tt.wantOK || (err != nil) != tt.wantErr {
+				t.Fatalf("Renew = ok %v, err %v; want ok %v, error %v", ok, err, tt.wantOK, tt.wantErr)
+			}
+			if tt.wantTTL == 0 {
+				return
+			}
+			if got.TTLSec != int(tt.wantTTL.Seconds()) {
+				t.Errorf("TTLSec = %d, want %d", got.TTLSec, int(tt.wantTTL.Seconds()))
+			}
+			if d := time.Until(got.ExpiresAt); d < tt.wantTTL-time.Minute || d > tt.wantTTL {
+				t.Errorf("ExpiresAt is %v away, want about %v", d, tt.wantTTL)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package modules
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Notification formats (DIGEST_WEBHOOK_FORMAT).
+const (
+	NotifyFormatPlain    = "plain" // default: {"text", "content"}, accepted by most incoming webhooks
+	NotifyFormatSlack    = "slack"
+	NotifyFormatDiscord  = "discord"
+	NotifyFormatTelegram = "telegram"
+)
+
+// Channel payload limits; longer text is split (Slack) or truncated.
+const (
+	slackSectionMax    = 3000
+	discordContentMax  = 2000
+	discordEmbedMax    = 4096
+	telegramMessageMax = 4096
+)
+
+// discordColor is the embed accent: red for dump warnings, amber otherwise.
+const (
+	discordColorWarn  = 0xE74C3C
+	discordColorAlert = 0xF1C40F
+)
+
+// NotificationFormatter renders a detection or a digest into the JSON payload
+// a channel's webhook expects. Sinks marshal the returned value as-is.
+type NotificationFormatter interface {
+	FormatDetection(d Detection) any
+	FormatDigest(report string) any
+}
+
+// NotificationFormatterFor returns the formatter for a format name; empty
+// means plain.
+func NotificationFormatterFor(name string) (NotificationFormatter, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", NotifyFormatPlain:
+		return PlainFormatter{}, nil
+	case NotifyFormatSlack:
+		return SlackFormatter{}, nil
+	case NotifyFormatDiscord:
+		return DiscordFormatter{}, nil
+	case NotifyFormatTelegram:
+		return TelegramFormatter{}, nil
+	}
+	return nil, fmt.Errorf("unknown notification format %q (want plain, slack, discord or telegram)", name)
+}
+
+// detectionTitle is the one-line headline shared by all formats.
+func detectionTitle(d Detection) string {
+	icon := "🚨"
+	if d.Signal == "dump_warning" {
+		icon = "📉"
+	}
+	return fmt.Sprintf("%s %s: $%s", icon, d.Signal, strings.ToUpper(d.Token))
+}
+
+// detectionFacts lists the detail lines of d, without any markup.
+func detectionFacts(d Detection) []string {
+	facts := []string{fmt.Sprintf("KOL: %s · confidence %.0f%%", d.KOL, d.Confidence*100)}
+	if m := d.Market; m != nil {
+		facts = append(facts, "Price "+marketLine(m))
+	}
+	if d.Summary != "" {
+		facts = append(facts, d.Summary)
+	}
+	return facts
+}
+
+func marketLine(m *DetectionMarket) string {
+	return fmt.Sprintf("%s (%+.1f%% 24h) · mcap %s · hype %.0f · risk %.0f",
+		FormatPrice(m.PriceUSD), m.Change24h, FormatLargeUSD(m.MarketCapUSD), m.Hype, m.Risk)
+}
+
+// truncateText cuts s to at most max runes, marking the cut with an ellipsis.
+func truncateText(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
+
+// splitText breaks s into pieces of at most max runes, preferring line breaks.
+func splitText(s string, max int) []string {
+	var parts []string
+	for r := []rune(s); len(r) > 0; {
+		if len(r) <= max {
+			parts = append(parts, string(r))
+			break
+		}
+		cut := max
+		for i := max - 1; i > max/2; i-- {
+			if r[i] == '\n' {
+				cut = i + 1
+				break
+			}
+		}
+		parts = append(parts, string(r[:cut]))
+		r = r[cut:]
+	}
+	return parts
+}
+
+// PlainFormatter sends plain text as both "text" (Slack, Mattermost) and
+// "content" (Discord), the format digests have always used.
+type PlainFormatter struct{}
+
+// FormatDetection implements NotificationFormatter.
+func (PlainFormatter) FormatDetection(d Detection) any {
+	lines := append([]string{detectionTitle(d)}, detectionFacts(d)...)
+	if d.Text != "" {
+		lines = append(lines, "“"+d.Text+"”")
+	}
+	if d.Link != "" {
+		lines = append(lines, d.Link)
+	}
+	return plainPayload(strings.Join(lines, "\n"))
+}
+
+// FormatDigest implements NotificationFormatter.
+func (PlainFormatter) FormatDigest(report string) any {
+	return plainPayload(report)
+}
+
+func plainPayload(text string) map[string]string {
+	return map[string]string{"text": text, "content": truncateText(text, discordContentMax)}
+}
+
+// SlackFormatter renders Block Kit messages, with "text" as the notification
+// fallback.
+type SlackFormatter struct{}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackEscape escapes the characters Slack mrkdwn treats as control sequences.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// FormatDetection implements NotificationFormatter.
+func (SlackFormatter) FormatDetection(d Detection) any {
+	title := detectionTitle(d)
+	body := slackEscape(strings.Join(detectionFacts(d), "\n"))
+	if d.Text != "" {
+		body += "\n>" + slackEscape(strings.ReplaceAll(d.Text, "\n", "\n>"))
+	}
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: truncateText(title, 150)}},
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: truncateText(body, slackSectionMax)}},
+	}
+	if d.Link != "" {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: "<" + d.Link + "|source>"}}})
+	}
+	return slackMessage{Text: title, Blocks: blocks}
+}
+
+// FormatDigest implements NotificationFormatter.
+func (SlackFormatter) FormatDigest(report string) any {
+	msg := slackMessage{Text: truncateText(report, slackSectionMax)}
+	for _, part := range splitText(slackEscape(report), slackSectionMax) {
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: part}})
+	}
+	return msg
+}
+
+// DiscordFormatter renders detections as an embed and digests as one
+// description-only embed.
+type DiscordFormatter struct{}
+
+type discordField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+// FormatDetection implements NotificationFormatter.
+func (DiscordFormatter) FormatDetection(d Detection) any {
+	e := discordEmbed{
+		Title:       truncateText(detectionTitle(d), 256),
+		Description: truncateText(d.Text, discordEmbedMax),
+		URL:         d.Link,
+		Color:       discordColorAlert,
+	}
+	if d.Signal == "dump_warning" {
+		e.Color = discordColorWarn
+	}
+	kol := d.KOL
+	if kol == "" {
+		kol = "unknown" // Discord rejects empty field values
+	}
+	e.Fields = []discordField{{Name: "KOL", Value: kol}, {Name: "Confidence", Value: fmt.Sprintf("%.0f%%", d.Confidence*100)}}
+	if m := d.Market; m != nil {
+		e.Fields = append(e.Fields, discordField{Name: "Market", Value: marketLine(m)})
+	}
+	if d.Summary != "" {
+		e.Fields = append(e.Fields, discordField{Name: "AI take", Value: truncateText(d.Summary, 1024)})
+	}
+	if !d.Timestamp.IsZero() {
+		e.Timestamp = d.Timestamp.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	return discordMessage{Embeds: []discordEmbed{e}}
+}
+
+// FormatDigest implements NotificationFormatter.
+func (DiscordFormatter) FormatDigest(report string) any {
+	return discordMessage{Embeds: []discordEmbed{{Description: truncateText(report, discordEmbedMax)}}}
+}
+
+// TelegramFormatter renders Bot API sendMessage bodies in MarkdownV2. The
+// webhook URL is the bot's sendMessage endpoint with chat_id in the query,
+// e.g. https://api.telegram.org/bot<token>/sendMessage?chat_id=<id>.
+type TelegramFormatter struct{}
+
+type telegramMessage struct {
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// telegramEscape escapes every character MarkdownV2 reserves.
+var telegramEscape = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+).Replace
+
+// FormatDetection implements NotificationFormatter.
+func (TelegramFormatter) FormatDetection(d Detection) any {
+	lines := []string{"*" + telegramEscape(detectionTitle(d)) + "*"}
+	for _, f := range detectionFacts(d) {
+		lines = append(lines, telegramEscape(f))
+	}
+	if d.Text != "" {
+		lines = append(lines, ">"+telegramEscape(truncateText(d.Text, telegramMessageMax/2)))
+	}
+	if d.Link != "" {
+		lines = append(lines, "[source]("+strings.NewReplacer(`\`, `\\`, ")", `\)`).Replace(d.Link)+")")
+	}
+	return telegramMessage{Text: strings.Join(lines, "\n"), ParseMode: "MarkdownV2", DisableWebPagePreview: true}
+}
+
+// FormatDigest implements NotificationFormatter.
+func (TelegramFormatter) FormatDigest(report string) any {
+	// truncate before escaping so an escape sequence is never cut in half
+	text := telegramEscape(truncateText(report, telegramMessageMax/2))
+	return telegramMessage{Text: text, ParseMode: "MarkdownV2", DisableWebPagePreview: true}
+}
@@ -0,0 +1,52 @@
+package modules
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotificationFormatters(t *testing.T) {
+	d := Detection{KOL: "GCR", Token: "sol", Signal: "early_call", Confidence: 0.6,
+		Text: "$SOL to 500 (soon).", Link: "https://x.com/gcr/status/1", Timestamp: time.Unix(1700000000, 0)}
+
+	for name, want := range map[string]string{
+		"":         `"content":"🚨 early_call: $SOL`,
+		"slack":    `"type":"header"`,
+		"discord":  `"embeds":[{"title":"🚨 early_call: $SOL"`,
+		"telegram": `"parse_mode":"MarkdownV2"`,
+	} {
+		f, err := NotificationFormatterFor(name)
+		if err != nil {
+			t.Fatalf("%q: %v", name, err)
+		}
+		b, err := json.Marshal(f.FormatDetection(d))
+		if err != nil {
+			t.Fatalf("%q: marshal: %v", name, err)
+		}
+		if !strings.Contains(string(b), want) {
+			t.Errorf("%q detection payload = %s, want it to contain %s", name, b, want)
+		}
+	}
+
+	msg := TelegramFormatter{}.FormatDetection(d).(telegramMessage)
+	if !strings.Contains(msg.Text, `\(soon\)\.`) {
+		t.Errorf("telegram text not MarkdownV2-escaped: %q", msg.Text)
+	}
+
+	report := strings.Repeat("line of digest text\n", 400)
+	slack := SlackFormatter{}.FormatDigest(report).(slackMessage)
+	if len(slack.Blocks) < 2 {
+		t.Errorf("slack digest of %d chars in %d block(s), want it split", len(report), len(slack.Blocks))
+	}
+	for _, b := range slack.Blocks {
+		if n := len([]rune(b.Text.Text)); n > slackSectionMax {
+			t.Errorf("slack block has %d chars, max %d", n, slackSectionMax)
+		}
+	}
+
+	if _, err := NotificationFormatterFor("teams"); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
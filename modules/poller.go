@@ -0,0 +1,140 @@
+package modules
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Poller runs a function every interval until its context is canceled. A
+// failing run is logged and the next one is delayed with exponential backoff
+// (capped at the max backoff); the first success restores the interval. Run
+// has the func(ctx) error shape the goroutine supervisor expects.
+type Poller struct {
+	name       string
+	fn         func(ctx context.Context) error
+	jitter     float64
+	maxBackoff time.Duration
+
+	mu       sync.Mutex
+	interval time.Duration
+	changed  chan struct{}
+}
+
+// PollerOption configures optional Poller behavior.
+type PollerOption func(*Poller)
+
+// WithJitter spreads runs by up to ±frac of the interval (e.g. 0.1 for ±10%)
+// so pollers started together don't hit an API in lockstep.
+func WithJitter(frac float64) PollerOption {
+	return func(p *Poller) {
+		if frac > 0 && frac < 1 {
+			p.jitter = frac
+		}
+	}
+}
+
+// WithMaxBackoff caps the delay after consecutive failures (default 10x the interval).
+func WithMaxBackoff(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		if d > 0 {
+			p.maxBackoff = d
+		}
+	}
+}
+
+// NewPoller creates a poller named name (used in log lines) that calls fn
+// every interval.
+func NewPoller(name string, interval time.Duration, fn func(ctx context.Context) error, opts ...PollerOption) *Poller {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	p := &Poller{
+		name:     name,
+		fn:       fn,
+		interval: interval,
+		changed:  make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Interval returns the current polling interval.
+func (p *Poller) Interval() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.interval
+}
+
+// SetInterval changes the interval; a pending wait is restarted with it.
+func (p *Poller) SetInterval(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	p.mu.Lock()
+	p.interval = d
+	p.mu.Unlock()
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Run polls until ctx is canceled and then returns nil. The first run happens
+// one interval after Run is called.
+func (p *Poller) Run(ctx context.Context) error {
+	failures := 0
+	timer := time.NewTimer(p.delay(failures))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.changed:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(p.delay(failures))
+		case <-timer.C:
+			if err := p.fn(ctx); err != nil && ctx.Err() == nil {
+				failures++
+				next := p.delay(failures)
+				log.Printf("[%s] poll failed (%d in a row), retrying in %s: %v", p.name, failures, next.Round(time.Second), err)
+				timer.Reset(next)
+				continue
+			}
+			failures = 0
+			timer.Reset(p.delay(0))
+		}
+	}
+}
+
+// delay is the interval, doubled per consecutive failure up to the max
+// backoff, with jitter applied.
+func (p *Poller) delay(failures int) time.Duration {
+	p.mu.Lock()
+	d := p.interval
+	p.mu.Unlock()
+
+	if failures > 0 {
+		limit := p.maxBackoff
+		if limit <= 0 {
+			limit = 10 * d
+		}
+		for i := 0; i < failures && d < limit; i++ {
+			d *= 2
+		}
+		d = min(d, limit)
+	}
+	if p.jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.jitter * float64(d))
+	}
+	return d
+}
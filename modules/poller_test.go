@@ -0,0 +1,62 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPollerRunsUntilCanceled(t *testing.T) {
+	var runs atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPoller("test", 5*time.Millisecond, func(ctx context.Context) error {
+		if runs.Add(1) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("poller did not stop after cancel")
+	}
+	if n := runs.Load(); n != 3 {
+		t.Errorf("runs = %d, want 3", n)
+	}
+}
+
+func TestPollerBackoff(t *testing.T) {
+	p := NewPoller("test", time.Second, func(ctx context.Context) error { return errors.New("boom") }, WithMaxBackoff(5*time.Second))
+	for failures, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := p.delay(failures); got != want {
+			t.Errorf("delay(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
+
+func TestPollerSetInterval(t *testing.T) {
+	var runs atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := NewPoller("test", time.Hour, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	go p.Run(ctx)
+	p.SetInterval(5 * time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runs.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if runs.Load() == 0 {
+		t.Fatal("SetInterval did not restart the pending wait")
+	}
+}
@@ -0,0 +1,87 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ReplayResult is one historical detection re-scored by the current pipeline.
+type ReplayResult struct {
+	Detection Detection `json:"detection"`
+	// Passed reports whether the detection would get past today's scanner
+	// filter (SCANNER_MIN_CONFIDENCE).
+	Passed bool `json:"passed"`
+	// Summary is the current AI summary; nil when no AI key is configured.
+	Summary *DetectionSummary `json:"summary,omitempty"`
+	// PreviousSummary is the summary stored in the log at detection time.
+	PreviousSummary string    `json:"previous_summary,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	ReplayedAt      time.Time `json:"replayed_at"`
+}
+
+// DetectionSink receives replay results.
+type DetectionSink interface {
+	Write(r ReplayResult) error
+}
+
+// JSONLSink writes each result as one JSON line.
+type JSONLSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONLSink returns a sink writing JSON lines to w.
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{enc: json.NewEncoder(w)}
+}
+
+func (s *JSONLSink) Write(r ReplayResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(r)
+}
+
+// ReplayDetections re-runs every detection stored in filename through the
+// current filter and AI summarization and writes the results to sink. The log
+// is only read, never modified. Summarization failures are recorded on the
+// result rather than stopping the replay; sink errors and ctx cancellation stop it.
+func ReplayDetections(ctx context.Context, filename string, sink DetectionSink) error {
+	if _, err := os.Stat(filename); err != nil {
+		return err
+	}
+	dets, err := LoadDetections(filename, time.Time{})
+	if err != nil {
+		return err
+	}
+	summarize := cfg().AIConfigured()
+	minConf := scannerMinConfidence()
+
+	for _, d := range dets {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r := ReplayResult{
+			Detection:       d,
+			Passed:          d.Text != "" && d.Confidence >= minConf,
+			PreviousSummary: d.Summary,
+		}
+		r.Detection.Summary, r.Detection.SummarizedAt = "", time.Time{}
+		if summarize {
+			s, err := SummarizeDetectionStructured(d)
+			if err != nil {
+				r.Error = err.Error()
+			} else {
+				r.Summary = &s
+			}
+		}
+		r.ReplayedAt = TimeNowUTC()
+		if err := sink.Write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
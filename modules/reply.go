@@ -0,0 +1,32 @@
+package modules
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const truncatedMarker = "…(truncated)"
+
+// TruncateReply shortens s to at most max characters, cutting on a line or
+// word boundary and appending a "…(truncated)" marker. max <= 0 disables truncation.
+func TruncateReply(s string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	keep := max - utf8.RuneCountInString(truncatedMarker) - 1
+	if keep <= 0 {
+		// no room for any text: as much of the marker as fits
+		marker := []rune(truncatedMarker)
+		return string(marker[:min(max, len(marker))])
+	}
+	cut := string([]rune(s)[:keep])
+
+	// prefer a line break, then a space, as long as we don't throw away more than half
+	if i := strings.LastIndex(cut, "\n"); i > len(cut)/2 {
+		cut = cut[:i]
+	} else if i := strings.LastIndexAny(cut, " \t"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " \t\n") + " " + truncatedMarker
+}
@@ -0,0 +1,46 @@
+package modules
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateReply(t *testing.T) {
+	const marker = " " + truncatedMarker // 13 runes
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{"short reply is untouched", "hello", 10, "hello"},
+		{"exactly max is untouched", "hello", 5, "hello"},
+		{"zero disables", strings.Repeat("x", 50), 0, strings.Repeat("x", 50)},
+		{"negative disables", strings.Repeat("x", 50), -1, strings.Repeat("x", 50)},
+		{"max smaller than the marker", strings.Repeat("x", 50), 5, "…(tru"},
+		{"max equal to the marker", strings.Repeat("x", 50), 12, truncatedMarker},
+		{"no room for text", strings.Repeat("x", 50), 13, truncatedMarker},
+		{"hard cut without a boundary", strings.Repeat("x", 50), 23, strings.Repeat("x", 10) + marker},
+		{"prefers a line break over a later space", "first line\nsecond line\nthird line and more text", 42, "first line\nsecond line" + marker},
+		{"cuts on a space", "alpha beta gamma delta epsilon", 29, "alpha beta" + marker},
+		{"ignores a boundary in the first half", "ab cdefghijklmnopqrstuvwxyz", 23, "ab cdefghi" + marker},
+		{"counts runes, not bytes", strings.Repeat("ä", 30), 30, strings.Repeat("ä", 30)},
+		{"cuts multi-byte text on a rune", strings.Repeat("ä", 30), 20, strings.Repeat("ä", 7) + marker},
+		{"multi-byte words", "größe straße übermäßig", 21, "größe" + marker},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateReply(tt.in, tt.max)
+			if got != tt.want {
+				t.Errorf("TruncateReply(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("result is not valid UTF-8: %q", got)
+			}
+			if n := utf8.RuneCountInString(got); tt.max > 0 && n > tt.max {
+				t.Errorf("result is %d runes, over the max of %d", n, tt.max)
+			}
+		})
+	}
+}
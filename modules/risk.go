@@ -1,11 +1,12 @@
 package modules
 
 import (
+	"context"
 	"strings"
 )
 
 // RunRiskCheck is the public entry used by the agent to perform risk checks.
-func RunRiskCheck(args []string) (string, error) {
+func RunRiskCheck(ctx context.Context, args []string) (string, error) {
 	if len(args) == 0 {
 		return "Usage: riskcheck [token]. Example: riskcheck sol", nil
 	}
@@ -14,6 +15,5 @@ func RunRiskCheck(args []string) (string, error) {
 		return "Usage: riskcheck [token]. Example: riskcheck sol", nil
 	}
 
-	reply := BuildRiskReply(token)
-	return reply, nil
+	return BuildRiskReply(ctx, token)
 }
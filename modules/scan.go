@@ -1,16 +1,17 @@
 package modules
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
 
 // RunScan performs a simple mock scan for a token and returns a human-readable summary.
-func RunScan(args []string) (string, error) {
+func RunScan(ctx context.Context, args []string) (string, error) {
 	if len(args) == 0 {
 		return "Usage: scan [token]. Example: scan SOL", nil
 	}
-	token := strings.ToUpper(args[0])
+	token := strings.ToUpper(canonicalSymbol(args[0]))
 	// Mocked analysis
 	hype := 72
 	sentiment := "mixed"
@@ -0,0 +1,109 @@
+package modules
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// drop reasons counted in ScannerMetrics.Dropped
+const (
+	DropFiltered       = "filtered"        // nothing to emit (no KOLs, empty text)
+	DropLowConfidence  = "low_confidence"  // below SCANNER_MIN_CONFIDENCE
+	DropChannelFull    = "channel_full"    // detection consumer not keeping up
+	DropMarketCap      = "low_marketcap"   // token below SCANNER_MIN_MARKETCAP
+	DropInvalidCashtag = "invalid_cashtag" // cashtag didn't resolve (CASHTAG_GATE=strict)
+)
+
+// ScannerMetrics is a snapshot of the scanner counters since start.
+type ScannerMetrics struct {
+	Ticks    int64            `json:"ticks"`
+	Emitted  int64            `json:"emitted"`
+	Dropped  map[string]int64 `json:"dropped"`
+	ByKOL    map[string]int64 `json:"by_kol"`
+	ByToken  map[string]int64 `json:"by_token"`
+	BySource map[string]int64 `json:"by_source"`
+}
+
+// counterMap is a concurrency-safe set of named counters.
+type counterMap struct{ m sync.Map } // string -> *atomic.Int64
+
+func (c *counterMap) inc(key string) {
+	if key == "" {
+		key = "unknown"
+	}
+	v, ok := c.m.Load(key)
+	if !ok {
+		v, _ = c.m.LoadOrStore(key, new(atomic.Int64))
+	}
+	v.(*atomic.Int64).Add(1)
+}
+
+func (c *counterMap) snapshot() map[string]int64 {
+	out := map[string]int64{}
+	c.m.Range(func(k, v any) bool {
+		out[k.(string)] = v.(*atomic.Int64).Load()
+		return true
+	})
+	return out
+}
+
+var scannerMetrics struct {
+	ticks    atomic.Int64
+	emitted  atomic.Int64
+	dropped  counterMap
+	byKOL    counterMap
+	byToken  counterMap
+	bySource counterMap
+}
+
+func recordScannerTick() { scannerMetrics.ticks.Add(1) }
+
+func recordScannerDrop(reason string) { scannerMetrics.dropped.inc(reason) }
+
+func recordScannerEmit(d Detection) {
+	scannerMetrics.emitted.Add(1)
+	scannerMetrics.byKOL.inc(d.KOL)
+	scannerMetrics.byToken.inc(strings.ToUpper(d.Token))
+	scannerMetrics.bySource.inc(d.Source)
+}
+
+// GetScannerMetrics returns the current scanner counters.
+func GetScannerMetrics() ScannerMetrics {
+	return ScannerMetrics{
+		Ticks:    scannerMetrics.ticks.Load(),
+		Emitted:  scannerMetrics.emitted.Load(),
+		Dropped:  scannerMetrics.dropped.snapshot(),
+		ByKOL:    scannerMetrics.byKOL.snapshot(),
+		ByToken:  scannerMetrics.byToken.snapshot(),
+		BySource: scannerMetrics.bySource.snapshot(),
+	}
+}
+
+// scannerMinConfidence returns SCANNER_MIN_CONFIDENCE in [0..1] (default 0: keep everything).
+func scannerMinConfidence() float64 {
+	return cfg().ScannerMinConfidence
+}
+
+// emitDetection applies the confidence and market cap filters and hands d to out without
+// blocking the scanner, counting the outcome either way.
+func emitDetection(out chan<- Detection, d Detection) {
+	if d.Text == "" {
+		recordScannerDrop(DropFiltered)
+		return
+	}
+	if d.Confidence < scannerMinConfidence() {
+		recordScannerDrop(DropLowConfidence)
+		return
+	}
+	if belowMinMarketCap(d.Token, scannerMinMarketCap()) {
+		recordScannerDrop(DropMarketCap)
+		return
+	}
+	select {
+	case out <- d:
+		recordScannerEmit(d)
+	default:
+		recordScannerDrop(DropChannelFull)
+	}
+}
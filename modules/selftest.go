@@ -0,0 +1,176 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// selfTestTimeout bounds each check so one hung integration can't stall the report.
+const selfTestTimeout = 10 * time.Second
+
+// errSkipped marks a check that doesn't apply to this configuration.
+var errSkipped = errors.New("skipped")
+
+// CheckResult is one line of the self-test report.
+type CheckResult struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"` // "pass", "fail" or "skip"
+	Detail   string        `json:"detail"`
+	Duration time.Duration `json:"-"`
+	Millis   int64         `json:"duration_ms"`
+}
+
+// selfTestCheck is a named integration probe; it returns a short detail line.
+type selfTestCheck struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+}
+
+var selfTestChecks = []selfTestCheck{
+	{"coingecko", checkCoinGecko},
+	{"ai", checkAI},
+	{"cache", checkCache},
+	{"auth", checkAuthKey},
+	{"nft-backend", checkNFTBackend},
+}
+
+// SelfTest runs every integration check concurrently and returns the results
+// in a fixed order.
+func SelfTest(ctx context.Context) []CheckResult {
+	results := make([]CheckResult, len(selfTestChecks))
+	var wg sync.WaitGroup
+	for i, c := range selfTestChecks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+			defer cancel()
+			start := time.Now()
+			detail, err := c.run(cctx)
+			r := CheckResult{Name: c.name, Status: "pass", Detail: detail, Duration: time.Since(start)}
+			switch {
+			case errors.Is(err, errSkipped):
+				r.Status = "skip"
+			case err != nil:
+				r.Status, r.Detail = "fail", err.Error()
+			}
+			r.Millis = r.Duration.Milliseconds()
+			results[i] = r
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// checkCoinGecko fetches BTC live, bypassing the market-data cache.
+func checkCoinGecko(ctx context.Context) (string, error) {
+	if cfg().MockMode {
+		return "mock mode, not called", errSkipped
+	}
+	resp, err := doCoinGecko(ctx, newHTTPClient(selfTestTimeout), coinGeckoBaseURL()+"/simple/price?ids=bitcoin&vs_currencies=usd")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp.StatusCode, "coingecko status %d", resp.StatusCode)
+	}
+	var body map[string]map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("coingecko decode err: %w", err)
+	}
+	price := body["bitcoin"]["usd"]
+	if price <= 0 {
+		return "", fmt.Errorf("coingecko returned no BTC price")
+	}
+	return "BTC " + FormatPrice(price), nil
+}
+
+// checkAI validates every configured provider key; it fails if any key is
+// rejected, naming the provider.
+func checkAI(ctx context.Context) (string, error) {
+	if !cfg().AIConfigured() {
+		return "no GOOGLE_API_KEY or OPENAI_API_KEY", errSkipped
+	}
+	results := ValidateAIKeysContext(ctx)
+	providers := slices.Sorted(maps.Keys(results))
+	var ok, failed []string
+	for _, p := range providers {
+		if err := results[p]; err != nil {
+			failed = append(failed, p+": "+summarizeErr(err))
+		} else {
+			ok = append(ok, p)
+		}
+	}
+	if len(failed) > 0 {
+		return "", errors.New(strings.Join(failed, "; "))
+	}
+	return "keys valid: " + strings.Join(ok, ", "), nil
+}
+
+// checkCache pings the store behind the AI reply cache.
+func checkCache(ctx context.Context) (string, error) {
+	c := currentAICache()
+	if err := c.Ping(ctx); err != nil {
+		return "", fmt.Errorf("cache ping: %w", err)
+	}
+	return fmt.Sprintf("%T reachable", c), nil
+}
+
+// checkAuthKey derives the agent address from PRIVATE_KEY and compares it with
+// OWNER_ADDRESS when that is set.
+func checkAuthKey(ctx context.Context) (string, error) {
+	c := cfg()
+	raw := strings.TrimPrefix(c.PrivateKey, "0x")
+	if raw == "" {
+		return "", fmt.Errorf("PRIVATE_KEY is not set")
+	}
+	key, err := crypto.HexToECDSA(raw)
+	if err != nil {
+		return "", fmt.Errorf("PRIVATE_KEY is not a valid secp256k1 key: %w", err)
+	}
+	addr := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	owner := c.OwnerAddress
+	if owner != "" && !strings.EqualFold(owner, addr) {
+		return "address " + addr + " (differs from OWNER_ADDRESS " + owner + ")", nil
+	}
+	return "address " + addr, nil
+}
+
+// checkNFTBackend fetches the contract config the NFT minter starts from
+// (BACKEND_URL, default http://localhost:8080).
+func checkNFTBackend(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", cfg().BackendURL+"/api/contract/config", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := newHTTPClient(selfTestTimeout).Do(req)
+	if err != nil {
+		return "", networkError("nft backend http err: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp.StatusCode, "nft backend status %d", resp.StatusCode)
+	}
+	var cfg struct {
+		ContractAddress string `json:"contract_address"`
+		ChainID         string `json:"chain_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return "", fmt.Errorf("nft backend decode err: %w", err)
+	}
+	if cfg.ContractAddress == "" {
+		return "", fmt.Errorf("nft backend returned no contract address")
+	}
+	return fmt.Sprintf("contract %s on chain %s", cfg.ContractAddress, cfg.ChainID), nil
+}
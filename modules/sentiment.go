@@ -1,19 +1,70 @@
 package modules
 
 import (
+	"context"
+	"fmt"
+	"sort"
 	"strings"
 )
 
 // RunSentiment provides the public entry used by the agent to return sentiment.
-func RunSentiment(args []string) (string, error) {
-	if len(args) == 0 {
-		return "Usage: sentiment [token]", nil
+// "sentiment all" (or no args) ranks the whole watchlist.
+func RunSentiment(ctx context.Context, args []string) (string, error) {
+	if len(args) == 0 || strings.EqualFold(strings.TrimSpace(args[0]), "all") {
+		return BuildWatchlistSentimentReply(ctx, Watchlist())
 	}
 	token := strings.TrimSpace(args[0])
 	if token == "" {
-		return "Usage: sentiment [token]", nil
+		return "Usage: sentiment [token|all]", nil
 	}
 
-	reply := BuildSentimentReply(token)
-	return reply, nil
+	return BuildSentimentReply(ctx, token)
+}
+
+// BuildWatchlistSentimentReply ranks tokens from most positive to most negative
+// using one batch market fetch. It fails only when no token has data.
+func BuildWatchlistSentimentReply(ctx context.Context, tokens []string) (string, error) {
+	if len(tokens) == 0 {
+		return "Sentiment overview: watchlist is empty (set WATCHLIST)", nil
+	}
+	if cfg().MockMode {
+		var b strings.Builder
+		b.WriteString("Sentiment overview (mock):\n")
+		for i, t := range tokens {
+			fmt.Fprintf(&b, "%d. $%s 👍 0.0%% / 👎 0.0%%\n", i+1, strings.ToUpper(t))
+		}
+		return b.String(), nil
+	}
+
+	data, err := GetMarketDataBatchContext(ctx, tokens, BatchOptions{})
+	if err != nil && len(data) == 0 {
+		return "", fmt.Errorf("sentiment overview: %w", err)
+	}
+
+	type row struct {
+		token  string
+		change float64
+	}
+	rows := []row{}
+	missing := []string{}
+	for _, t := range tokens {
+		md, ok := data[strings.ToLower(t)]
+		if !ok {
+			missing = append(missing, strings.ToUpper(t))
+			continue
+		}
+		rows = append(rows, row{token: strings.ToUpper(t), change: md.Change24h})
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].change > rows[j].change })
+
+	var b strings.Builder
+	b.WriteString("Sentiment overview (most positive first):\n")
+	for i, r := range rows {
+		pos, neg := sentimentSplit(r.change)
+		fmt.Fprintf(&b, "%d. $%s 👍 %.1f%% / 👎 %.1f%% (24h: %+0.2f%%)\n", i+1, r.token, pos, neg, r.change)
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(&b, "No data: %s\n", strings.Join(missing, ", "))
+	}
+	return b.String(), nil
 }
@@ -0,0 +1,179 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultSignalWindow = 6 * time.Hour
+
+// AggregatedSignal combines the recent detections of one token.
+type AggregatedSignal struct {
+	Token         string    `json:"token"`
+	Detections    int       `json:"detections"`
+	KOLs          []string  `json:"kols"`
+	Sources       []string  `json:"sources"`
+	MaxConfidence float64   `json:"max_confidence"`
+	Confidence    float64   `json:"confidence"` // combined, >= MaxConfidence
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+}
+
+// CorrelateSignals groups detections for token within window from the
+// detection log and combines them into one signal.
+func CorrelateSignals(token string, window time.Duration) (AggregatedSignal, error) {
+	if window <= 0 {
+		window = defaultSignalWindow
+	}
+	dets, err := LoadKOLMentions(DetectionLogPath(), TimeNowUTC().Add(-window))
+	if err != nil {
+		return AggregatedSignal{}, err
+	}
+	return aggregateSignal(token, dets), nil
+}
+
+// aggregateSignal combines detections of token. Independent corroboration is
+// merged noisy-OR style: each distinct KOL/source contributes its best
+// confidence c and the result is 1 - Π(1-c), so agreement raises confidence
+// above any single detection while repeats from the same KOL don't. The
+// agent's own monitor and alert events are not corroboration and are skipped.
+func aggregateSignal(token string, dets []Detection) AggregatedSignal {
+	agg := AggregatedSignal{Token: strings.ToUpper(token)}
+	best := map[string]float64{} // per KOL (or source when KOL is unknown)
+	kols := map[string]bool{}
+	sources := map[string]bool{}
+
+	for _, d := range dets {
+		if !strings.EqualFold(d.Token, token) || !d.IsKOLMention() {
+			continue
+		}
+		agg.Detections++
+		if agg.FirstSeen.IsZero() || d.Timestamp.Before(agg.FirstSeen) {
+			agg.FirstSeen = d.Timestamp
+		}
+		if d.Timestamp.After(agg.LastSeen) {
+			agg.LastSeen = d.Timestamp
+		}
+		if d.Confidence > agg.MaxConfidence {
+			agg.MaxConfidence = d.Confidence
+		}
+		if d.KOL != "" {
+			kols[d.KOL] = true
+		}
+		if d.Source != "" {
+			sources[d.Source] = true
+		}
+
+		origin := "kol:" + strings.ToLower(d.KOL)
+		if d.KOL == "" {
+			origin = "source:" + strings.ToLower(d.Source)
+		}
+		c := clamp01(d.Confidence)
+		if c > best[origin] {
+			best[origin] = c
+		}
+	}
+
+	miss := 1.0
+	for _, c := range best {
+		miss *= 1 - c
+	}
+	if agg.Detections > 0 {
+		// max() keeps float rounding from dipping below the best single detection
+		agg.Confidence = max(1-miss, clamp01(agg.MaxConfidence))
+	}
+	agg.KOLs = sortedKeys(kols)
+	agg.Sources = sortedKeys(sources)
+	return agg
+}
+
+// RecentSignals correlates every token seen within window, strongest first.
+func RecentSignals(window time.Duration) ([]AggregatedSignal, error) {
+	if window <= 0 {
+		window = defaultSignalWindow
+	}
+	dets, err := LoadKOLMentions(DetectionLogPath(), TimeNowUTC().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	tokens := map[string]bool{}
+	for _, d := range dets {
+		if d.Token != "" {
+			tokens[strings.ToUpper(d.Token)] = true
+		}
+	}
+	out := make([]AggregatedSignal, 0, len(tokens))
+	for tok := range tokens {
+		out = append(out, aggregateSignal(tok, dets))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Confidence > out[j].Confidence })
+	return out, nil
+}
+
+// RunSignal implements "signal [token]".
+func RunSignal(ctx context.Context, args []string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
+		agg, err := CorrelateSignals(args[0], defaultSignalWindow)
+		if err != nil {
+			return "", err
+		}
+		if agg.Detections == 0 {
+			return fmt.Sprintf("No signals for $%s in the last %s.", agg.Token, defaultSignalWindow), nil
+		}
+		return formatAggregatedSignal(agg), nil
+	}
+
+	signals, err := RecentSignals(defaultSignalWindow)
+	if err != nil {
+		return "", err
+	}
+	if len(signals) == 0 {
+		return fmt.Sprintf("No signals in the last %s.", defaultSignalWindow), nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Latest signals (%s):\n", defaultSignalWindow)
+	for i, s := range signals {
+		if i == topCallsLimit {
+			break
+		}
+		fmt.Fprintf(&b, "%d. $%s – confidence %.2f (%d detections, %d KOLs, %d sources)\n",
+			i+1, s.Token, s.Confidence, s.Detections, len(s.KOLs), len(s.Sources))
+	}
+	return b.String(), nil
+}
+
+func formatAggregatedSignal(s AggregatedSignal) string {
+	kols := "none"
+	if len(s.KOLs) > 0 {
+		kols = strings.Join(s.KOLs, ", ")
+	}
+	return fmt.Sprintf("Signal for $%s:\n- Combined confidence: %.2f (best single: %.2f)\n- Detections: %d\n- KOLs (%d): %s\n- Sources: %s\n- Window: %s → %s",
+		s.Token, s.Confidence, s.MaxConfidence, s.Detections, len(s.KOLs), kols,
+		strings.Join(s.Sources, ", "),
+		FormatReplyTime(s.FirstSeen), FormatReplyTime(s.LastSeen))
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
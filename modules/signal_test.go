@@ -0,0 +1,101 @@
+package modules
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAggregateSignal(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	det := func(kol, source, token string, conf float64, minutes int) Detection {
+		return Detection{KOL: kol, Source: source, Token: token, Confidence: conf, Timestamp: t0.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	tests := []struct {
+		name        string
+		dets        []Detection
+		wantCount   int
+		wantConf    float64
+		wantKOLs    []string
+		wantSources []string
+	}{
+		{
+			name: "no detections",
+		},
+		{
+			name:        "single KOL",
+			dets:        []Detection{det("ansem", "x", "SOL", 0.6, 0)},
+			wantCount:   1,
+			wantConf:    0.6,
+			wantKOLs:    []string{"ansem"},
+			wantSources: []string{"x"},
+		},
+		{
+			name:        "independent KOLs combine noisy-OR",
+			dets:        []Detection{det("ansem", "x", "SOL", 0.5, 0), det("cobie", "x", "sol", 0.5, 5)},
+			wantCount:   2,
+			wantConf:    0.75,
+			wantKOLs:    []string{"ansem", "cobie"},
+			wantSources: []string{"x"},
+		},
+		{
+			name:        "repeats from one KOL count once at their best",
+			dets:        []Detection{det("ansem", "x", "SOL", 0.4, 0), det("Ansem", "x", "SOL", 0.6, 5)},
+			wantCount:   2,
+			wantConf:    0.6,
+			wantKOLs:    []string{"Ansem", "ansem"},
+			wantSources: []string{"x"},
+		},
+		{
+			name:        "KOL-less detections group by source",
+			dets:        []Detection{det("", "coingecko", "SOL", 0.5, 0), det("", "coingecko", "SOL", 0.3, 5), det("ansem", "x", "SOL", 0.5, 10)},
+			wantCount:   3,
+			wantConf:    0.75,
+			wantKOLs:    []string{"ansem"},
+			wantSources: []string{"coingecko", "x"},
+		},
+		{
+			name: "alerts and monitors are not corroboration",
+			dets: []Detection{
+				det("ansem", "x", "SOL", 0.5, 0),
+				det("", SourceAlert, "SOL", 1, 5),
+				det("", SourceMonitor, "SOL", 0.9, 10),
+			},
+			wantCount:   1,
+			wantConf:    0.5,
+			wantKOLs:    []string{"ansem"},
+			wantSources: []string{"x"},
+		},
+		{
+			name: "only agent events means no signal",
+			dets: []Detection{det("", SourceAlert, "SOL", 1, 0)},
+		},
+		{
+			name:        "other tokens are ignored",
+			dets:        []Detection{det("ansem", "x", "ETH", 0.9, 0), det("cobie", "x", "SOL", 0.2, 0)},
+			wantCount:   1,
+			wantConf:    0.2,
+			wantKOLs:    []string{"cobie"},
+			wantSources: []string{"x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := aggregateSignal("sol", tt.dets)
+			if got.Token != "SOL" || got.Detections != tt.wantCount {
+				t.Errorf("token %q, detections %d; want SOL, %d", got.Token, got.Detections, tt.wantCount)
+			}
+			if math.Abs(got.Confidence-tt.wantConf) > 1e-9 {
+				t.Errorf("confidence = %v, want %v", got.Confidence, tt.wantConf)
+			}
+			if got.Confidence < got.MaxConfidence {
+				t.Errorf("confidence %v below max %v", got.Confidence, got.MaxConfidence)
+			}
+			if !slices.Equal(got.KOLs, tt.wantKOLs) || !slices.Equal(got.Sources, tt.wantSources) {
+				t.Errorf("KOLs %v sources %v; want %v %v", got.KOLs, got.Sources, tt.wantKOLs, tt.wantSources)
+			}
+		})
+	}
+}
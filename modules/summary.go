@@ -1,5 +1,41 @@
 package modules
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const summaryTakes = 3
+
 func RunSummary() (string, error) {
-	return "Daily summary: 5 signals detected, 2 risky tokens, 3 trending coins (mock).", nil
+	dets, err := LoadKOLMentions(DetectionLogPath(), TimeNowUTC().Add(-24*time.Hour))
+	if err != nil || len(dets) == 0 {
+		return "Daily summary: 5 signals detected, 2 risky tokens, 3 trending coins (mock).", nil
+	}
+
+	tokens := map[string]bool{}
+	kols := map[string]bool{}
+	for _, d := range dets {
+		tokens[strings.ToUpper(d.Token)] = true
+		if d.KOL != "" {
+			kols[d.KOL] = true
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Daily summary: %d signals detected across %d tokens from %d KOLs.", len(dets), len(tokens), len(kols))
+
+	// latest AI takes, newest first, without re-calling the model
+	takes := 0
+	for i := len(dets) - 1; i >= 0 && takes < summaryTakes; i-- {
+		if d := dets[i]; d.Summary != "" {
+			if takes == 0 {
+				b.WriteString("\nLatest AI takes:")
+			}
+			fmt.Fprintf(&b, "\n- $%s (%s): %s", strings.ToUpper(d.Token), d.KOL, d.Summary)
+			takes++
+		}
+	}
+	return b.String(), nil
 }
@@ -0,0 +1,213 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Full names and common spellings -> ticker, for users who type "solana" or
+// "polygon" instead of the ticker. Keys must be lowercase.
+var cgNameToSymbol = map[string]string{
+	"bitcoin":       "btc",
+	"ethereum":      "eth",
+	"ether":         "eth",
+	"binance":       "bnb",
+	"binancecoin":   "bnb",
+	"solana":        "sol",
+	"polygon":       "matic",
+	"matic-network": "matic",
+	"cardano":       "ada",
+	"dogecoin":      "doge",
+	"tether":        "usdt",
+	"usd-coin":      "usdc",
+	"usdcoin":       "usdc",
+	"litecoin":      "ltc",
+	"avalanche":     "avax",
+	"avalanche-2":   "avax",
+	"polkadot":      "dot",
+	"chainlink":     "link",
+	"shiba":         "shib",
+	"shiba-inu":     "shib",
+	"shibainu":      "shib",
+	"uniswap":       "uni",
+	"fantom":        "ftm",
+	"cosmos":        "atom",
+	"optimism":      "op",
+	"arbitrum":      "arb",
+}
+
+// Pegged assets: momentum, hype and trend are meaningless for them. Keys are
+// lowercase tickers; STABLECOINS (comma separated) adds more.
+var stablecoins = map[string]bool{
+	"usdt":  true,
+	"usdc":  true,
+	"dai":   true,
+	"busd":  true,
+	"tusd":  true,
+	"fdusd": true,
+	"pyusd": true,
+	"usde":  true,
+}
+
+// IsStablecoin reports whether symbol (any form ResolveSymbol accepts) is a
+// known stablecoin or listed in STABLECOINS.
+func IsStablecoin(symbol string) bool {
+	sym := canonicalSymbol(symbol)
+	if stablecoins[sym] {
+		return true
+	}
+	return slices.Contains(cfg().Stablecoins, sym)
+}
+
+// coinGeckoIDRe matches strings that could be a CoinGecko coin id.
+var coinGeckoIDRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+const maxSymbolSuggestions = 3
+
+// UnknownSymbolError reports a token that could not be resolved, with the
+// closest known tickers as suggestions. It matches ErrNotFound with errors.Is.
+type UnknownSymbolError struct {
+	Input       string
+	Suggestions []string // uppercase tickers
+	Err         error    // underlying lookup error, if any
+}
+
+func (e *UnknownSymbolError) Error() string {
+	msg := fmt.Sprintf("unknown token %q", e.Input)
+	if len(e.Suggestions) > 0 {
+		msg += "; did you mean " + strings.Join(e.Suggestions, ", ") + "?"
+	}
+	return msg
+}
+
+func (e *UnknownSymbolError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrNotFound) true for unknown tokens.
+func (e *UnknownSymbolError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ResolveSymbol normalizes user input ("$sol", "SOL", "solana") to a lowercase
+// ticker and its CoinGecko id. Inputs that are neither a known ticker nor a
+// known name but look like a CoinGecko id are passed through as the id (e.g.
+// "pepe"); anything else is an *UnknownSymbolError with suggestions.
+func ResolveSymbol(input string) (symbol, coingeckoID string, err error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	s = strings.TrimSpace(strings.TrimLeft(s, "$"))
+	if s == "" {
+		return "", "", &UnknownSymbolError{Input: input}
+	}
+	if id, ok := cgSymbolToID[s]; ok {
+		return s, id, nil
+	}
+	if sym, ok := cgNameToSymbol[s]; ok {
+		return sym, cgSymbolToID[sym], nil
+	}
+	if coinGeckoIDRe.MatchString(s) {
+		return s, s, nil
+	}
+	return "", "", &UnknownSymbolError{Input: input, Suggestions: suggestSymbols(s)}
+}
+
+// canonicalSymbol is the ResolveSymbol ticker, or the trimmed lowercase input
+// when it can't be resolved (the market layer then reports the error).
+func canonicalSymbol(input string) string {
+	if sym, _, err := ResolveSymbol(input); err == nil {
+		return sym
+	}
+	return strings.ToLower(strings.TrimSpace(input))
+}
+
+// isKnownSymbol reports whether sym is one of the mapped tickers.
+func isKnownSymbol(sym string) bool {
+	_, ok := cgSymbolToID[sym]
+	return ok
+}
+
+// unknownSymbolOnNotFound turns a 404 for an unmapped symbol into an
+// *UnknownSymbolError with suggestions; other errors are returned unchanged.
+func unknownSymbolOnNotFound(sym string, err error) error {
+	if err == nil || isKnownSymbol(sym) || !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return &UnknownSymbolError{Input: sym, Suggestions: suggestSymbols(sym), Err: err}
+}
+
+// suggestSymbols returns up to three known tickers whose ticker or name is
+// close to s (one or two typos, or a shared prefix), best match first.
+func suggestSymbols(s string) []string {
+	type cand struct {
+		sym  string
+		dist int
+	}
+	// short inputs only tolerate one typo, or everything looks alike
+	maxDist := 2
+	if len(s) <= 4 {
+		maxDist = 1
+	}
+	best := map[string]int{}
+	consider := func(key, sym string) {
+		d := editDistance(s, key)
+		if len(s) >= 3 && (strings.HasPrefix(key, s) || strings.HasPrefix(s, key)) && len(key) >= 3 {
+			d = min(d, 1)
+		}
+		if d > maxDist {
+			return
+		}
+		if prev, ok := best[sym]; !ok || d < prev {
+			best[sym] = d
+		}
+	}
+	for sym := range cgSymbolToID {
+		consider(sym, sym)
+	}
+	for name, sym := range cgNameToSymbol {
+		consider(name, sym)
+	}
+
+	cands := make([]cand, 0, len(best))
+	for sym, d := range best {
+		cands = append(cands, cand{sym, d})
+	}
+	sort.Slice(cands, func(i, j int) bool {
+		if cands[i].dist != cands[j].dist {
+			return cands[i].dist < cands[j].dist
+		}
+		return cands[i].sym < cands[j].sym
+	})
+	out := make([]string, 0, maxSymbolSuggestions)
+	for _, c := range cands {
+		if len(out) == maxSymbolSuggestions {
+			break
+		}
+		out = append(out, strings.ToUpper(c.sym))
+	}
+	return out
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
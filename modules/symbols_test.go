@@ -0,0 +1,70 @@
+package modules
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestResolveSymbol(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantSym string
+		wantID  string
+	}{
+		{"SOL", "sol", "solana"},
+		{"$sol", "sol", "solana"},
+		{" $SOL ", "sol", "solana"},
+		{"solana", "sol", "solana"},
+		{"Polygon", "matic", "matic-network"},
+		{"pepe", "pepe", "pepe"}, // passed through as a CoinGecko id
+	}
+	for _, tt := range tests {
+		sym, id, err := ResolveSymbol(tt.input)
+		if err != nil {
+			t.Errorf("ResolveSymbol(%q) error: %v", tt.input, err)
+			continue
+		}
+		if sym != tt.wantSym || id != tt.wantID {
+			t.Errorf("ResolveSymbol(%q) = %q, %q; want %q, %q", tt.input, sym, id, tt.wantSym, tt.wantID)
+		}
+	}
+}
+
+func TestResolveSymbolUnknown(t *testing.T) {
+	for _, input := range []string{"", "$", "sol ana", "s@l"} {
+		_, _, err := ResolveSymbol(input)
+		var ue *UnknownSymbolError
+		if !errors.As(err, &ue) {
+			t.Errorf("ResolveSymbol(%q) error = %v, want *UnknownSymbolError", input, err)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("ResolveSymbol(%q) error should match ErrNotFound", input)
+		}
+	}
+}
+
+func TestSuggestSymbols(t *testing.T) {
+	if got := suggestSymbols("solanna"); !slices.Contains(got, "SOL") {
+		t.Errorf("suggestSymbols(solanna) = %v, want SOL among them", got)
+	}
+	if got := suggestSymbols("etherium"); !slices.Contains(got, "ETH") {
+		t.Errorf("suggestSymbols(etherium) = %v, want ETH among them", got)
+	}
+	if got := suggestSymbols("zzzzzz"); len(got) != 0 {
+		t.Errorf("suggestSymbols(zzzzzz) = %v, want none", got)
+	}
+}
+
+func TestUnknownSymbolOnNotFound(t *testing.T) {
+	newCoinGeckoMock(t)
+
+	_, err := GetMarketData("missingcoin")
+	var ue *UnknownSymbolError
+	if !errors.As(err, &ue) {
+		t.Fatalf("GetMarketData(missingcoin) error = %v, want *UnknownSymbolError", err)
+	}
+	if msg := UserFacingError(err); msg == "" {
+		t.Error("UserFacingError returned empty message")
+	}
+}
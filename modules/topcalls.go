@@ -1,12 +1,79 @@
 package modules
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const topCallsLimit = 5
 
 func RunTopCalls() (string, error) {
-	return fmt.Sprintf(
-`Latest KOL Early Calls:
+	dets, err := LoadKOLMentions(DetectionLogPath(), TimeNowUTC().Add(-24*time.Hour))
+	if err != nil || len(dets) == 0 {
+		return fmt.Sprintf(
+			`Latest KOL Early Calls:
 1. SOL – Mentioned by Ansem
 2. ETH – Mentioned by SatoshiLite
 3. DOGE – Mentioned by TheMoonCarl
-`, ), nil
+`), nil
+	}
+
+	// group last 24h detections per token, remembering the latest KOL
+	type call struct {
+		token    string
+		mentions int
+		lastKOL  string
+		lastSeen time.Time
+		summary  string // latest AI take, if any
+	}
+	byToken := map[string]*call{}
+	for _, d := range dets {
+		tok := strings.ToUpper(d.Token)
+		if tok == "" {
+			continue
+		}
+		c, ok := byToken[tok]
+		if !ok {
+			c = &call{token: tok}
+			byToken[tok] = c
+		}
+		c.mentions++
+		if !d.Timestamp.Before(c.lastSeen) {
+			c.lastSeen = d.Timestamp
+			c.lastKOL = d.KOL
+			if d.Summary != "" {
+				c.summary = d.Summary
+			}
+		}
+	}
+	calls := make([]*call, 0, len(byToken))
+	for _, c := range byToken {
+		calls = append(calls, c)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		if calls[i].mentions != calls[j].mentions {
+			return calls[i].mentions > calls[j].mentions
+		}
+		return calls[i].lastSeen.After(calls[j].lastSeen)
+	})
+	if len(calls) > topCallsLimit {
+		calls = calls[:topCallsLimit]
+	}
+
+	now := TimeNowUTC()
+	var b strings.Builder
+	b.WriteString("Latest KOL Early Calls (24h):\n")
+	for i, c := range calls {
+		fmt.Fprintf(&b, "%d. %s – %d mentions, latest by %s", i+1, c.token, c.mentions, c.lastKOL)
+		if cur, _, ratio := mentionVelocity(dets, c.token, time.Hour, now); cur > 0 {
+			fmt.Fprintf(&b, " • mention velocity %.1fx baseline", ratio)
+		}
+		b.WriteString("\n")
+		if c.summary != "" {
+			fmt.Fprintf(&b, "   ↳ AI: %s\n", c.summary)
+		}
+	}
+	return b.String(), nil
 }
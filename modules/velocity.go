@@ -0,0 +1,63 @@
+package modules
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// baselineWindows is how many windows before the current one form the baseline.
+const baselineWindows = 6
+
+// ComputeMentionVelocity compares the mention rate of token in the last window
+// against the preceding baselineWindows windows of the detection log.
+// current and baseline are mentions per hour; ratio is current/baseline.
+// With no baseline mentions, the baseline is treated as one mention over the
+// baseline span so a fresh burst still shows up as a spike.
+func ComputeMentionVelocity(token string, window time.Duration) (current, baseline, ratio float64) {
+	if window <= 0 {
+		window = time.Hour
+	}
+	now := TimeNowUTC()
+	start := now.Add(-window * (baselineWindows + 1))
+	dets, err := LoadKOLMentions(DetectionLogPath(), start)
+	if err != nil {
+		return 0, 0, 0
+	}
+	return mentionVelocity(dets, token, window, now)
+}
+
+func mentionVelocity(dets []Detection, token string, window time.Duration, now time.Time) (current, baseline, ratio float64) {
+	cutoff := now.Add(-window)
+	var cur, base int
+	for _, d := range dets {
+		if !strings.EqualFold(d.Token, token) {
+			continue
+		}
+		if d.Timestamp.After(cutoff) {
+			cur++
+		} else {
+			base++
+		}
+	}
+
+	hours := window.Hours()
+	current = float64(cur) / hours
+	baseline = float64(base) / (hours * baselineWindows)
+	if cur == 0 {
+		return current, baseline, 0
+	}
+	denom := baseline
+	if denom == 0 {
+		denom = 1 / (hours * baselineWindows)
+	}
+	return current, baseline, current / denom
+}
+
+// formatVelocity renders a velocity line, or "" when there were no recent mentions.
+func formatVelocity(current, ratio float64) string {
+	if current == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Mention velocity: %.1f/h (%.1fx baseline)", current, ratio)
+}
@@ -0,0 +1,77 @@
+package modules
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMentionVelocity(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// mentions of SOL at the given minutes before now
+	at := func(minutes ...int) []Detection {
+		var dets []Detection
+		for _, m := range minutes {
+			dets = append(dets, Detection{Token: "SOL", Timestamp: now.Add(-time.Duration(m) * time.Minute)})
+		}
+		return dets
+	}
+
+	tests := []struct {
+		name                         string
+		dets                         []Detection
+		window                       time.Duration
+		wantCur, wantBase, wantRatio float64
+	}{
+		{name: "no mentions", window: time.Hour},
+		{
+			name:    "steady chatter is 1x",
+			dets:    at(10, 70, 130, 190, 250, 310, 370),
+			window:  time.Hour,
+			wantCur: 1, wantBase: 1, wantRatio: 1,
+		},
+		{
+			name:    "burst over a quiet baseline",
+			dets:    at(5, 10, 15, 20, 200, 300),
+			window:  time.Hour,
+			wantCur: 4, wantBase: 2.0 / 6, wantRatio: 12,
+		},
+		{
+			name:    "zero baseline counts as one mention over the span",
+			dets:    at(5, 10, 15),
+			window:  time.Hour,
+			wantCur: 3, wantBase: 0, wantRatio: 18,
+		},
+		{
+			name:    "baseline only has no velocity",
+			dets:    at(90, 200),
+			window:  time.Hour,
+			wantCur: 0, wantBase: 2.0 / 6, wantRatio: 0,
+		},
+		{
+			name:    "rates are per hour for other windows",
+			dets:    at(5, 10, 40, 100),
+			window:  30 * time.Minute,
+			wantCur: 4, wantBase: 2.0 / 3, wantRatio: 6,
+		},
+		{
+			name:    "other tokens are ignored, case-insensitively matched",
+			dets:    append(at(5), Detection{Token: "sol", Timestamp: now.Add(-time.Minute)}, Detection{Token: "ETH", Timestamp: now}),
+			window:  time.Hour,
+			wantCur: 2, wantBase: 0, wantRatio: 12,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur, base, ratio := mentionVelocity(tt.dets, "SOL", tt.window, now)
+			for _, v := range []struct {
+				what      string
+				got, want float64
+			}{{"current", cur, tt.wantCur}, {"baseline", base, tt.wantBase}, {"ratio", ratio, tt.wantRatio}} {
+				if math.Abs(v.got-v.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", v.what, v.got, v.want)
+				}
+			}
+		})
+	}
+}
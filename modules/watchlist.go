@@ -0,0 +1,9 @@
+package modules
+
+var defaultWatchlist = []string{"BTC", "ETH", "SOL"}
+
+// Watchlist returns the tokens from WATCHLIST (comma separated), uppercased,
+// or a default of BTC, ETH, SOL.
+func Watchlist() []string {
+	return cfg().Watchlist
+}
@@ -0,0 +1,419 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrXRateLimited is wrapped by errors for X API 429 responses that could not
+// be waited out (context canceled or past its deadline).
+var ErrXRateLimited = errors.New("x rate limited")
+
+const (
+	defaultXBaseURL = "https://api.twitter.com/2"
+	// xWindow and defaultXWindowLimit are the client-side budget: 300 requests
+	// per 15 minutes, the recent-search cap of the lower API tiers.
+	xWindow             = 15 * time.Minute
+	defaultXWindowLimit = 300
+	xPageMin            = 10 // max_results bounds per page
+	xPageMax            = 100
+	xMaxPages           = 5 // pages followed when catching up from a sinceID
+	xUsersPerLookup     = 100
+	xTimeout            = 20 * time.Second
+)
+
+// X API timestamps (start_time) are RFC 3339 in UTC.
+const xTimeFormat = "2006-01-02T15:04:05Z"
+
+// Tweet is a post returned by the X API. Username is resolved from the
+// response's user expansion.
+type Tweet struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	AuthorID  string    `json:"author_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// URL links to the tweet on x.com.
+func (t Tweet) URL() string {
+	if t.Username == "" {
+		return "https://x.com/i/web/status/" + t.ID
+	}
+	return "https://x.com/" + t.Username + "/status/" + t.ID
+}
+
+// xLimit is the last rate-limit state the API reported for one endpoint.
+type xLimit struct {
+	remaining int
+	reset     time.Time
+}
+
+// XClient is a Twitter/X v2 API client with bearer auth. It tracks the
+// x-rate-limit-* headers per endpoint and a local 300-per-15-minute budget,
+// and waits for the window to reset instead of sending requests that would
+// be rejected.
+type XClient struct {
+	baseURL     string
+	bearer      string
+	client      *http.Client
+	windowLimit int
+	lookback    time.Duration
+	maxResults  int
+
+	mu      sync.Mutex
+	limits  map[string]xLimit
+	sent    []time.Time       // request times inside the current local window
+	userIDs map[string]string // lowercased handle -> user ID, resolved once
+}
+
+// XClientOption configures optional XClient behavior.
+type XClientOption func(*XClient)
+
+// WithXBaseURL points the client at another API root (tests, proxies).
+func WithXBaseURL(u string) XClientOption {
+	return func(c *XClient) {
+		if u != "" {
+			c.baseURL = strings.TrimRight(u, "/")
+		}
+	}
+}
+
+// WithXWindowLimit changes the local budget of requests per 15 minutes
+// (default 300).
+func WithXWindowLimit(n int) XClientOption {
+	return func(c *XClient) {
+		if n > 0 {
+			c.windowLimit = n
+		}
+	}
+}
+
+// WithXLookback limits fetches without a sinceID to tweets newer than d,
+// so a first run doesn't backfill the whole 7-day search window.
+func WithXLookback(d time.Duration) XClientOption {
+	return func(c *XClient) {
+		if d > 0 {
+			c.lookback = d
+		}
+	}
+}
+
+// WithXMaxResults caps the tweets one SearchRecent or UserTimeline call
+// returns (default: up to 5 pages of 100). When more are available the newest
+// are kept; older ones are skipped, not fetched on the next poll.
+func WithXMaxResults(n int) XClientOption {
+	return func(c *XClient) {
+		if n > 0 {
+			c.maxResults = n
+		}
+	}
+}
+
+// NewXClient creates a client authenticating with an app bearer token.
+func NewXClient(bearer string, opts ...XClientOption) *XClient {
+	c := &XClient{
+		baseURL:     defaultXBaseURL,
+		bearer:      bearer,
+		client:      newHTTPClient(xTimeout),
+		windowLimit: defaultXWindowLimit,
+		limits:      map[string]xLimit{},
+		userIDs:     map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SearchRecent is SearchRecentContext without a deadline.
+func (c *XClient) SearchRecent(query string, sinceID string) ([]Tweet, error) {
+	return c.SearchRecentContext(context.Background(), query, sinceID)
+}
+
+// SearchRecentContext runs a recent search and returns matching tweets
+// oldest first. With a sinceID only newer tweets are returned, following
+// pagination for up to 5 pages; without one a single page no older than the
+// lookback is fetched. Either way at most the max results are returned.
+func (c *XClient) SearchRecentContext(ctx context.Context, query string, sinceID string) ([]Tweet, error) {
+	params := url.Values{
+		"query":        {query},
+		"max_results":  {strconv.Itoa(c.pageSize())},
+		"tweet.fields": {"created_at,author_id"},
+		"expansions":   {"author_id"},
+		"user.fields":  {"username"},
+	}
+	c.setSince(params, sinceID)
+	return c.tweets(ctx, "/tweets/search/recent", "/tweets/search/recent", "next_token", params, sinceID != "")
+}
+
+// UserTimeline is UserTimelineContext without a deadline.
+func (c *XClient) UserTimeline(userID, sinceID string) ([]Tweet, error) {
+	return c.UserTimelineContext(context.Background(), userID, sinceID)
+}
+
+// UserTimelineContext returns a user's recent original tweets and quotes
+// (retweets excluded), oldest first, with the same sinceID paging, lookback
+// and max results as SearchRecentContext.
+func (c *XClient) UserTimelineContext(ctx context.Context, userID, sinceID string) ([]Tweet, error) {
+	params := url.Values{
+		"max_results":  {strconv.Itoa(c.pageSize())},
+		"tweet.fields": {"created_at,author_id"},
+		"expansions":   {"author_id"},
+		"user.fields":  {"username"},
+		"exclude":      {"retweets"},
+	}
+	c.setSince(params, sinceID)
+	return c.tweets(ctx, "/users/:id/tweets", "/users/"+url.PathEscape(userID)+"/tweets", "pagination_token", params, sinceID != "")
+}
+
+// UserIDs resolves handles (with or without @) to user IDs, keyed by the
+// handle as given. Handles are looked up once and cached for the client's
+// lifetime; handles the API doesn't know are logged once and left out.
+func (c *XClient) UserIDs(ctx context.Context, handles []string) (map[string]string, error) {
+	var missing []string
+	c.mu.Lock()
+	for _, h := range handles {
+		name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(h), "@"))
+		if name == "" {
+			continue
+		}
+		if _, ok := c.userIDs[name]; !ok && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+	c.mu.Unlock()
+
+	for len(missing) > 0 {
+		batch := missing[:min(len(missing), xUsersPerLookup)]
+		missing = missing[len(batch):]
+		var body struct {
+			Data []struct {
+				ID       string `json:"id"`
+				Username string `json:"username"`
+			} `json:"data"`
+		}
+		if err := c.get(ctx, "/users/by", "/users/by", url.Values{"usernames": {strings.Join(batch, ",")}}, &body); err != nil {
+			return nil, err
+		}
+		found := map[string]bool{}
+		c.mu.Lock()
+		for _, u := range body.Data {
+			c.userIDs[strings.ToLower(u.Username)] = u.ID
+			found[strings.ToLower(u.Username)] = true
+		}
+		c.mu.Unlock()
+		for _, name := range batch {
+			if !found[name] {
+				log.Printf("[xscanner] X user @%s not found; skipping", name)
+				c.mu.Lock()
+				c.userIDs[name] = "" // don't look it up again every poll
+				c.mu.Unlock()
+			}
+		}
+	}
+
+	out := make(map[string]string, len(handles))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, h := range handles {
+		if id := c.userIDs[strings.ToLower(strings.TrimPrefix(strings.TrimSpace(h), "@"))]; id != "" {
+			out[h] = id
+		}
+	}
+	return out, nil
+}
+
+// setSince adds since_id, or start_time for the lookback when there is no
+// sinceID yet.
+func (c *XClient) setSince(params url.Values, sinceID string) {
+	switch {
+	case sinceID != "":
+		params.Set("since_id", sinceID)
+	case c.lookback > 0:
+		params.Set("start_time", time.Now().Add(-c.lookback).UTC().Format(xTimeFormat))
+	}
+}
+
+// pageSize is max_results per request: the max results clamped to what the
+// API accepts.
+func (c *XClient) pageSize() int {
+	if c.maxResults <= 0 {
+		return xPageMax
+	}
+	return min(max(c.maxResults, xPageMin), xPageMax)
+}
+
+// tweets fetches a tweet list endpoint, following meta.next_token (sent back
+// as pageParam) when paginate is set, and returns the tweets oldest first.
+func (c *XClient) tweets(ctx context.Context, limitKey, path, pageParam string, params url.Values, paginate bool) ([]Tweet, error) {
+	var out []Tweet
+	for page := 0; page < xMaxPages; page++ {
+		var body struct {
+			Data     []Tweet `json:"data"`
+			Includes struct {
+				Users []struct {
+					ID       string `json:"id"`
+					Username string `json:"username"`
+				} `json:"users"`
+			} `json:"includes"`
+			Meta struct {
+				NextToken string `json:"next_token"`
+			} `json:"meta"`
+		}
+		if err := c.get(ctx, limitKey, path, params, &body); err != nil {
+			return nil, err
+		}
+		users := make(map[string]string, len(body.Includes.Users))
+		for _, u := range body.Includes.Users {
+			users[u.ID] = u.Username
+		}
+		for _, t := range body.Data {
+			if t.Username == "" {
+				t.Username = users[t.AuthorID]
+			}
+			out = append(out, t)
+		}
+		if !paginate || body.Meta.NextToken == "" || (c.maxResults > 0 && len(out) >= c.maxResults) {
+			break
+		}
+		params.Set(pageParam, body.Meta.NextToken)
+	}
+	// the API returns newest first
+	slices.SortFunc(out, func(a, b Tweet) int { return compareTweetIDs(a.ID, b.ID) })
+	if c.maxResults > 0 && len(out) > c.maxResults {
+		out = out[len(out)-c.maxResults:]
+	}
+	return out, nil
+}
+
+// get sends an authenticated GET through the rate limiter and decodes the
+// JSON response into v. A 429 waits for the reported reset and retries once.
+func (c *XClient) get(ctx context.Context, limitKey, path string, params url.Values, v any) error {
+	for attempt := 0; ; attempt++ {
+		if err := c.wait(ctx, limitKey); err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+params.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.bearer)
+		req.Header.Set("Accept", "application/json")
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return networkError("x http err: %w", err)
+		}
+		c.observe(limitKey, resp)
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt == 0 {
+			resp.Body.Close()
+			continue // observe recorded remaining=0, so wait sleeps until the reset
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return statusError(resp.StatusCode, "x %s status %d", path, resp.StatusCode)
+		}
+		err = json.NewDecoder(resp.Body).Decode(v)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("x %s decode err: %w", path, err)
+		}
+		return nil
+	}
+}
+
+// observe records the rate-limit headers of resp. A 429 without headers
+// blocks the endpoint for a full window.
+func (c *XClient) observe(limitKey string, resp *http.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.limits[limitKey]
+	if n, err := strconv.Atoi(resp.Header.Get("x-rate-limit-remaining")); err == nil {
+		l.remaining, ok = n, true
+	}
+	if secs, err := strconv.ParseInt(resp.Header.Get("x-rate-limit-reset"), 10, 64); err == nil {
+		l.reset, ok = time.Unix(secs, 0), true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		l.remaining, ok = 0, true
+		if !l.reset.After(time.Now()) {
+			l.reset = time.Now().Add(xWindow)
+		}
+	}
+	if ok {
+		c.limits[limitKey] = l
+	}
+}
+
+// wait blocks until a request to limitKey fits both the API-reported limit
+// and the local window budget, then reserves it.
+func (c *XClient) wait(ctx context.Context, limitKey string) error {
+	for {
+		c.mu.Lock()
+		now := time.Now()
+		var until time.Time
+		if l, ok := c.limits[limitKey]; ok && l.remaining <= 0 && now.Before(l.reset) {
+			until = l.reset
+		}
+		cutoff := now.Add(-xWindow)
+		for len(c.sent) > 0 && !c.sent[0].After(cutoff) {
+			c.sent = c.sent[1:]
+		}
+		if len(c.sent) >= c.windowLimit {
+			if t := c.sent[0].Add(xWindow); t.After(until) {
+				until = t
+			}
+		}
+		if until.IsZero() {
+			c.sent = append(c.sent, now)
+			if l, ok := c.limits[limitKey]; ok && l.remaining > 0 {
+				l.remaining--
+				c.limits[limitKey] = l
+			}
+			c.mu.Unlock()
+			return nil
+		}
+		c.mu.Unlock()
+
+		d := time.Until(until)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+			return &RetryableError{StatusCode: http.StatusTooManyRequests, Err: fmt.Errorf("x %s: reset in %s: %w", limitKey, d.Round(time.Second), ErrXRateLimited)}
+		}
+		log.Printf("[xscanner] rate limited on %s, waiting %s for the window to reset", limitKey, d.Round(time.Second))
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return &RetryableError{StatusCode: http.StatusTooManyRequests, Err: fmt.Errorf("x %s: %w", limitKey, ErrXRateLimited)}
+		case <-t.C:
+		}
+	}
+}
+
+// compareTweetIDs orders tweet IDs (snowflakes, so numeric strings of
+// varying length) chronologically.
+func compareTweetIDs(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+// maxTweetID returns the newer of two tweet IDs; either may be empty.
+func maxTweetID(a, b string) string {
+	if compareTweetIDs(a, b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,170 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestXClientSearchRecent(t *testing.T) {
+	var requests atomic.Int32
+	remaining := "5"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Path != "/tweets/search/recent" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("since_id"); got != "100" {
+			t.Errorf("since_id = %q, want 100", got)
+		}
+		w.Header().Set("x-rate-limit-remaining", remaining)
+		w.Header().Set("x-rate-limit-reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+		w.Write([]byte(`{"data":[
+			{"id":"1000","text":"$SOL next","author_id":"7","created_at":"2025-01-02T00:00:00Z"},
+			{"id":"999","text":"$BTC now","author_id":"7","created_at":"2025-01-01T00:00:00Z"}],
+			"includes":{"users":[{"id":"7","username":"GCRClassic"}]},"meta":{"result_count":2}}`))
+	}))
+	defer srv.Close()
+
+	c := NewXClient("tok", WithXBaseURL(srv.URL))
+	tweets, err := c.SearchRecent("from:GCRClassic", "100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tweets) != 2 || tweets[0].ID != "999" || tweets[1].Username != "GCRClassic" {
+		t.Fatalf("tweets = %+v, want oldest first with usernames", tweets)
+	}
+	if got := tweets[1].URL(); got != "https://x.com/GCRClassic/status/1000" {
+		t.Errorf("URL = %q", got)
+	}
+
+	// the API says the window is exhausted: the next call waits for the reset
+	// instead of hitting the server, and gives up at the context deadline
+	remaining = "0"
+	if _, err := c.SearchRecent("from:GCRClassic", "100"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.SearchRecentContext(ctx, "from:GCRClassic", "100")
+	if !errors.Is(err, ErrXRateLimited) || !IsRetryable(err) {
+		t.Errorf("err = %v, want retryable ErrXRateLimited", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server saw %d requests, want 2", n)
+	}
+}
+
+func TestXClientLocalWindow(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// no sinceID yet: only the lookback is fetched
+		start, err := time.Parse(xTimeFormat, r.URL.Query().Get("start_time"))
+		if err != nil || time.Since(start) > time.Hour+time.Minute {
+			t.Errorf("start_time = %q, want about an hour ago", r.URL.Query().Get("start_time"))
+		}
+		w.Write([]byte(`{"meta":{"result_count":0}}`))
+	}))
+	defer srv.Close()
+
+	c := NewXClient("tok", WithXBaseURL(srv.URL), WithXWindowLimit(1), WithXLookback(time.Hour))
+	if _, err := c.SearchRecent("q", ""); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.SearchRecentContext(ctx, "q", ""); !errors.Is(err, ErrXRateLimited) {
+		t.Errorf("err = %v, want ErrXRateLimited once the local budget is spent", err)
+	}
+}
+
+func TestXSearchQueries(t *testing.T) {
+	var kols []string
+	for i := 0; i < 60; i++ {
+		kols = append(kols, "kol_account_"+strconv.Itoa(i))
+	}
+	qs := xSearchQueries(kols)
+	if len(qs) < 2 {
+		t.Fatalf("got %d queries, want the KOLs split across several", len(qs))
+	}
+	for _, q := range qs {
+		if len(q) > xQueryMaxLen {
+			t.Errorf("query of %d chars exceeds %d: %s", len(q), xQueryMaxLen, q)
+		}
+	}
+	if got := kolForUsername([]string{"@GCRClassic"}, "gcrclassic"); got != "@GCRClassic" {
+		t.Errorf("kolForUsername = %q", got)
+	}
+}
+
+func TestXClientUserTimeline(t *testing.T) {
+	var lookups atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/by":
+			lookups.Add(1)
+			if got := r.URL.Query().Get("usernames"); got != "gcrclassic,nobody" {
+				t.Errorf("usernames = %q", got)
+			}
+			w.Write([]byte(`{"data":[{"id":"7","username":"GCRClassic"}]}`))
+		case "/users/7/tweets":
+			if r.URL.Query().Get("exclude") != "retweets" {
+				t.Errorf("retweets not excluded: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"data":[{"id":"5","text":"$SOL","author_id":"7"}],"includes":{"users":[{"id":"7","username":"GCRClassic"}]}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewXClient("tok", WithXBaseURL(srv.URL))
+	for i := 0; i < 2; i++ {
+		ids, err := c.UserIDs(context.Background(), []string{"@GCRClassic", "nobody"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1 || ids["@GCRClassic"] != "7" {
+			t.Fatalf("ids = %v, want only @GCRClassic -> 7", ids)
+		}
+	}
+	if n := lookups.Load(); n != 1 {
+		t.Errorf("lookups = %d, want 1 (handles resolve once)", n)
+	}
+	tweets, err := c.UserTimeline("7", "")
+	if err != nil || len(tweets) != 1 || tweets[0].Username != "GCRClassic" {
+		t.Errorf("timeline = %+v, %v", tweets, err)
+	}
+}
+
+func TestXClientMaxResults(t *testing.T) {
+	pages := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		if got := r.URL.Query().Get("max_results"); got != "10" {
+			t.Errorf("max_results = %s, want the API minimum 10", got)
+		}
+		if pages == 1 {
+			w.Write([]byte(`{"data":[{"id":"30"},{"id":"29"},{"id":"28"}],"meta":{"next_token":"p2"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"27"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewXClient("tok", WithXBaseURL(srv.URL), WithXMaxResults(2))
+	tweets, err := c.SearchRecent("q", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages != 1 || len(tweets) != 2 || tweets[0].ID != "29" || tweets[1].ID != "30" {
+		t.Errorf("pages = %d, tweets = %+v; want one page and the newest two, oldest first", pages, tweets)
+	}
+}
@@ -5,10 +5,39 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
+// ScannerConfig holds the scanner settings that can be changed at runtime.
+type ScannerConfig struct {
+	IntervalSec int
+	KOLs        []string
+}
+
+var (
+	activeScannerMu sync.Mutex
+	activeScanner   ScannerConfig
+)
+
+// ActiveScannerConfig returns the interval and KOLs the running X scanner is
+// using, hot reloads included. It is the zero value until StartXScanner runs.
+func ActiveScannerConfig() ScannerConfig {
+	activeScannerMu.Lock()
+	defer activeScannerMu.Unlock()
+	c := activeScanner
+	c.KOLs = slices.Clone(c.KOLs)
+	return c
+}
+
+func setActiveScannerConfig(intervalSec int, kols []string) {
+	activeScannerMu.Lock()
+	defer activeScannerMu.Unlock()
+	activeScanner = ScannerConfig{IntervalSec: intervalSec, KOLs: slices.Clone(kols)}
+}
+
 // StartXScanner runs a scanner loop. It sends detections into out channel
 // signature:
 // ctx context.Context
@@ -18,37 +47,126 @@ import (
 // source string
 // mock bool
 // out chan<- Detection
-func StartXScanner(ctx context.Context, intervalSec int, kols []string, bearer string, source string, mock bool, out chan<- Detection) {
-	log.Printf("[xscanner] Starting scanner (mock=%v, interval=%ds, KOLs=%v, source=%s)", mock, intervalSec, kols, source)
-	ticker := time.NewTicker(time.Duration(intervalSec) * time.Second)
+// reload <-chan ScannerConfig (optional, nil = no hot reload): new KOLs / interval applied on the fly
+func StartXScanner(ctx context.Context, intervalSec int, kols []string, bearer string, source string, mock bool, out chan<- Detection, reload <-chan ScannerConfig) {
+	log.Printf("[xscanner] Starting scanner (mock=%v, realistic=%v, interval=%ds, KOLs=%v, source=%s)", mock, mock && MockRealistic(), intervalSec, kols, source)
 	rand.Seed(time.Now().UnixNano())
+	setActiveScannerConfig(intervalSec, kols)
+
+	// MOCK_REALISTIC: 0..n detections per tick on a jittered schedule
+	realistic := mock && MockRealistic()
+	var opts []PollerOption
+	if realistic {
+		opts = append(opts, WithJitter(mockRealisticJitter))
+	}
+
+	var xc *XClient
+	var state *xScannerState
+	if !mock && bearer != "" {
+		c := cfg()
+		xopts := []XClientOption{WithXMaxResults(c.ScannerMaxResults), WithXLookback(c.ScannerLookback)}
+		if c.XAPIBaseURL != "" {
+			xopts = append(xopts, WithXBaseURL(c.XAPIBaseURL))
+		}
+		xc = NewXClient(bearer, xopts...)
+		state = loadXScannerState(XStatePath())
+	}
 
-	defer ticker.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("[xscanner] Stopped.")
-			return
-		case <-ticker.C:
-			// produce one mock detection per tick when mock==true
-			if mock {
+	var mu sync.Mutex // guards kols against hot reloads
+	poller := NewPoller("xscanner", time.Duration(intervalSec)*time.Second, func(ctx context.Context) error {
+		recordScannerTick()
+		// produce one mock detection per tick when mock==true
+		if mock {
+			n := 1
+			if realistic {
+				n = drawMockCount(MockBursts(), mockMaxPerTick())
+			}
+			for i := 0; i < n; i++ {
+				mu.Lock()
 				d := generateMockDetection(kols, source)
-				if d.Text != "" {
-					out <- d
+				mu.Unlock()
+				emitDetection(out, d)
+			}
+			return nil
+		}
+
+		if xc == nil {
+			log.Println("[xscanner] WARNING: real mode requested but no bearer token provided; skipping")
+			return nil
+		}
+
+		mu.Lock()
+		current := kols
+		mu.Unlock()
+		defer func() {
+			if err := state.save(); err != nil {
+				log.Println("[xscanner] Warning: could not save scanner state:", err)
+			}
+		}()
+		emit := func(key string, tweets []Tweet) {
+			for _, t := range tweets {
+				if !state.markSeen(key, t.ID) {
+					continue
+				}
+				if d, ok := DetectionFromPost(kolForUsername(current, t.Username), t.Text, t.URL(), source, t.CreatedAt); ok {
+					emitDetection(out, d)
+				}
+			}
+		}
+
+		if cfg().XScanMode == XScanTimeline {
+			ids, err := xc.UserIDs(ctx, current)
+			if err != nil {
+				return fmt.Errorf("x user lookup: %w", err)
+			}
+			for _, kol := range current {
+				id, ok := ids[kol]
+				if !ok {
+					continue
+				}
+				key := "timeline:" + id
+				tweets, err := xc.UserTimelineContext(ctx, id, state.sinceID(key))
+				if err != nil {
+					return fmt.Errorf("x timeline %s: %w", kol, err)
 				}
-				continue
+				emit(key, tweets)
 			}
+			return nil
+		}
 
-			// REAL mode placeholder: not implemented (must use bearer token)
-			if bearer == "" {
-				log.Println("[xscanner] WARNING: real mode requested but no bearer token provided; skipping")
-				continue
+		for _, q := range xSearchQueries(current) {
+			tweets, err := xc.SearchRecentContext(ctx, q, state.sinceID(q))
+			if err != nil {
+				return fmt.Errorf("x search: %w", err)
 			}
+			emit(q, tweets)
+		}
+		return nil
+	}, opts...)
 
-			// TODO: implement real fetch using X/Twitter API with rate-limits and parsing
-			log.Println("[xscanner] real mode requested but not implemented yet.")
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sc := <-reload:
+				mu.Lock()
+				if len(sc.KOLs) > 0 {
+					kols = sc.KOLs
+				}
+				if sc.IntervalSec > 0 && sc.IntervalSec != intervalSec {
+					intervalSec = sc.IntervalSec
+					poller.SetInterval(time.Duration(intervalSec) * time.Second)
+				}
+				setActiveScannerConfig(intervalSec, kols)
+				log.Printf("[xscanner] Reloaded (interval=%ds, KOLs=%v)", intervalSec, kols)
+				mu.Unlock()
+			}
 		}
-	}
+	}()
+
+	poller.Run(ctx)
+	log.Println("[xscanner] Stopped.")
 }
 
 func generateMockDetection(kols []string, source string) Detection {
@@ -60,7 +178,7 @@ func generateMockDetection(kols []string, source string) Detection {
 	token := tokenList[rand.Intn(len(tokenList))]
 	msg := fmt.Sprintf("KOL %s mentioned %s", kol, token)
 	link := "https://twitter.com/" + strings.ToLower(kol)
-	return Detection{
+	d := Detection{
 		Text:      msg,
 		Link:      link,
 		Source:    source,
@@ -69,4 +187,59 @@ func generateMockDetection(kols []string, source string) Detection {
 		Token:     token,
 		Confidence: rand.Float64()*0.6 + 0.4,
 	}
+	d.EnsureID()
+	return d
+}
+
+const (
+	defaultScannerMaxResults = 10
+	defaultScannerLookback   = time.Hour
+)
+
+// X scanner modes (X_SCAN_MODE).
+const (
+	XScanSearch   = "search"   // default: one recent search per batch of KOLs
+	XScanTimeline = "timeline" // poll each KOL's user timeline
+)
+
+// xQueryMaxLen is the recent-search query length limit of the lower API tiers.
+const xQueryMaxLen = 512
+
+// xSearchQueries builds recent-search queries for cashtag posts by kols,
+// packing as many from: clauses into each query as the length limit allows.
+func xSearchQueries(kols []string) []string {
+	const suffix = ") has:cashtags -is:retweet"
+	var queries []string
+	var b strings.Builder
+	for _, k := range kols {
+		k = strings.TrimPrefix(strings.TrimSpace(k), "@")
+		if k == "" {
+			continue
+		}
+		clause := "from:" + k
+		if b.Len() > 0 && b.Len()+len(" OR ")+len(clause)+len(suffix) > xQueryMaxLen {
+			queries = append(queries, b.String()+suffix)
+			b.Reset()
+		}
+		if b.Len() == 0 {
+			b.WriteString("(")
+		} else {
+			b.WriteString(" OR ")
+		}
+		b.WriteString(clause)
+	}
+	if b.Len() > 0 {
+		queries = append(queries, b.String()+suffix)
+	}
+	return queries
+}
+
+// kolForUsername maps a tweet author back to the configured KOL spelling.
+func kolForUsername(kols []string, username string) string {
+	for _, k := range kols {
+		if strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(k), "@"), username) {
+			return k
+		}
+	}
+	return username
 }
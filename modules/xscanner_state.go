@@ -0,0 +1,95 @@
+package modules
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+)
+
+// xSeenMax bounds the persisted seen-ID list; since_id already keeps old
+// tweets out, this only covers overlaps between queries and timelines.
+const xSeenMax = 2000
+
+// XStatePath returns the real scanner's state file (X_STATE_FILE, default
+// xscanner_state.json).
+func XStatePath() string {
+	return cfg().XStateFile
+}
+
+// xScannerState is where the real scanner resumes after a restart: the
+// newest tweet ID per search query or timeline, and the IDs of recently
+// processed tweets so none becomes a detection twice.
+type xScannerState struct {
+	path string
+
+	SinceIDs map[string]string `json:"since_ids"`
+	Seen     []string          `json:"seen"` // oldest first
+	seen     map[string]bool
+	dirty    bool
+}
+
+// loadXScannerState reads path; a missing or unreadable file starts fresh.
+func loadXScannerState(path string) *xScannerState {
+	s := &xScannerState{path: path, SinceIDs: map[string]string{}, seen: map[string]bool{}}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Println("[xscanner] Warning: could not read scanner state:", err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		log.Println("[xscanner] Warning: could not parse scanner state, starting fresh:", err)
+		return &xScannerState{path: path, SinceIDs: map[string]string{}, seen: map[string]bool{}}
+	}
+	if s.SinceIDs == nil {
+		s.SinceIDs = map[string]string{}
+	}
+	for _, id := range s.Seen {
+		s.seen[id] = true
+	}
+	log.Printf("[xscanner] Resuming %d query/timeline position(s) from %s", len(s.SinceIDs), path)
+	return s
+}
+
+// sinceID is the newest tweet ID processed for key ("" if none yet).
+func (s *xScannerState) sinceID(key string) string {
+	return s.SinceIDs[key]
+}
+
+// markSeen records tweet id under key and reports whether it is new.
+func (s *xScannerState) markSeen(key, id string) bool {
+	if newest := maxTweetID(s.SinceIDs[key], id); newest != s.SinceIDs[key] {
+		s.SinceIDs[key] = newest
+		s.dirty = true
+	}
+	if s.seen[id] {
+		return false
+	}
+	s.seen[id] = true
+	s.Seen = append(s.Seen, id)
+	if over := len(s.Seen) - xSeenMax; over > 0 {
+		for _, old := range s.Seen[:over] {
+			delete(s.seen, old)
+		}
+		s.Seen = s.Seen[over:]
+	}
+	s.dirty = true
+	return true
+}
+
+// save writes the state if it changed since the last save.
+func (s *xScannerState) save() error {
+	if !s.dirty || s.path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path, b, 0644); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
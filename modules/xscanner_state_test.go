@@ -0,0 +1,33 @@
+package modules
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestXScannerStateResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xstate.json")
+	s := loadXScannerState(path)
+	if s.sinceID("q") != "" {
+		t.Fatal("fresh state has a sinceID")
+	}
+	for _, id := range []string{"998", "1001", "999"} {
+		if !s.markSeen("q", id) {
+			t.Errorf("tweet %s reported as seen on first sight", id)
+		}
+	}
+	if err := s.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	s = loadXScannerState(path)
+	if got := s.sinceID("q"); got != "1001" {
+		t.Errorf("sinceID after restart = %q, want 1001", got)
+	}
+	if s.markSeen("timeline:7", "999") {
+		t.Error("tweet seen before the restart was processed again")
+	}
+	if got := s.sinceID("timeline:7"); got != "999" {
+		t.Errorf("timeline sinceID = %q, want 999", got)
+	}
+}
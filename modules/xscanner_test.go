@@ -0,0 +1,92 @@
+package modules
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartXScannerRealMode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tweets/search/recent" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if q := r.URL.Query().Get("query"); !strings.Contains(q, "from:GCRClassic") {
+			t.Errorf("query = %q", q)
+		}
+		w.Header().Set("x-rate-limit-remaining", "100")
+		w.Header().Set("x-rate-limit-reset", strconv.FormatInt(time.Now().Add(15*time.Minute).Unix(), 10))
+		w.Write([]byte(`{"data":[
+			{"id":"1000","text":"loading more $SOL and $PEPE here","author_id":"7","created_at":"2025-01-02T03:04:05Z"}],
+			"includes":{"users":[{"id":"7","username":"GCRClassic"}]},"meta":{"result_count":1}}`))
+	}))
+	defer srv.Close()
+
+	c := LoadConfig()
+	c.XAPIBaseURL = srv.URL
+	c.XStateFile = filepath.Join(t.TempDir(), "xstate.json")
+	c.CashtagGate = CashtagGateOff
+	SetConfig(c)
+	t.Cleanup(func() { SetConfig(nil) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan Detection, 10)
+	go StartXScanner(ctx, 1, []string{"GCRClassic"}, "tok", "x", false, out, nil)
+
+	var d Detection
+	select {
+	case d = <-out:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no detection")
+	}
+	// the post mentions $SOL and $PEPE; the detection carries the first
+	want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	if d.Token != "SOL" || d.KOL != "GCRClassic" || d.Source != "x" {
+		t.Errorf("detection = %+v, want $SOL from GCRClassic", d)
+	}
+	if d.Link != "https://x.com/GCRClassic/status/1000" || !d.Timestamp.Equal(want) || d.Text == "" {
+		t.Errorf("detection link/time/text = %q %v %q", d.Link, d.Timestamp, d.Text)
+	}
+	select {
+	case extra := <-out:
+		t.Errorf("one post produced a second detection: %+v", extra)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestActiveScannerConfigFollowsReload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reload := make(chan ScannerConfig)
+	go StartXScanner(ctx, 60, []string{"ansem"}, "", "x", true, make(chan Detection, 10), reload)
+
+	waitActive := func(want ScannerConfig) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			got := ActiveScannerConfig()
+			if got.IntervalSec == want.IntervalSec && slices.Equal(got.KOLs, want.KOLs) {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("ActiveScannerConfig = %+v, want %+v", got, want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitActive(ScannerConfig{IntervalSec: 60, KOLs: []string{"ansem"}})
+
+	reload <- ScannerConfig{IntervalSec: 30, KOLs: []string{"cobie", "ansem"}}
+	waitActive(ScannerConfig{IntervalSec: 30, KOLs: []string{"cobie", "ansem"}})
+
+	// an empty KOL list keeps the current one
+	reload <- ScannerConfig{IntervalSec: 45}
+	waitActive(ScannerConfig{IntervalSec: 45, KOLs: []string{"cobie", "ansem"}})
+}
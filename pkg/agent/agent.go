@@ -10,8 +10,9 @@ import (
 	"syscall"
 	"time"
 
+	"signalshield/pkg/nft"
+
 	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/auth"
-	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/nft"
 	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/types"
 )
 
@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/types"
+)
+
+// BaseAgent provides no-op implementations of the optional handler interfaces
+// so simple agents only have to implement ProcessTask. Embed it and override
+// whatever you need:
+//
+//	type EchoAgent struct {
+//		agent.BaseAgent
+//	}
+//
+//	func (a *EchoAgent) ProcessTask(ctx context.Context, task string) (string, error) {
+//		return task, nil
+//	}
+//
+// The contract for handlers run by Agent and EnhancedAgent:
+//   - ProcessTask is called concurrently (up to MaxConcurrentTasks) and must
+//     respect ctx, which is cancelled when the task times out or the agent stops
+//   - Initialize (types.AgentInitializer) runs once before any task, with the
+//     agent's config; an error aborts startup
+//   - GetAvailableTasks (types.TaskProvider) may return nil when there is no local work
+//   - HandleTaskResult (types.TaskResultHandler) runs after each successful task
+//   - Cleanup (types.AgentCleaner) runs once on shutdown
+type BaseAgent struct {
+	// Config is the value passed to Initialize, the agent's *Config
+	Config interface{}
+}
+
+var (
+	_ types.AgentHandler      = (*BaseAgent)(nil)
+	_ types.AgentInitializer  = (*BaseAgent)(nil)
+	_ types.TaskProvider      = (*BaseAgent)(nil)
+	_ types.TaskResultHandler = (*BaseAgent)(nil)
+	_ types.AgentCleaner      = (*BaseAgent)(nil)
+)
+
+// ProcessTask returns types.ErrNotImplemented; embedding agents override it
+func (b *BaseAgent) ProcessTask(ctx context.Context, task string) (string, error) {
+	return "", types.ErrNotImplemented
+}
+
+// Initialize stores the agent config for later use
+func (b *BaseAgent) Initialize(ctx context.Context, config interface{}) error {
+	b.Config = config
+	return nil
+}
+
+// GetAvailableTasks reports no local tasks
+func (b *BaseAgent) GetAvailableTasks(ctx context.Context) ([]types.Task, error) {
+	return nil, nil
+}
+
+// HandleTaskResult does nothing with the result
+func (b *BaseAgent) HandleTaskResult(ctx context.Context, taskID string, result string) error {
+	return nil
+}
+
+// Cleanup has nothing to release
+func (b *BaseAgent) Cleanup(ctx context.Context) error {
+	return nil
+}
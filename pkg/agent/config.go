@@ -37,6 +37,9 @@ type Config struct {
 	// Health monitoring
 	HealthEnabled bool `json:"health_enabled"`
 	HealthPort    int  `json:"health_port"`
+	// HealthPortFallback is how many following ports to try when HealthPort is
+	// taken; Start fails with a clear error when none of them is free
+	HealthPortFallback int `json:"health_port_fallback"`
 
 	// Authentication
 	PrivateKey   string `json:"private_key"`
@@ -135,6 +138,11 @@ func (c *Config) LoadFromEnv() error {
 			c.HealthPort = port
 		}
 	}
+	if fallback := os.Getenv("HEALTH_PORT_FALLBACK"); fallback != "" {
+		if n, err := strconv.Atoi(fallback); err == nil && n >= 0 {
+			c.HealthPortFallback = n
+		}
+	}
 	if rateLimit := os.Getenv("RATE_LIMIT_PER_MINUTE"); rateLimit != "" {
 		if limit, err := strconv.Atoi(rateLimit); err == nil {
 			c.RateLimitPerMinute = limit
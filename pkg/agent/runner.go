@@ -3,8 +3,10 @@ package agent
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
@@ -12,11 +14,12 @@ import (
 	"syscall"
 	"time"
 
+	"signalshield/pkg/cache"
+	"signalshield/pkg/health"
+	"signalshield/pkg/network"
+	"signalshield/pkg/nft"
+
 	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/auth"
-	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/cache"
-	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/health"
-	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/network"
-	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/nft"
 	"github.com/TeneoProtocolAI/teneo-agent-sdk/pkg/types"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -251,6 +254,7 @@ func NewEnhancedAgent(config *EnhancedAgentConfig) (*EnhancedAgent, error) {
 			config.Config.HealthPort,
 			agentInfo,
 			agent,
+			health.WithPortFallback(config.Config.HealthPortFallback),
 		)
 	}
 
@@ -283,9 +287,16 @@ func (a *EnhancedAgent) Start() error {
 
 	// Start health server if enabled
 	if a.healthServer != nil {
+		// bind before serving so an occupied port (after any HEALTH_PORT_FALLBACK
+		// ports) is fatal rather than leaving the agent running with no health
+		// endpoint for orchestrators to probe
+		if err := a.healthServer.Listen(); err != nil {
+			a.running = false
+			return fmt.Errorf("cannot start health server (set HEALTH_PORT or HEALTH_PORT_FALLBACK): %w", err)
+		}
 		go func() {
-			log.Printf("🌐 Starting health monitoring on port %d", a.config.HealthPort)
-			if err := a.healthServer.Start(); err != nil {
+			log.Printf("🌐 Starting health monitoring on port %d", a.healthServer.Port())
+			if err := a.healthServer.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				log.Printf("❌ Health server error: %v", err)
 			}
 		}()
@@ -493,6 +504,21 @@ func (a *EnhancedAgent) GetUptime() time.Duration {
 	return time.Since(a.startTime)
 }
 
+// GetLastError implements the health.ActivityGetter interface
+func (a *EnhancedAgent) GetLastError() health.ErrorEvent {
+	metrics := a.networkClient.GetHealthMetrics()
+	return health.ErrorEvent{Err: metrics.LastError, At: metrics.LastErrorTime}
+}
+
+// GetLastActivity implements the health.ActivityGetter interface
+func (a *EnhancedAgent) GetLastActivity() time.Time {
+	metrics := a.networkClient.GetHealthMetrics()
+	if metrics.LastMessageReceived.After(metrics.LastMessageSent) {
+		return metrics.LastMessageReceived
+	}
+	return metrics.LastMessageSent
+}
+
 // GetConfig returns the agent configuration
 func (a *EnhancedAgent) GetConfig() *Config {
 	return a.config
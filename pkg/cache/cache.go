@@ -13,7 +13,7 @@ type AgentCache interface {
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 
 	// Get retrieves a value by key
-	// Returns nil if the key doesn't exist
+	// Returns ErrCacheKeyNotFound if the key doesn't exist
 	Get(ctx context.Context, key string) (string, error)
 
 	// GetBytes retrieves a value as bytes
@@ -32,15 +32,18 @@ type AgentCache interface {
 	SetWithExpiry(ctx context.Context, key string, value interface{}, expiryTime time.Time) error
 
 	// Increment increments a counter key by 1
+	// A missing key starts at 0, so the first call returns 1
 	Increment(ctx context.Context, key string) (int64, error)
 
 	// IncrementBy increments a counter key by a specific amount
 	IncrementBy(ctx context.Context, key string, value int64) (int64, error)
 
 	// SetIfNotExists sets a value only if the key doesn't exist (returns true if set)
+	// The check and set must be atomic so it can be used as a lock
 	SetIfNotExists(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
 
 	// GetTTL returns the remaining TTL for a key
+	// Returns 0 if the key has no expiry and ErrCacheKeyNotFound if it doesn't exist
 	GetTTL(ctx context.Context, key string) (time.Duration, error)
 
 	// Ping checks if the cache is available
@@ -54,6 +57,8 @@ type AgentCache interface {
 }
 
 // NoOpCache is a cache implementation that does nothing (for when Redis is disabled)
+// It never stores anything, so SetIfNotExists always returns false and reads
+// always miss; use MemoryCache when a working cache is needed without Redis.
 type NoOpCache struct{}
 
 func (c *NoOpCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
@@ -0,0 +1,324 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// cacheFactories returns the AgentCache implementations to run the
+// conformance suite against. Redis is only included when REDIS_ADDRESS is set.
+func cacheFactories(t *testing.T) map[string]func(t *testing.T) AgentCache {
+	factories := map[string]func(t *testing.T) AgentCache{
+		"memory": func(t *testing.T) AgentCache {
+			return NewMemoryCache()
+		},
+		"prefixed-memory": func(t *testing.T) AgentCache {
+			return WithPrefix(NewMemoryCache(), "ns:")
+		},
+		"timeout-memory": func(t *testing.T) AgentCache {
+			return WithTimeout(NewMemoryCache(), time.Second)
+		},
+	}
+
+	if addr := os.Getenv("REDIS_ADDRESS"); addr != "" {
+		factories["redis"] = func(t *testing.T) AgentCache {
+			config := DefaultRedisConfig()
+			config.Address = addr
+			config.KeyPrefix = fmt.Sprintf("teneo:test:%d:", time.Now().UnixNano())
+			c, err := NewRedisCache(config)
+			if err != nil {
+				t.Skipf("Redis not available: %v", err)
+			}
+			t.Cleanup(func() {
+				c.Clear(context.Background())
+				c.Close()
+			})
+			return c
+		}
+	}
+
+	return factories
+}
+
+func TestAgentCacheConformance(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, c AgentCache)
+	}{
+		{"SetIfNotExistsIsAtomic", testSetIfNotExistsAtomic},
+		{"GetTTLReflectsSetTTL", testGetTTL},
+		{"IncrementStartsAtOne", testIncrementStartsAtOne},
+		{"DeletePatternOnlyMatching", testDeletePattern},
+		{"GetMissingKey", testGetMissingKey},
+		{"RateLimiterCountsPerKey", testRateLimiterCounts},
+		{"RateLimiterWindowRollover", testRateLimiterRollover},
+		{"RateLimiterUnlimited", testRateLimiterUnlimited},
+		{"JSONRoundTrip", testJSONRoundTrip},
+		{"JSONErrors", testJSONErrors},
+	}
+
+	for implName, newCache := range cacheFactories(t) {
+		for _, tt := range tests {
+			t.Run(implName+"/"+tt.name, func(t *testing.T) {
+				tt.run(t, newCache(t))
+			})
+		}
+	}
+}
+
+func testSetIfNotExistsAtomic(t *testing.T, c AgentCache) {
+	ctx := context.Background()
+
+	const workers = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ok, err := c.SetIfNotExists(ctx, "lock", fmt.Sprintf("owner-%d", i), time.Minute)
+			if err != nil {
+				t.Errorf("SetIfNotExists failed: %v", err)
+				return
+			}
+			if ok {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if winners != 1 {
+		t.Errorf("Expected exactly 1 SetIfNotExists to succeed, got %d", winners)
+	}
+
+	ok, err := c.SetIfNotExists(ctx, "lock", "late", time.Minute)
+	if err != nil || ok {
+		t.Errorf("Expected SetIfNotExists on existing key to return false, got %v (err: %v)", ok, err)
+	}
+}
+
+func testGetTTL(t *testing.T, c AgentCache) {
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "ttl", "value", time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	ttl, err := c.GetTTL(ctx, "ttl")
+	if err != nil {
+		t.Fatalf("GetTTL failed: %v", err)
+	}
+	if ttl <= 50*time.Second || ttl > time.Minute {
+		t.Errorf("Expected TTL close to 1m, got %v", ttl)
+	}
+
+	if err := c.Set(ctx, "forever", "value", 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	ttl, err = c.GetTTL(ctx, "forever")
+	if err != nil || ttl != 0 {
+		t.Errorf("Expected TTL 0 for key without expiry, got %v (err: %v)", ttl, err)
+	}
+
+	if _, err := c.GetTTL(ctx, "missing"); !errors.Is(err, ErrCacheKeyNotFound) {
+		t.Errorf("Expected ErrCacheKeyNotFound for missing key, got %v", err)
+	}
+}
+
+func testIncrementStartsAtOne(t *testing.T, c AgentCache) {
+	ctx := context.Background()
+
+	n, err := c.Increment(ctx, "counter")
+	if err != nil {
+		t.Fatalf("Increment failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected first Increment to return 1, got %d", n)
+	}
+
+	n, err = c.IncrementBy(ctx, "counter", 5)
+	if err != nil {
+		t.Fatalf("IncrementBy failed: %v", err)
+	}
+	if n != 6 {
+		t.Errorf("Expected IncrementBy to return 6, got %d", n)
+	}
+}
+
+func testDeletePattern(t *testing.T, c AgentCache) {
+	ctx := context.Background()
+
+	for _, key := range []string{"session:1", "session:2", "user:1"} {
+		if err := c.Set(ctx, key, "value", time.Minute); err != nil {
+			t.Fatalf("Set %s failed: %v", key, err)
+		}
+	}
+
+	if err := c.DeletePattern(ctx, "session:*"); err != nil {
+		t.Fatalf("DeletePattern failed: %v", err)
+	}
+
+	for key, want := range map[string]bool{"session:1": false, "session:2": false, "user:1": true} {
+		exists, err := c.Exists(ctx, key)
+		if err != nil {
+			t.Fatalf("Exists %s failed: %v", key, err)
+		}
+		if exists != want {
+			t.Errorf("Expected Exists(%s) = %v, got %v", key, want, exists)
+		}
+	}
+}
+
+func testGetMissingKey(t *testing.T, c AgentCache) {
+	if _, err := c.Get(context.Background(), "missing"); !errors.Is(err, ErrCacheKeyNotFound) {
+		t.Errorf("Expected ErrCacheKeyNotFound, got %v", err)
+	}
+}
+
+func testRateLimiterCounts(t *testing.T, c AgentCache) {
+	ctx := context.Background()
+	l := NewCacheRateLimiter(c, 3, time.Hour)
+
+	var resetAt time.Time
+	for i, want := range []struct {
+		allowed   bool
+		remaining int
+	}{{true, 2}, {true, 1}, {true, 0}, {false, 0}, {false, 0}} {
+		res, err := l.Allow(ctx, "alice")
+		if err != nil {
+			t.Fatalf("Allow %d failed: %v", i+1, err)
+		}
+		if res.Allowed != want.allowed || res.Remaining != want.remaining || res.Limit != 3 {
+			t.Errorf("Allow %d = %+v, want allowed=%v remaining=%d limit=3", i+1, res, want.allowed, want.remaining)
+		}
+		if i == 0 {
+			resetAt = res.ResetAt
+			if d := time.Until(resetAt); d <= 0 || d > time.Hour {
+				t.Errorf("Expected ResetAt within the next hour, got %v", resetAt)
+			}
+		} else if !res.ResetAt.Equal(resetAt) {
+			t.Errorf("Allow %d ResetAt = %v, want %v", i+1, res.ResetAt, resetAt)
+		}
+	}
+
+	// another key has its own budget
+	if res, err := l.Allow(ctx, "bob"); err != nil || !res.Allowed || res.Remaining != 2 {
+		t.Errorf("Expected a fresh budget for another key, got %+v (err: %v)", res, err)
+	}
+}
+
+func testRateLimiterRollover(t *testing.T, c AgentCache) {
+	ctx := context.Background()
+	const window = 200 * time.Millisecond
+	l := NewCacheRateLimiter(c, 1, window)
+
+	// start just after a window boundary so both calls land in the same window
+	time.Sleep(time.Until(time.Now().Truncate(window).Add(window + 5*time.Millisecond)))
+	first, err := l.Allow(ctx, "alice")
+	if err != nil || !first.Allowed {
+		t.Fatalf("Expected the first request to be allowed, got %+v (err: %v)", first, err)
+	}
+	if res, err := l.Allow(ctx, "alice"); err != nil || res.Allowed {
+		t.Fatalf("Expected the second request to be limited, got %+v (err: %v)", res, err)
+	}
+	if d := first.RetryAfter(); d <= 0 || d > window {
+		t.Errorf("Expected RetryAfter within the window, got %v", d)
+	}
+
+	time.Sleep(time.Until(first.ResetAt) + 5*time.Millisecond)
+	res, err := l.Allow(ctx, "alice")
+	if err != nil || !res.Allowed || res.Remaining != 0 {
+		t.Errorf("Expected a new window to allow the request, got %+v (err: %v)", res, err)
+	}
+	if !res.ResetAt.After(first.ResetAt) {
+		t.Errorf("Expected ResetAt to move past %v, got %v", first.ResetAt, res.ResetAt)
+	}
+}
+
+func testRateLimiterUnlimited(t *testing.T, c AgentCache) {
+	for _, limit := range []int{0, -1} {
+		l := NewCacheRateLimiter(c, limit, time.Minute)
+		for i := 0; i < 5; i++ {
+			res, err := l.Allow(context.Background(), "alice")
+			if err != nil || !res.Allowed || res.Remaining != -1 {
+				t.Errorf("limit %d: Allow %d = %+v (err: %v), want allowed with Remaining -1", limit, i+1, res, err)
+			}
+		}
+	}
+}
+
+type jsonProfile struct {
+	Name  string   `json:"name"`
+	Score float64  `json:"score"`
+	Tags  []string `json:"tags"`
+}
+
+func testJSONRoundTrip(t *testing.T, c AgentCache) {
+	ctx := context.Background()
+	want := jsonProfile{Name: "alice", Score: 0.75, Tags: []string{"kol", "sol"}}
+
+	if err := SetJSON(ctx, c, "profile", want, time.Minute); err != nil {
+		t.Fatalf("SetJSON failed: %v", err)
+	}
+	got, err := GetJSON[jsonProfile](ctx, c, "profile")
+	if err != nil {
+		t.Fatalf("GetJSON failed: %v", err)
+	}
+	if got.Name != want.Name || got.Score != want.Score || !slices.Equal(got.Tags, want.Tags) {
+		t.Errorf("GetJSON = %+v, want %+v", got, want)
+	}
+
+	var into jsonProfile
+	if err := GetJSONInto(ctx, c, "profile", &into); err != nil || into.Name != want.Name {
+		t.Errorf("GetJSONInto = %+v (err: %v), want %+v", into, err, want)
+	}
+
+	if ttl, err := c.GetTTL(ctx, "profile"); err != nil || ttl <= 50*time.Second || ttl > time.Minute {
+		t.Errorf("Expected SetJSON to keep the 1m TTL, got %v (err: %v)", ttl, err)
+	}
+}
+
+func testJSONErrors(t *testing.T, c AgentCache) {
+	ctx := context.Background()
+
+	if _, err := GetJSON[jsonProfile](ctx, c, "missing"); !errors.Is(err, ErrCacheKeyNotFound) {
+		t.Errorf("Expected ErrCacheKeyNotFound for a missing key, got %v", err)
+	}
+
+	if err := c.Set(ctx, "garbage", "not json", time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	got, err := GetJSON[jsonProfile](ctx, c, "garbage")
+	if !errors.Is(err, ErrCacheInvalidData) {
+		t.Errorf("Expected ErrCacheInvalidData for a non-JSON value, got %v", err)
+	}
+	if got.Name != "" || got.Tags != nil {
+		t.Errorf("Expected the zero value on error, got %+v", got)
+	}
+
+	// valid JSON of the wrong shape is invalid data too
+	if err := SetJSON(ctx, c, "wrong", []int{1, 2}, time.Minute); err != nil {
+		t.Fatalf("SetJSON failed: %v", err)
+	}
+	var into jsonProfile
+	if err := GetJSONInto(ctx, c, "wrong", &into); !errors.Is(err, ErrCacheInvalidData) {
+		t.Errorf("Expected ErrCacheInvalidData for mismatched JSON, got %v", err)
+	}
+
+	if err := SetJSON(ctx, c, "chan", make(chan int), time.Minute); err == nil {
+		t.Error("Expected SetJSON to fail for a value that can't be marshaled")
+	}
+	if exists, _ := c.Exists(ctx, "chan"); exists {
+		t.Error("Expected a failed SetJSON not to store anything")
+	}
+}
@@ -11,4 +11,10 @@ var (
 
 	// ErrCacheOperationFailed is returned when a cache operation fails
 	ErrCacheOperationFailed = errors.New("cache operation failed")
+
+	// ErrCacheInvalidData is returned when a cached value cannot be decoded
+	ErrCacheInvalidData = errors.New("cache value could not be decoded")
+
+	// ErrCacheTimeout is returned when a cache operation exceeds its deadline
+	ErrCacheTimeout = errors.New("cache operation timed out")
 )
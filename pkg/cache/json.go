@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SetJSON marshals v to JSON and stores it under key with an optional TTL
+func SetJSON(ctx context.Context, c AgentCache, key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value for key %s: %w", key, err)
+	}
+	return c.Set(ctx, key, data, ttl)
+}
+
+// GetJSON retrieves key and unmarshals its JSON value into a T.
+// Returns ErrCacheKeyNotFound if the key doesn't exist and ErrCacheInvalidData
+// if the stored value is not valid JSON for T.
+func GetJSON[T any](ctx context.Context, c AgentCache, key string) (T, error) {
+	var v T
+	if err := GetJSONInto(ctx, c, key, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// GetJSONInto retrieves key and unmarshals its JSON value into dst
+// (non-generic variant of GetJSON)
+func GetJSONInto(ctx context.Context, c AgentCache, key string, dst interface{}) error {
+	data, err := c.GetBytes(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("%w: key %s: %v", ErrCacheInvalidData, key, err)
+	}
+	return nil
+}
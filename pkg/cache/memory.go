@@ -0,0 +1,361 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+	"weak"
+)
+
+// defaultJanitorInterval is how often NewMemoryCache sweeps expired keys
+const defaultJanitorInterval = time.Minute
+
+// MemoryCache implements the AgentCache interface with an in-process map.
+// It is useful for single-instance agents and tests; state is not shared
+// between processes. Expired keys are never returned, and a background
+// janitor evicts them so keys that are not read again don't pile up.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+
+	janitor *janitorStop
+}
+
+// janitorStop signals the janitor goroutine to exit; stop is safe to call
+// more than once (Close and the GC cleanup may both call it)
+type janitorStop struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+func (s *janitorStop) stop() {
+	s.once.Do(func() { close(s.ch) })
+}
+
+// memoryEntry is a stored value with an optional expiry
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time // zero means no expiry
+}
+
+// expired reports whether the entry has passed its expiry time
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// NewMemoryCache creates a new in-memory cache whose janitor sweeps expired
+// keys every minute
+func NewMemoryCache() *MemoryCache {
+	return NewMemoryCacheWithJanitor(defaultJanitorInterval)
+}
+
+// NewMemoryCacheWithJanitor creates a new in-memory cache that sweeps expired
+// keys every interval (interval <= 0 disables the janitor; expired keys are
+// then only dropped when read). The janitor stops on Close or once the cache
+// is garbage collected.
+func NewMemoryCacheWithJanitor(interval time.Duration) *MemoryCache {
+	m := &MemoryCache{
+		entries: make(map[string]memoryEntry),
+	}
+	if interval > 0 {
+		// the janitor holds only a weak reference, so an unclosed cache can
+		// still be collected; the cleanup then stops the goroutine
+		m.janitor = &janitorStop{ch: make(chan struct{})}
+		go runJanitor(weak.Make(m), interval, m.janitor.ch)
+		runtime.AddCleanup(m, (*janitorStop).stop, m.janitor)
+	}
+	return m
+}
+
+// runJanitor calls deleteExpired every interval until stop is closed or the
+// cache is gone
+func runJanitor(ref weak.Pointer[MemoryCache], interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m := ref.Value()
+			if m == nil {
+				return
+			}
+			m.deleteExpired()
+		}
+	}
+}
+
+// deleteExpired evicts every expired entry
+func (m *MemoryCache) deleteExpired() {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, entry := range m.entries {
+		if entry.expired(now) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// encodeValue converts a value to bytes the same way RedisCache does
+func encodeValue(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return append([]byte(nil), v...), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value: %w", err)
+		}
+		return data, nil
+	}
+}
+
+// expiryFor converts a TTL into an absolute expiry (zero for no expiry)
+func expiryFor(ttl time.Duration) time.Time {
+	if ttl == 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// lookup returns a live entry, evicting it if it has expired. Caller must hold mu.
+func (m *MemoryCache) lookup(key string) (memoryEntry, bool) {
+	entry, ok := m.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if entry.expired(time.Now()) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+// Set stores a value with an optional TTL
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if ttl < 0 {
+		return fmt.Errorf("TTL cannot be negative")
+	}
+
+	data, err := encodeValue(value)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = memoryEntry{value: data, expiresAt: expiryFor(ttl)}
+	return nil
+}
+
+// Get retrieves a value by key
+func (m *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	data, err := m.GetBytes(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetBytes retrieves a value as bytes
+func (m *MemoryCache) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.lookup(key)
+	if !ok {
+		return nil, ErrCacheKeyNotFound
+	}
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Delete removes a key from the cache
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+// DeletePattern removes all keys matching a glob pattern (e.g., "session:*")
+func (m *MemoryCache) DeletePattern(ctx context.Context, pattern string) error {
+	if err := validateKey(pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	pattern = sanitizePattern(pattern)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.entries {
+		if globMatch(pattern, key) {
+			delete(m.entries, key)
+		}
+	}
+	return nil
+}
+
+// Exists checks if a key exists
+func (m *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	if err := validateKey(key); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.lookup(key)
+	return ok, nil
+}
+
+// SetWithExpiry sets a key with an absolute expiration time
+func (m *MemoryCache) SetWithExpiry(ctx context.Context, key string, value interface{}, expiryTime time.Time) error {
+	ttl := time.Until(expiryTime)
+	if ttl <= 0 {
+		return fmt.Errorf("expiry time must be in the future")
+	}
+	return m.Set(ctx, key, value, ttl)
+}
+
+// Increment increments a counter key by 1
+func (m *MemoryCache) Increment(ctx context.Context, key string) (int64, error) {
+	return m.IncrementBy(ctx, key, 1)
+}
+
+// IncrementBy increments a counter key by a specific amount.
+// A missing key starts at 0; an existing key keeps its TTL.
+func (m *MemoryCache) IncrementBy(ctx context.Context, key string, value int64) (int64, error) {
+	if err := validateKey(key); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.lookup(key)
+	var current int64
+	if ok {
+		n, err := strconv.ParseInt(string(entry.value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to increment key %s: value is not an integer", key)
+		}
+		current = n
+	}
+
+	current += value
+	entry.value = []byte(strconv.FormatInt(current, 10))
+	m.entries[key] = entry
+	return current, nil
+}
+
+// SetIfNotExists sets a value only if the key doesn't exist
+func (m *MemoryCache) SetIfNotExists(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	if err := validateKey(key); err != nil {
+		return false, err
+	}
+	if ttl < 0 {
+		return false, fmt.Errorf("TTL cannot be negative")
+	}
+
+	data, err := encodeValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.lookup(key); ok {
+		return false, nil
+	}
+	m.entries[key] = memoryEntry{value: data, expiresAt: expiryFor(ttl)}
+	return true, nil
+}
+
+// GetTTL returns the remaining TTL for a key (0 if the key has no expiry)
+func (m *MemoryCache) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	if err := validateKey(key); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.lookup(key)
+	if !ok {
+		return 0, ErrCacheKeyNotFound
+	}
+	if entry.expiresAt.IsZero() {
+		return 0, nil
+	}
+	return time.Until(entry.expiresAt), nil
+}
+
+// Ping checks if the cache is available
+func (m *MemoryCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close stops the janitor and releases the cache contents
+func (m *MemoryCache) Close() error {
+	if m.janitor != nil {
+		m.janitor.stop()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]memoryEntry)
+	return nil
+}
+
+// Clear removes all keys from the cache
+func (m *MemoryCache) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]memoryEntry)
+	return nil
+}
+
+// globMatch reports whether key matches a Redis-style glob pattern
+// supporting '*' (any sequence) and '?' (any single character)
+func globMatch(pattern, key string) bool {
+	p, k := []rune(pattern), []rune(key)
+	pi, ki := 0, 0
+	star, match := -1, 0
+
+	for ki < len(k) {
+		switch {
+		case pi < len(p) && p[pi] == '*':
+			star = pi
+			match = ki
+			pi++
+		case pi < len(p) && (p[pi] == '?' || p[pi] == k[ki]):
+			pi++
+			ki++
+		case star >= 0:
+			pi = star + 1
+			match++
+			ki = match
+		default:
+			return false
+		}
+	}
+
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
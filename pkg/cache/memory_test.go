@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheWithJanitor(0)
+	defer c.Close()
+
+	if err := c.Set(ctx, "short", "v", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "forever", "v", 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.Get(ctx, "short"); err != nil || got != "v" {
+		t.Fatalf("Get before expiry = %q, %v", got, err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := c.Get(ctx, "short"); !errors.Is(err, ErrCacheKeyNotFound) {
+		t.Errorf("Get after expiry: err = %v, want ErrCacheKeyNotFound", err)
+	}
+	if ok, _ := c.Exists(ctx, "forever"); !ok {
+		t.Error("key without TTL expired")
+	}
+	// an expired key no longer blocks SetIfNotExists
+	if err := c.Set(ctx, "lock", "a", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if ok, err := c.SetIfNotExists(ctx, "lock", "b", time.Minute); err != nil || !ok {
+		t.Errorf("SetIfNotExists on expired key = %v, %v; want true", ok, err)
+	}
+}
+
+func TestMemoryCacheJanitorEvicts(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheWithJanitor(10 * time.Millisecond)
+	defer c.Close()
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := c.Set(ctx, key, "v", 5*time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Set(ctx, "keep", "v", time.Minute)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.mu.RLock()
+		n := len(c.entries)
+		c.mu.RUnlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("janitor left %d entries, want 1", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMemoryCacheJanitorStopsWhenCollected(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for range 10 {
+		NewMemoryCacheWithJanitor(time.Millisecond)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d janitor goroutines still running", runtime.NumGoroutine()-before)
+		}
+		runtime.GC()
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMemoryCacheConcurrentIncrement(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+	defer c.Close()
+
+	const workers, perWorker = 8, 250
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWorker {
+				if _, err := c.Increment(ctx, "hits"); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, err := c.Get(ctx, "hits"); err != nil || got != "2000" {
+		t.Errorf("hits = %q, %v; want 2000", got, err)
+	}
+}
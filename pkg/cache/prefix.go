@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// PrefixedCache wraps an AgentCache and transparently prepends a namespace to
+// every key, so several agents can share one backend without colliding.
+// DeletePattern and Clear only touch keys within the namespace.
+type PrefixedCache struct {
+	inner  AgentCache
+	prefix string
+}
+
+// WithPrefix returns a cache that namespaces all keys of c under prefix
+// (e.g., "agent:myagent:")
+func WithPrefix(c AgentCache, prefix string) AgentCache {
+	return &PrefixedCache{
+		inner:  c,
+		prefix: prefix,
+	}
+}
+
+// prefixKey adds the namespace to a key
+func (p *PrefixedCache) prefixKey(key string) string {
+	return p.prefix + key
+}
+
+// Set stores a value with an optional TTL
+func (p *PrefixedCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return p.inner.Set(ctx, p.prefixKey(key), value, ttl)
+}
+
+// Get retrieves a value by key
+func (p *PrefixedCache) Get(ctx context.Context, key string) (string, error) {
+	return p.inner.Get(ctx, p.prefixKey(key))
+}
+
+// GetBytes retrieves a value as bytes
+func (p *PrefixedCache) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	return p.inner.GetBytes(ctx, p.prefixKey(key))
+}
+
+// Delete removes a key from the cache
+func (p *PrefixedCache) Delete(ctx context.Context, key string) error {
+	return p.inner.Delete(ctx, p.prefixKey(key))
+}
+
+// DeletePattern removes all keys in the namespace matching a pattern
+func (p *PrefixedCache) DeletePattern(ctx context.Context, pattern string) error {
+	return p.inner.DeletePattern(ctx, p.prefixKey(sanitizePattern(pattern)))
+}
+
+// Exists checks if a key exists
+func (p *PrefixedCache) Exists(ctx context.Context, key string) (bool, error) {
+	return p.inner.Exists(ctx, p.prefixKey(key))
+}
+
+// SetWithExpiry sets a key with an absolute expiration time
+func (p *PrefixedCache) SetWithExpiry(ctx context.Context, key string, value interface{}, expiryTime time.Time) error {
+	return p.inner.SetWithExpiry(ctx, p.prefixKey(key), value, expiryTime)
+}
+
+// Increment increments a counter key by 1
+func (p *PrefixedCache) Increment(ctx context.Context, key string) (int64, error) {
+	return p.inner.Increment(ctx, p.prefixKey(key))
+}
+
+// IncrementBy increments a counter key by a specific amount
+func (p *PrefixedCache) IncrementBy(ctx context.Context, key string, value int64) (int64, error) {
+	return p.inner.IncrementBy(ctx, p.prefixKey(key), value)
+}
+
+// SetIfNotExists sets a value only if the key doesn't exist
+func (p *PrefixedCache) SetIfNotExists(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return p.inner.SetIfNotExists(ctx, p.prefixKey(key), value, ttl)
+}
+
+// GetTTL returns the remaining TTL for a key
+func (p *PrefixedCache) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	return p.inner.GetTTL(ctx, p.prefixKey(key))
+}
+
+// Ping checks if the underlying cache is available
+func (p *PrefixedCache) Ping(ctx context.Context) error {
+	return p.inner.Ping(ctx)
+}
+
+// Close closes the underlying cache
+func (p *PrefixedCache) Close() error {
+	return p.inner.Close()
+}
+
+// Clear removes all keys within the namespace
+func (p *PrefixedCache) Clear(ctx context.Context) error {
+	return p.inner.DeletePattern(ctx, p.prefix+"*")
+}
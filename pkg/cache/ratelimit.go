@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CacheRateLimiter enforces a fixed-window rate limit using shared cache state,
+// so the limit holds across multiple agent instances backed by the same cache
+type CacheRateLimiter struct {
+	cache  AgentCache
+	limit  int
+	window time.Duration
+	prefix string
+}
+
+// RateLimitResult describes the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed   bool      // Whether the request is within the limit
+	Limit     int       // Maximum requests per window
+	Remaining int       // Requests left in the current window
+	ResetAt   time.Time // When the current window ends
+}
+
+// RetryAfter returns how long the caller should wait before the window resets
+func (r RateLimitResult) RetryAfter() time.Duration {
+	d := time.Until(r.ResetAt)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// NewCacheRateLimiter creates a rate limiter allowing limit requests per window.
+// If window is 0, a one minute window is used.
+func NewCacheRateLimiter(c AgentCache, limit int, window time.Duration) *CacheRateLimiter {
+	if window <= 0 {
+		window = time.Minute
+	}
+	return &CacheRateLimiter{
+		cache:  c,
+		limit:  limit,
+		window: window,
+		prefix: "ratelimit:",
+	}
+}
+
+// Allow records a request for key and reports whether it is within the limit.
+// A limit of 0 or less means unlimited.
+func (l *CacheRateLimiter) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	now := time.Now()
+	windowStart := now.Truncate(l.window)
+	result := RateLimitResult{
+		Limit:   l.limit,
+		ResetAt: windowStart.Add(l.window),
+	}
+
+	if l.limit <= 0 {
+		result.Allowed = true
+		result.Remaining = -1
+		return result, nil
+	}
+
+	// Milliseconds, so sub-second windows within the same second get their own counter
+	windowKey := fmt.Sprintf("%s%s:%d", l.prefix, key, windowStart.UnixMilli())
+
+	// Create the counter with the window TTL first; Increment keeps the TTL
+	if _, err := l.cache.SetIfNotExists(ctx, windowKey, "0", time.Until(result.ResetAt)+time.Second); err != nil {
+		return result, fmt.Errorf("failed to initialize rate limit window: %w", err)
+	}
+
+	count, err := l.cache.Increment(ctx, windowKey)
+	if err != nil {
+		return result, fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+
+	result.Allowed = count <= int64(l.limit)
+	result.Remaining = l.limit - int(count)
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+
+	return result, nil
+}
@@ -409,8 +409,12 @@ func (r *RedisCache) GetTTL(ctx context.Context, key string) (time.Duration, err
 		return 0, fmt.Errorf("failed to get TTL for key %s: %w", key, err)
 	}
 
-	if ttl < 0 {
+	// Redis returns -2 for a missing key and -1 for a key without expiry
+	switch ttl {
+	case -2:
 		return 0, ErrCacheKeyNotFound
+	case -1:
+		return 0, nil
 	}
 
 	return ttl, nil
@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultOperationTimeout is the per-operation timeout used by TimeoutCache
+// when none is configured
+const DefaultOperationTimeout = 2 * time.Second
+
+// TimeoutCache wraps an AgentCache and bounds every operation to a maximum
+// duration. Operations run against a derived context and the caller gets
+// ErrCacheTimeout as soon as the deadline passes, even if the underlying
+// implementation ignores the context.
+type TimeoutCache struct {
+	inner   AgentCache
+	timeout time.Duration
+}
+
+// WithTimeout returns a cache that enforces timeout on each operation of c.
+// If timeout is 0, DefaultOperationTimeout is used.
+func WithTimeout(c AgentCache, timeout time.Duration) *TimeoutCache {
+	if timeout <= 0 {
+		timeout = DefaultOperationTimeout
+	}
+	return &TimeoutCache{
+		inner:   c,
+		timeout: timeout,
+	}
+}
+
+// Timeout returns the configured per-operation timeout
+func (t *TimeoutCache) Timeout() time.Duration {
+	return t.timeout
+}
+
+// runWithTimeout executes op with a bounded context and returns its result,
+// or ErrCacheTimeout if op doesn't finish in time
+func runWithTimeout[T any](ctx context.Context, timeout time.Duration, name string, op func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		val T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		val, err := op(ctx)
+		done <- result{val, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.val, r.err
+	case <-ctx.Done():
+		var zero T
+		if ctx.Err() == context.DeadlineExceeded {
+			return zero, fmt.Errorf("%w: %s after %v", ErrCacheTimeout, name, timeout)
+		}
+		return zero, ctx.Err()
+	}
+}
+
+// runErrWithTimeout is runWithTimeout for operations that only return an error
+func runErrWithTimeout(ctx context.Context, timeout time.Duration, name string, op func(ctx context.Context) error) error {
+	_, err := runWithTimeout(ctx, timeout, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, op(ctx)
+	})
+	return err
+}
+
+// Set stores a value with an optional TTL
+func (t *TimeoutCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return runErrWithTimeout(ctx, t.timeout, "set", func(ctx context.Context) error {
+		return t.inner.Set(ctx, key, value, ttl)
+	})
+}
+
+// Get retrieves a value by key
+func (t *TimeoutCache) Get(ctx context.Context, key string) (string, error) {
+	return runWithTimeout(ctx, t.timeout, "get", func(ctx context.Context) (string, error) {
+		return t.inner.Get(ctx, key)
+	})
+}
+
+// GetBytes retrieves a value as bytes
+func (t *TimeoutCache) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	return runWithTimeout(ctx, t.timeout, "get", func(ctx context.Context) ([]byte, error) {
+		return t.inner.GetBytes(ctx, key)
+	})
+}
+
+// Delete removes a key from the cache
+func (t *TimeoutCache) Delete(ctx context.Context, key string) error {
+	return runErrWithTimeout(ctx, t.timeout, "delete", func(ctx context.Context) error {
+		return t.inner.Delete(ctx, key)
+	})
+}
+
+// DeletePattern removes all keys matching a pattern
+func (t *TimeoutCache) DeletePattern(ctx context.Context, pattern string) error {
+	return runErrWithTimeout(ctx, t.timeout, "delete pattern", func(ctx context.Context) error {
+		return t.inner.DeletePattern(ctx, pattern)
+	})
+}
+
+// Exists checks if a key exists
+func (t *TimeoutCache) Exists(ctx context.Context, key string) (bool, error) {
+	return runWithTimeout(ctx, t.timeout, "exists", func(ctx context.Context) (bool, error) {
+		return t.inner.Exists(ctx, key)
+	})
+}
+
+// SetWithExpiry sets a key with an absolute expiration time
+func (t *TimeoutCache) SetWithExpiry(ctx context.Context, key string, value interface{}, expiryTime time.Time) error {
+	return runErrWithTimeout(ctx, t.timeout, "set with expiry", func(ctx context.Context) error {
+		return t.inner.SetWithExpiry(ctx, key, value, expiryTime)
+	})
+}
+
+// Increment increments a counter key by 1
+func (t *TimeoutCache) Increment(ctx context.Context, key string) (int64, error) {
+	return runWithTimeout(ctx, t.timeout, "increment", func(ctx context.Context) (int64, error) {
+		return t.inner.Increment(ctx, key)
+	})
+}
+
+// IncrementBy increments a counter key by a specific amount
+func (t *TimeoutCache) IncrementBy(ctx context.Context, key string, value int64) (int64, error) {
+	return runWithTimeout(ctx, t.timeout, "increment", func(ctx context.Context) (int64, error) {
+		return t.inner.IncrementBy(ctx, key, value)
+	})
+}
+
+// SetIfNotExists sets a value only if the key doesn't exist
+func (t *TimeoutCache) SetIfNotExists(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return runWithTimeout(ctx, t.timeout, "set if not exists", func(ctx context.Context) (bool, error) {
+		return t.inner.SetIfNotExists(ctx, key, value, ttl)
+	})
+}
+
+// GetTTL returns the remaining TTL for a key
+func (t *TimeoutCache) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	return runWithTimeout(ctx, t.timeout, "get ttl", func(ctx context.Context) (time.Duration, error) {
+		return t.inner.GetTTL(ctx, key)
+	})
+}
+
+// Ping checks if the cache is available
+func (t *TimeoutCache) Ping(ctx context.Context) error {
+	return runErrWithTimeout(ctx, t.timeout, "ping", t.inner.Ping)
+}
+
+// Close closes the underlying cache
+func (t *TimeoutCache) Close() error {
+	return t.inner.Close()
+}
+
+// Clear removes all keys with the agent's prefix
+func (t *TimeoutCache) Clear(ctx context.Context) error {
+	return runErrWithTimeout(ctx, t.timeout, "clear", t.inner.Clear)
+}
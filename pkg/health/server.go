@@ -1,20 +1,128 @@
 package health
 
 import (
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 )
 
+// ErrPortInUse is returned when the health port is already bound by another process
+var ErrPortInUse = errors.New("port already in use")
+
 // Server provides health monitoring endpoints
 type Server struct {
-	port         int
+	mu        sync.Mutex // guards port, listener, server, startTime and stopped
+	port      int
+	listener  net.Listener
+	server    *http.Server
+	startTime time.Time
+	stopped   bool
+
 	agentInfo    *AgentInfo
 	statusGetter StatusGetter
-	server       *http.Server
+	statusPolicy StatusPolicy
+	certFile     string
+	keyFile      string
+	tlsConfig    *tls.Config
+	portRetries  int
+}
+
+// StatusPolicy maps the agent's connection state to the status string and
+// HTTP status code returned by /health
+type StatusPolicy func(connected, authenticated bool) (status string, statusCode int)
+
+// DefaultStatusPolicy reports 200 while connected, authenticated or not, and 503 when disconnected
+func DefaultStatusPolicy(connected, authenticated bool) (string, int) {
+	if connected && authenticated {
+		return "healthy", http.StatusOK
+	} else if connected {
+		return "connected_not_authenticated", http.StatusOK
+	}
+	return "disconnected", http.StatusServiceUnavailable
+}
+
+// StrictStatusPolicy reports 503 unless the agent is both connected and authenticated,
+// for load balancers that should only route to fully functional agents
+func StrictStatusPolicy(connected, authenticated bool) (string, int) {
+	status, _ := DefaultStatusPolicy(connected, authenticated)
+	if connected && authenticated {
+		return status, http.StatusOK
+	}
+	return status, http.StatusServiceUnavailable
+}
+
+// Option configures optional Server behavior
+type Option func(*Server)
+
+// WithStatusPolicy sets the policy used by /health to pick its status and HTTP code
+func WithStatusPolicy(policy StatusPolicy) Option {
+	return func(s *Server) {
+		if policy != nil {
+			s.statusPolicy = policy
+		}
+	}
+}
+
+// WithTLS serves over HTTPS using the given certificate and key files
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *Server) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
+// WithTLSConfig serves over HTTPS using cfg, e.g. from an autocert.Manager's
+// TLSConfig(). Cert and key files from WithTLS, if any, are loaded on top of it.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(s *Server) {
+		s.tlsConfig = cfg
+	}
+}
+
+// WithPortFallback makes Start try up to retries following ports when the
+// configured one is in use, instead of failing with ErrPortInUse
+func WithPortFallback(retries int) Option {
+	return func(s *Server) {
+		if retries > 0 {
+			s.portRetries = retries
+		}
+	}
+}
+
+// ListenWithFallback binds port, moving on to up to retries following ports
+// while they are in use. It returns the listener and the port actually bound;
+// when every port is taken the error wraps ErrPortInUse
+func ListenWithFallback(port, retries int) (net.Listener, int, error) {
+	ln, err := listenPort(port)
+	p := port
+	for i := 1; err != nil && errors.Is(err, ErrPortInUse) && i <= retries; i++ {
+		log.Printf("⚠️ Health server port %d in use, trying %d", p, port+i)
+		p = port + i
+		ln, err = listenPort(p)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return ln, p, nil
+}
+
+func listenPort(port int) (net.Listener, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		if errors.Is(err, syscall.EADDRINUSE) {
+			return nil, fmt.Errorf("health server port %d: %w", port, ErrPortInUse)
+		}
+		return nil, fmt.Errorf("health server port %d: %w", port, err)
+	}
+	return ln, nil
 }
 
 // AgentInfo contains basic agent information
@@ -31,31 +139,83 @@ type StatusGetter interface {
 	IsConnected() bool
 	IsAuthenticated() bool
 	GetActiveTaskCount() int
+}
+
+// UptimeGetter can optionally be implemented by a StatusGetter that tracks
+// uptime itself. Otherwise the Server reports the time since Start.
+type UptimeGetter interface {
 	GetUptime() time.Duration
 }
 
+// ActivityGetter can optionally be implemented by a StatusGetter to report the
+// last error the agent hit and when it last did useful work
+type ActivityGetter interface {
+	GetLastError() ErrorEvent
+	GetLastActivity() time.Time
+}
+
+// ErrorEvent is an error and when it happened; a nil Err means none so far
+type ErrorEvent struct {
+	Err error
+	At  time.Time
+}
+
 // HealthStatus represents the agent's health status
 type HealthStatus struct {
-	Status        string    `json:"status"`
-	Connected     bool      `json:"connected"`
-	Authenticated bool      `json:"authenticated"`
-	ActiveTasks   int       `json:"active_tasks"`
-	Uptime        string    `json:"uptime"`
-	Timestamp     time.Time `json:"timestamp"`
-	Agent         AgentInfo `json:"agent"`
+	Status        string     `json:"status"`
+	Connected     bool       `json:"connected"`
+	Authenticated bool       `json:"authenticated"`
+	ActiveTasks   int        `json:"active_tasks"`
+	Uptime        string     `json:"uptime"`
+	StartedAt     time.Time  `json:"started_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	LastActivity  *time.Time `json:"last_activity,omitempty"`
+	Timestamp     time.Time  `json:"timestamp"`
+	Agent         AgentInfo  `json:"agent"`
 }
 
 // NewServer creates a new health monitoring server
-func NewServer(port int, agentInfo *AgentInfo, statusGetter StatusGetter) *Server {
-	return &Server{
+func NewServer(port int, agentInfo *AgentInfo, statusGetter StatusGetter, opts ...Option) *Server {
+	s := &Server{
 		port:         port,
 		agentInfo:    agentInfo,
 		statusGetter: statusGetter,
+		statusPolicy: DefaultStatusPolicy,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
+}
+
+// Listen binds the health port (or a fallback port, see WithPortFallback)
+// without serving yet, so callers get bind errors synchronously. Start calls
+// it if it hasn't been called
+func (s *Server) Listen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return http.ErrServerClosed
+	}
+	if s.listener != nil {
+		return nil
+	}
+	ln, port, err := ListenWithFallback(s.port, s.portRetries)
+	if err != nil {
+		return err
+	}
+	s.listener, s.port = ln, port
+	return nil
 }
 
-// Start starts the health monitoring server
+// Start starts the health monitoring server and blocks until it stops; after
+// Stop it returns http.ErrServerClosed
 func (s *Server) Start() error {
+	if err := s.Listen(); err != nil {
+		return err
+	}
+
 	mux := http.NewServeMux()
 
 	// Health endpoints
@@ -64,23 +224,80 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/status", s.statusHandler)
 	mux.HandleFunc("/info", s.infoHandler)
 
-	s.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", s.port),
-		Handler: mux,
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return http.ErrServerClosed
+	}
+	ln, port := s.listener, s.port
+	srv := &http.Server{
+		Addr:      fmt.Sprintf(":%d", port),
+		Handler:   mux,
+		TLSConfig: s.tlsConfig,
+	}
+	s.server = srv
+	s.startTime = time.Now()
+	s.mu.Unlock()
+
+	if s.TLSEnabled() {
+		log.Printf("🔒 Starting health server on port %d (TLS)...", port)
+		return srv.ServeTLS(ln, s.certFile, s.keyFile)
 	}
 
-	log.Printf("🌐 Starting health server on port %d...", s.port)
-	return s.server.ListenAndServe()
+	log.Printf("🌐 Starting health server on port %d...", port)
+	return srv.Serve(ln)
+}
+
+// Port returns the port the server listens on, which differs from the
+// configured one if a fallback port was used
+func (s *Server) Port() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.port
+}
+
+// TLSEnabled reports whether the server was configured to serve HTTPS
+func (s *Server) TLSEnabled() bool {
+	return s.tlsConfig != nil || (s.certFile != "" && s.keyFile != "")
 }
 
 // Stop stops the health monitoring server
 func (s *Server) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
 	if s.server != nil {
 		return s.server.Close()
 	}
+	if s.listener != nil {
+		// bound but never served
+		return s.listener.Close()
+	}
 	return nil
 }
 
+// Uptime returns the agent uptime, preferring the StatusGetter's own tracking when available
+func (s *Server) Uptime() time.Duration {
+	if ug, ok := s.statusGetter.(UptimeGetter); ok {
+		return ug.GetUptime()
+	}
+	start := s.StartTime()
+	if start.IsZero() {
+		return 0
+	}
+	return time.Since(start)
+}
+
+// StartTime returns when the server was started, or the zero time if it hasn't been
+func (s *Server) StartTime() time.Time {
+	if ug, ok := s.statusGetter.(UptimeGetter); ok {
+		return time.Now().Add(-ug.GetUptime())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.startTime
+}
+
 // rootHandler handles the root endpoint
 func (s *Server) rootHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain")
@@ -93,7 +310,7 @@ func (s *Server) rootHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "Authenticated: %v\n", s.statusGetter.IsAuthenticated())
 	fmt.Fprintf(w, "Active Tasks: %d\n", s.statusGetter.GetActiveTaskCount())
 	fmt.Fprintf(w, "Capabilities: %s\n", strings.Join(s.agentInfo.Capabilities, ", "))
-	fmt.Fprintf(w, "Uptime: %v\n", s.statusGetter.GetUptime())
+	fmt.Fprintf(w, "Uptime: %v\n", s.Uptime())
 	fmt.Fprintf(w, "\nEndpoints:\n")
 	fmt.Fprintf(w, "  /health - Health check\n")
 	fmt.Fprintf(w, "  /status - Detailed status (JSON)\n")
@@ -107,19 +324,7 @@ func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	connected := s.statusGetter.IsConnected()
 	authenticated := s.statusGetter.IsAuthenticated()
 
-	var status string
-	var statusCode int
-
-	if connected && authenticated {
-		status = "healthy"
-		statusCode = http.StatusOK
-	} else if connected {
-		status = "connected_not_authenticated"
-		statusCode = http.StatusOK
-	} else {
-		status = "disconnected"
-		statusCode = http.StatusServiceUnavailable
-	}
+	status, statusCode := s.statusPolicy(connected, authenticated)
 
 	w.WriteHeader(statusCode)
 
@@ -154,11 +359,24 @@ func (s *Server) statusHandler(w http.ResponseWriter, r *http.Request) {
 		Connected:     connected,
 		Authenticated: authenticated,
 		ActiveTasks:   s.statusGetter.GetActiveTaskCount(),
-		Uptime:        s.statusGetter.GetUptime().String(),
+		Uptime:        s.Uptime().String(),
+		StartedAt:     s.StartTime(),
 		Timestamp:     time.Now(),
 		Agent:         *s.agentInfo,
 	}
 
+	if ag, ok := s.statusGetter.(ActivityGetter); ok {
+		if last := ag.GetLastError(); last.Err != nil {
+			healthStatus.LastError = last.Err.Error()
+			if !last.At.IsZero() {
+				healthStatus.LastErrorAt = &last.At
+			}
+		}
+		if at := ag.GetLastActivity(); !at.IsZero() {
+			healthStatus.LastActivity = &at
+		}
+	}
+
 	json.NewEncoder(w).Encode(healthStatus)
 }
 
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+type stubStatus struct{}
+
+func (stubStatus) IsConnected() bool       { return true }
+func (stubStatus) IsAuthenticated() bool   { return true }
+func (stubStatus) GetActiveTaskCount() int { return 0 }
+
+func TestServerListenFallback(t *testing.T) {
+	busy, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+	port := busy.Addr().(*net.TCPAddr).Port
+
+	// without a fallback the bind error comes back synchronously
+	s := NewServer(port, &AgentInfo{}, stubStatus{})
+	if err := s.Listen(); !errors.Is(err, ErrPortInUse) {
+		t.Fatalf("Listen on a busy port: err = %v, want ErrPortInUse", err)
+	}
+
+	s = NewServer(port, &AgentInfo{}, stubStatus{}, WithPortFallback(5))
+	if err := s.Listen(); err != nil {
+		t.Skipf("no free port after %d: %v", port, err)
+	}
+	defer s.Stop()
+	if got := s.Port(); got <= port || got > port+5 {
+		t.Errorf("Port() = %d, want one of the %d ports after %d", got, 5, port)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Start() }()
+	_ = s.Port() // concurrent with Start; -race checks the port is guarded
+	s.Stop()
+	<-done
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"unicode"
 )
 
@@ -19,6 +20,14 @@ type AgentNamingRules struct {
 	AllowNumbers    bool
 	AllowHyphens    bool
 	AllowUnderscores bool
+
+	// SimilarityThreshold is the edit distance at or below which
+	// ValidateAgentNameUnique flags a name as too close to an existing one
+	// (0 disables); SimilarityStrict makes that an error instead of a warning
+	SimilarityThreshold int
+	SimilarityStrict    bool
+
+	reservedMu sync.RWMutex // guards ReservedNames against AddReservedNames
 }
 
 // ValidationResult represents the result of name validation
@@ -41,6 +50,7 @@ var DefaultAgentNamingRules = &AgentNamingRules{
 	AllowNumbers:     true,
 	AllowHyphens:     true,
 	AllowUnderscores: true,
+	SimilarityThreshold: 2,
 }
 
 // Strict naming rules for production environments
@@ -55,6 +65,8 @@ var StrictAgentNamingRules = &AgentNamingRules{
 	AllowNumbers:     true,
 	AllowHyphens:     true,
 	AllowUnderscores: false,
+	SimilarityThreshold: 2,
+	SimilarityStrict: true,
 }
 
 // getReservedNames returns a map of reserved agent names
@@ -112,6 +124,14 @@ func getReservedNames() map[string]bool {
 
 // ValidateAgentName validates an agent name against the specified rules
 func ValidateAgentName(name string, rules *AgentNamingRules) *ValidationResult {
+	result, reasons := validateAgentName(name, rules)
+	recordValidation(reasons)
+	return result
+}
+
+// validateAgentName is ValidateAgentName without counting the outcome in the
+// validation stats; it returns the failure reasons for the caller to record
+func validateAgentName(name string, rules *AgentNamingRules) (*ValidationResult, []string) {
 	if rules == nil {
 		rules = DefaultAgentNamingRules
 	}
@@ -122,6 +142,8 @@ func ValidateAgentName(name string, rules *AgentNamingRules) *ValidationResult {
 		Warnings: make([]string, 0),
 	}
 	
+	var reasons []string
+
 	// normalize name for validation
 	normalizedName := strings.TrimSpace(name)
 	if !rules.CaseSensitive {
@@ -132,58 +154,68 @@ func ValidateAgentName(name string, rules *AgentNamingRules) *ValidationResult {
 	// check if name is empty
 	if normalizedName == "" {
 		result.IsValid = false
+		reasons = append(reasons, ReasonEmpty)
 		result.Errors = append(result.Errors, "agent name cannot be empty")
-		return result
+		return result, reasons
 	}
 	
 	// check length constraints
 	if len(normalizedName) < rules.MinLength {
 		result.IsValid = false
+		reasons = append(reasons, ReasonTooShort)
 		result.Errors = append(result.Errors, fmt.Sprintf("agent name must be at least %d characters long", rules.MinLength))
 	}
 	
 	if len(normalizedName) > rules.MaxLength {
 		result.IsValid = false
+		reasons = append(reasons, ReasonTooLong)
 		result.Errors = append(result.Errors, fmt.Sprintf("agent name must not exceed %d characters", rules.MaxLength))
 	}
 	
 	// check pattern
 	if !rules.AllowedPattern.MatchString(normalizedName) {
 		result.IsValid = false
+		reasons = append(reasons, ReasonBadPattern)
 		result.Errors = append(result.Errors, "agent name contains invalid characters or format")
 	}
 	
 	// check reserved names
-	if rules.ReservedNames[normalizedName] {
+	if rules.isReserved(normalizedName) {
 		result.IsValid = false
+		reasons = append(reasons, ReasonReserved)
 		result.Errors = append(result.Errors, fmt.Sprintf("'%s' is a reserved name and cannot be used", normalizedName))
 	}
 	
 	// check required prefix
 	if rules.RequiredPrefix != "" && !strings.HasPrefix(normalizedName, rules.RequiredPrefix) {
 		result.IsValid = false
+		reasons = append(reasons, ReasonMissingPrefix)
 		result.Errors = append(result.Errors, fmt.Sprintf("agent name must start with '%s'", rules.RequiredPrefix))
 	}
 	
 	// check required suffix
 	if rules.RequiredSuffix != "" && !strings.HasSuffix(normalizedName, rules.RequiredSuffix) {
 		result.IsValid = false
+		reasons = append(reasons, ReasonMissingSuffix)
 		result.Errors = append(result.Errors, fmt.Sprintf("agent name must end with '%s'", rules.RequiredSuffix))
 	}
 	
 	// additional character validations
 	if !rules.AllowNumbers && containsNumbers(normalizedName) {
 		result.IsValid = false
+		reasons = append(reasons, ReasonNumbers)
 		result.Errors = append(result.Errors, "agent name cannot contain numbers")
 	}
 	
 	if !rules.AllowHyphens && strings.Contains(normalizedName, "-") {
 		result.IsValid = false
+		reasons = append(reasons, ReasonHyphens)
 		result.Errors = append(result.Errors, "agent name cannot contain hyphens")
 	}
 	
 	if !rules.AllowUnderscores && strings.Contains(normalizedName, "_") {
 		result.IsValid = false
+		reasons = append(reasons, ReasonUnderscores)
 		result.Errors = append(result.Errors, "agent name cannot contain underscores")
 	}
 	
@@ -204,7 +236,7 @@ func ValidateAgentName(name string, rules *AgentNamingRules) *ValidationResult {
 		result.Warnings = append(result.Warnings, "consider using descriptive names instead of abbreviations")
 	}
 	
-	return result
+	return result, reasons
 }
 
 // NormalizeAgentName normalizes an agent name according to rules
@@ -0,0 +1,79 @@
+package naming
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AddReservedNames reserves additional names (brand terms, internal services)
+// on top of the rules' current set, which starts from the built-in defaults.
+// Names are case-folded unless the rules are case sensitive, matching how
+// ValidateAgentName normalizes the name it checks. Safe to call while other
+// goroutines validate names against the same rules
+func (r *AgentNamingRules) AddReservedNames(names ...string) {
+	r.reservedMu.Lock()
+	defer r.reservedMu.Unlock()
+
+	// copy on write so readers holding the old map are unaffected
+	reserved := make(map[string]bool, len(r.ReservedNames)+len(names))
+	if r.ReservedNames == nil {
+		reserved = getReservedNames()
+	}
+	for name := range r.ReservedNames {
+		reserved[name] = true
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !r.CaseSensitive {
+			name = strings.ToLower(name)
+		}
+		if name != "" {
+			reserved[name] = true
+		}
+	}
+	r.ReservedNames = reserved
+}
+
+// AddReservedNamesFromFile reserves the names listed in path, one per line.
+// Blank lines and lines starting with # are ignored
+func (r *AgentNamingRules) AddReservedNamesFromFile(path string) error {
+	names, err := LoadReservedNames(path)
+	if err != nil {
+		return err
+	}
+	r.AddReservedNames(names...)
+	return nil
+}
+
+// LoadReservedNames reads a reserved-name list: one name per line, blank
+// lines and # comments ignored
+func LoadReservedNames(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reserved names file: %w", err)
+	}
+	defer f.Close()
+
+	var names []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read reserved names file: %w", err)
+	}
+	return names, nil
+}
+
+// isReserved reports whether the normalized name is reserved
+func (r *AgentNamingRules) isReserved(name string) bool {
+	r.reservedMu.RLock()
+	defer r.reservedMu.RUnlock()
+	return r.ReservedNames[name]
+}
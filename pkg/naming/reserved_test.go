@@ -0,0 +1,92 @@
+package naming
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+func newTestRules(caseSensitive bool) *AgentNamingRules {
+	return &AgentNamingRules{
+		MaxLength:        50,
+		MinLength:        3,
+		AllowedPattern:   regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9\-_]*[a-zA-Z0-9]$`),
+		ReservedNames:    getReservedNames(),
+		CaseSensitive:    caseSensitive,
+		AllowNumbers:     true,
+		AllowHyphens:     true,
+		AllowUnderscores: true,
+	}
+}
+
+func TestAddReservedNames(t *testing.T) {
+	rules := newTestRules(false)
+	rules.AddReservedNames(" AcmeCorp ", "billing-svc")
+
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"acmecorp", false},
+		{"ACMECORP", false},
+		{"billing-svc", false},
+		{"admin", false}, // built-in defaults are kept
+		{"acme-trader", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateAgentName(tt.name, rules).IsValid; got != tt.valid {
+				t.Errorf("ValidateAgentName(%q).IsValid = %v, want %v", tt.name, got, tt.valid)
+			}
+		})
+	}
+
+	strict := newTestRules(true)
+	strict.AddReservedNames("AcmeCorp")
+	if ValidateAgentName("AcmeCorp", strict).IsValid {
+		t.Error("case-sensitive rules should reserve the exact spelling")
+	}
+	if !ValidateAgentName("acmecorp", strict).IsValid {
+		t.Error("case-sensitive rules should not reserve other spellings")
+	}
+}
+
+func TestAddReservedNamesFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reserved.txt")
+	content := "# brand terms\nacme\n\n  widgetco  \n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := newTestRules(false)
+	if err := rules.AddReservedNamesFromFile(path); err != nil {
+		t.Fatalf("AddReservedNamesFromFile: %v", err)
+	}
+	for _, name := range []string{"acme", "widgetco"} {
+		if ValidateAgentName(name, rules).IsValid {
+			t.Errorf("%q from the file should be reserved", name)
+		}
+	}
+	if err := rules.AddReservedNamesFromFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestAddReservedNamesConcurrent(t *testing.T) {
+	rules := newTestRules(false)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			rules.AddReservedNames("concurrent-name")
+		}()
+		go func() {
+			defer wg.Done()
+			ValidateAgentName("some-agent", rules)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,98 @@
+package naming
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateAgentNameUnique validates name like ValidateAgentName and also
+// checks it against the names already registered. An exact match (after the
+// rules' normalization) is an error. A name within the rules'
+// SimilarityThreshold edit distance of an existing one (ignoring case,
+// hyphens and underscores, so "signal-shield" is close to "signalshield")
+// is a warning, or an error when SimilarityStrict is set. Those rejections
+// are counted in the validation stats as ReasonTaken and ReasonSimilar
+func ValidateAgentNameUnique(name string, existing []string, rules *AgentNamingRules) *ValidationResult {
+	if rules == nil {
+		rules = DefaultAgentNamingRules
+	}
+	result, reasons := validateAgentName(name, rules)
+	if result.NormalizedName != "" {
+		reasons = append(reasons, checkExisting(result, existing, rules)...)
+	}
+	recordValidation(reasons)
+	return result
+}
+
+// checkExisting adds the duplicate and similarity findings for result to it
+// and returns their failure reasons, each at most once
+func checkExisting(result *ValidationResult, existing []string, rules *AgentNamingRules) []string {
+	taken, similar := false, false
+	proposed := similarityKey(result.NormalizedName)
+	for _, other := range existing {
+		normalized := strings.TrimSpace(other)
+		if !rules.CaseSensitive {
+			normalized = strings.ToLower(normalized)
+		}
+		if normalized == "" {
+			continue
+		}
+		if normalized == result.NormalizedName {
+			result.IsValid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("'%s' is already taken", other))
+			taken = true
+			continue
+		}
+		if rules.SimilarityThreshold <= 0 {
+			continue
+		}
+		d := levenshtein(proposed, similarityKey(normalized))
+		if d > rules.SimilarityThreshold {
+			continue
+		}
+		msg := fmt.Sprintf("'%s' is confusingly similar to existing agent '%s' (edit distance %d)", result.NormalizedName, other, d)
+		if rules.SimilarityStrict {
+			result.IsValid = false
+			result.Errors = append(result.Errors, msg)
+			similar = true
+		} else {
+			result.Warnings = append(result.Warnings, msg)
+		}
+	}
+
+	var reasons []string
+	if taken {
+		reasons = append(reasons, ReasonTaken)
+	}
+	if similar {
+		reasons = append(reasons, ReasonSimilar)
+	}
+	return reasons
+}
+
+// similarityKey strips what readers gloss over when comparing names
+func similarityKey(name string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,66 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"shield", "sheild", 2},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAgentNameUnique(t *testing.T) {
+	existing := []string{"signalshield", "market-maker"}
+
+	tests := []struct {
+		name        string
+		proposed    string
+		rules       *AgentNamingRules
+		wantValid   bool
+		wantWarning bool
+	}{
+		{"exact duplicate", "SignalShield", DefaultAgentNamingRules, false, false},
+		{"separator only", "signal-shield", DefaultAgentNamingRules, true, true},
+		{"typo", "signal-sheild", DefaultAgentNamingRules, true, true},
+		{"distinct", "price-oracle", DefaultAgentNamingRules, true, false},
+		{"strict errors", "signal-sheild-agent", &AgentNamingRules{
+			MaxLength: 50, MinLength: 3, AllowedPattern: StrictAgentNamingRules.AllowedPattern,
+			AllowNumbers: true, AllowHyphens: true, SimilarityThreshold: 8, SimilarityStrict: true,
+		}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateAgentNameUnique(tt.proposed, existing, tt.rules)
+			if result.IsValid != tt.wantValid {
+				t.Errorf("IsValid = %v, want %v (errors: %v)", result.IsValid, tt.wantValid, result.Errors)
+			}
+			warned := false
+			for _, w := range result.Warnings {
+				if strings.Contains(w, "confusingly similar") {
+					warned = true
+				}
+			}
+			if warned != tt.wantWarning {
+				t.Errorf("similarity warning = %v, want %v (warnings: %v)", warned, tt.wantWarning, result.Warnings)
+			}
+		})
+	}
+
+	off := &AgentNamingRules{MaxLength: 50, MinLength: 3, AllowedPattern: DefaultAgentNamingRules.AllowedPattern, AllowHyphens: true}
+	if r := ValidateAgentNameUnique("signal-shield", existing, off); len(r.Warnings) != 0 {
+		t.Errorf("threshold 0 should disable the check, got %v", r.Warnings)
+	}
+}
@@ -0,0 +1,68 @@
+package naming
+
+import "sync"
+
+// Failure reasons counted by GetValidationStats; a name failing several
+// checks counts once under each
+const (
+	ReasonEmpty         = "empty"
+	ReasonTooShort      = "too_short"
+	ReasonTooLong       = "too_long"
+	ReasonBadPattern    = "bad_pattern"
+	ReasonReserved      = "reserved"
+	ReasonMissingPrefix = "missing_prefix"
+	ReasonMissingSuffix = "missing_suffix"
+	ReasonNumbers       = "numbers"
+	ReasonHyphens       = "hyphens"
+	ReasonUnderscores   = "underscores"
+	ReasonTaken         = "taken"   // ValidateAgentNameUnique: exact duplicate
+	ReasonSimilar       = "similar" // ValidateAgentNameUnique: too close to an existing name, with SimilarityStrict
+)
+
+// ValidationStats counts ValidateAgentName and ValidateAgentNameUnique outcomes since start (or the last
+// ResetValidationStats)
+type ValidationStats struct {
+	Total    int64            `json:"total"`
+	Passed   int64            `json:"passed"`
+	Failed   int64            `json:"failed"`
+	ByReason map[string]int64 `json:"by_reason"`
+}
+
+var validationStats = struct {
+	sync.Mutex
+	ValidationStats
+}{ValidationStats: ValidationStats{ByReason: map[string]int64{}}}
+
+// recordValidation counts one validation; no reasons means it passed
+func recordValidation(reasons []string) {
+	validationStats.Lock()
+	defer validationStats.Unlock()
+	validationStats.Total++
+	if len(reasons) == 0 {
+		validationStats.Passed++
+		return
+	}
+	validationStats.Failed++
+	for _, r := range reasons {
+		validationStats.ByReason[r]++
+	}
+}
+
+// GetValidationStats returns a snapshot of the validation counters
+func GetValidationStats() ValidationStats {
+	validationStats.Lock()
+	defer validationStats.Unlock()
+	out := validationStats.ValidationStats
+	out.ByReason = make(map[string]int64, len(validationStats.ByReason))
+	for r, n := range validationStats.ByReason {
+		out.ByReason[r] = n
+	}
+	return out
+}
+
+// ResetValidationStats zeroes the validation counters
+func ResetValidationStats() {
+	validationStats.Lock()
+	defer validationStats.Unlock()
+	validationStats.ValidationStats = ValidationStats{ByReason: map[string]int64{}}
+}
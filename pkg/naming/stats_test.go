@@ -0,0 +1,86 @@
+package naming
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestValidationStats(t *testing.T) {
+	ResetValidationStats()
+
+	ValidateAgentName("market-analyst", DefaultAgentNamingRules)
+	ValidateAgentName("admin", DefaultAgentNamingRules)
+	ValidateAgentName("ab", DefaultAgentNamingRules)
+	ValidateAgentName("", DefaultAgentNamingRules)
+	ValidateAgentName("Bad_Name", StrictAgentNamingRules) // pattern, suffix and underscores
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ValidateAgentName("another-agent-name", DefaultAgentNamingRules)
+		}()
+	}
+	wg.Wait()
+
+	stats := GetValidationStats()
+	if stats.Total != 15 || stats.Passed != 11 || stats.Failed != 4 {
+		t.Errorf("total/passed/failed = %d/%d/%d, want 15/11/4", stats.Total, stats.Passed, stats.Failed)
+	}
+	want := map[string]int64{
+		ReasonReserved:      1,
+		ReasonTooShort:      1,
+		ReasonEmpty:         1,
+		ReasonBadPattern:    1,
+		ReasonMissingSuffix: 1,
+		ReasonUnderscores:   1,
+	}
+	for reason, n := range want {
+		if stats.ByReason[reason] != n {
+			t.Errorf("ByReason[%s] = %d, want %d (all: %v)", reason, stats.ByReason[reason], n, stats.ByReason)
+		}
+	}
+
+	stats.ByReason[ReasonReserved] = 99
+	if GetValidationStats().ByReason[ReasonReserved] != 1 {
+		t.Error("GetValidationStats should return a copy")
+	}
+}
+
+func TestValidationStatsUnique(t *testing.T) {
+	strict := &AgentNamingRules{
+		MaxLength: 50, MinLength: 3, AllowedPattern: DefaultAgentNamingRules.AllowedPattern,
+		AllowNumbers: true, AllowHyphens: true, SimilarityThreshold: 2, SimilarityStrict: true,
+	}
+	existing := []string{"signalshield", "market-maker"}
+
+	tests := []struct {
+		name       string
+		proposed   string
+		rules      *AgentNamingRules
+		wantPassed int64
+		wantReason string
+	}{
+		{"unique", "price-oracle", DefaultAgentNamingRules, 1, ""},
+		{"taken", "SignalShield", DefaultAgentNamingRules, 0, ReasonTaken},
+		{"similar is only a warning", "signal-sheild", DefaultAgentNamingRules, 1, ""},
+		{"similar under strict rules", "signal-sheild", strict, 0, ReasonSimilar},
+		{"rule failure still counted", "admin", DefaultAgentNamingRules, 0, ReasonReserved},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResetValidationStats()
+			ValidateAgentNameUnique(tt.proposed, existing, tt.rules)
+
+			stats := GetValidationStats()
+			if stats.Total != 1 || stats.Passed != tt.wantPassed || stats.Failed != 1-tt.wantPassed {
+				t.Errorf("total/passed/failed = %d/%d/%d, want 1/%d/%d",
+					stats.Total, stats.Passed, stats.Failed, tt.wantPassed, 1-tt.wantPassed)
+			}
+			if tt.wantReason != "" && stats.ByReason[tt.wantReason] != 1 {
+				t.Errorf("ByReason = %v, want %s counted once", stats.ByReason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -42,6 +42,19 @@ func (v *AgentNameValidator) ValidateName(name string) *types.AgentNameValidatio
 	}
 }
 
+// ValidateNameUnique validates an agent name against the rules and the names
+// already registered (see ValidateAgentNameUnique)
+func (v *AgentNameValidator) ValidateNameUnique(name string, existing []string) *types.AgentNameValidation {
+	result := ValidateAgentNameUnique(name, existing, v.rules)
+
+	return &types.AgentNameValidation{
+		IsValid:        result.IsValid,
+		NormalizedName: result.NormalizedName,
+		Errors:         result.Errors,
+		Warnings:       result.Warnings,
+	}
+}
+
 // NormalizeName normalizes an agent name according to the validator's rules
 func (v *AgentNameValidator) NormalizeName(name string) string {
 	return NormalizeAgentName(name, v.rules)
@@ -47,6 +47,7 @@ type CircuitBreaker struct {
 	
 	mu               sync.RWMutex
 	onStateChange    func(from, to CircuitState)
+	onProbeResult    func(success bool)
 }
 
 // NewCircuitBreaker creates a new circuit breaker
@@ -66,6 +67,14 @@ func (cb *CircuitBreaker) SetStateChangeHandler(handler func(from, to CircuitSta
 	cb.onStateChange = handler
 }
 
+// SetProbeResultHandler sets a callback for the outcome of each half-open
+// probe, e.g. to log or alarm on repeated probe failures
+func (cb *CircuitBreaker) SetProbeResultHandler(handler func(success bool)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.onProbeResult = handler
+}
+
 // Call executes the given function if the circuit allows it
 func (cb *CircuitBreaker) Call(fn func() error) error {
 	if !cb.CanAttempt() {
@@ -137,6 +146,7 @@ func (cb *CircuitBreaker) recordFailure(currentState CircuitState) {
 		}
 		
 	case CircuitHalfOpen:
+		cb.notifyProbeLocked(false)
 		// Single failure in half-open state opens the circuit
 		cb.transitionToLocked(CircuitOpen)
 		cb.halfOpenAttempts = 0
@@ -150,6 +160,7 @@ func (cb *CircuitBreaker) recordSuccess(currentState CircuitState) {
 	
 	switch currentState {
 	case CircuitHalfOpen:
+		cb.notifyProbeLocked(true)
 		cb.successCount++
 		if cb.successCount >= cb.halfOpenRequests {
 			// Successfully tested, close the circuit
@@ -167,6 +178,14 @@ func (cb *CircuitBreaker) recordSuccess(currentState CircuitState) {
 	}
 }
 
+// notifyProbeLocked reports a half-open probe outcome (must hold lock)
+func (cb *CircuitBreaker) notifyProbeLocked(success bool) {
+	if cb.onProbeResult != nil {
+		// Call handler without holding lock to prevent deadlock
+		go cb.onProbeResult(success)
+	}
+}
+
 // transitionTo transitions to a new state (thread-safe)
 func (cb *CircuitBreaker) transitionTo(newState CircuitState) {
 	cb.mu.Lock()
@@ -90,6 +90,13 @@ func NewNetworkClient(config *Config) *NetworkClient {
 	client.circuitBreaker.SetStateChangeHandler(func(from, to CircuitState) {
 		log.Printf("🔌 Circuit breaker state changed: %s → %s", from, to)
 	})
+	client.circuitBreaker.SetProbeResultHandler(func(success bool) {
+		if success {
+			log.Printf("🔌 Circuit breaker half-open probe succeeded")
+		} else {
+			log.Printf("⚠️ Circuit breaker half-open probe failed")
+		}
+	})
 
 	client.retryQueue = NewMessageRetryQueue(DefaultRetryPolicy(), client.sendMessageDirect)
 
@@ -597,6 +604,11 @@ func (c *NetworkClient) GetHealthReport() string {
 	return c.healthMonitor.GetHealthReport()
 }
 
+// GetHealthMetrics returns a snapshot of the connection health metrics
+func (c *NetworkClient) GetHealthMetrics() ConnectionMetrics {
+	return c.healthMonitor.GetMetrics()
+}
+
 // GetCircuitBreakerStats returns circuit breaker statistics
 func (c *NetworkClient) GetCircuitBreakerStats() CircuitBreakerStats {
 	return c.circuitBreaker.GetStats()
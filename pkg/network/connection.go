@@ -1,16 +1,32 @@
 package network
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
 	"time"
 )
 
 // ReconnectionManager handles automatic reconnection logic
 type ReconnectionManager struct {
-	enabled     bool
-	attempts    int
-	maxAttempts int
-	delay       time.Duration
-	backoffFunc func(int) time.Duration
+	enabled       bool
+	attempts      int
+	maxAttempts   int
+	delay         time.Duration
+	backoffFunc   func(int) time.Duration
+	healthMonitor *HealthMonitor
+}
+
+// NewReconnectionManager creates a new reconnection manager.
+// A nil backoffFunc waits the fixed delay between attempts.
+func NewReconnectionManager(enabled bool, maxAttempts int, delay time.Duration, backoffFunc func(int) time.Duration) *ReconnectionManager {
+	return &ReconnectionManager{
+		enabled:     enabled,
+		maxAttempts: maxAttempts,
+		delay:       delay,
+		backoffFunc: backoffFunc,
+	}
 }
 
 // ShouldReconnect returns whether reconnection should be attempted
@@ -55,3 +71,86 @@ func (r *ReconnectionManager) SetEnabled(enabled bool) {
 func (r *ReconnectionManager) IncrementAttempts() {
 	r.attempts++
 }
+
+// SetHealthMonitor sets the health monitor that RunWithReconnect reports
+// connection and reconnection events to
+func (r *ReconnectionManager) SetHealthMonitor(hm *HealthMonitor) {
+	r.healthMonitor = hm
+}
+
+// establishedKey is the context key carrying the RunWithReconnect callback
+type establishedKey struct{}
+
+// ConnectionEstablished notifies RunWithReconnect that the connect function
+// passed to it has successfully connected. Call it with the context given to
+// connect, before blocking on the connection.
+func ConnectionEstablished(ctx context.Context) {
+	if fn, ok := ctx.Value(establishedKey{}).(func()); ok {
+		fn()
+	}
+}
+
+// RunWithReconnect runs connect and reconnects with backoff whenever it fails.
+//
+// connect should dial, call ConnectionEstablished once the connection is up,
+// then block until the connection drops. A connection that was established
+// and later dropped resets the attempt counter, so only consecutive failures
+// count towards maxAttempts. RunWithReconnect returns nil when connect
+// returns nil, the context error when ctx is done, or the last connect error
+// once reconnection attempts are exhausted.
+func (r *ReconnectionManager) RunWithReconnect(ctx context.Context, connect func(ctx context.Context) error) error {
+	reconnecting := false
+
+	for {
+		var established int32
+		connCtx := context.WithValue(ctx, establishedKey{}, func() {
+			if !atomic.CompareAndSwapInt32(&established, 0, 1) {
+				return
+			}
+			if r.healthMonitor != nil {
+				if reconnecting {
+					r.healthMonitor.RecordReconnectAttempt(true)
+				}
+				r.healthMonitor.RecordConnectionEstablished()
+			}
+		})
+
+		err := connect(connCtx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			return nil
+		}
+
+		if atomic.LoadInt32(&established) == 1 {
+			// The connection was up before it dropped, start the backoff sequence over
+			log.Printf("🔌 Connection lost: %v", err)
+			r.Reset()
+			if r.healthMonitor != nil {
+				r.healthMonitor.RecordConnectionLost()
+			}
+		} else {
+			log.Printf("❌ Connection failed: %v", err)
+			if reconnecting && r.healthMonitor != nil {
+				r.healthMonitor.RecordReconnectAttempt(false)
+			}
+		}
+
+		if !r.ShouldReconnect() {
+			return fmt.Errorf("giving up after %d reconnection attempts: %w", r.attempts, err)
+		}
+
+		r.IncrementAttempts()
+		backoff := r.NextBackoff()
+		reconnecting = true
+
+		log.Printf("🔄 Reconnection attempt %d/%d in %v...", r.attempts, r.maxAttempts, backoff)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
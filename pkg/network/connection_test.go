@@ -0,0 +1,150 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// connect step outcomes for the fake connect func
+const (
+	stepFail   = iota // dial fails
+	stepDrop          // connects, then the connection drops
+	stepClean         // connects and shuts down cleanly
+	stepCancel        // the caller's context is cancelled mid-connection
+)
+
+func TestRunWithReconnect(t *testing.T) {
+	errDial := errors.New("dial refused")
+	errDrop := errors.New("connection reset")
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		maxAttempts int
+		steps       []int
+		wantCalls   int
+		wantBackoff []int // attempt numbers passed to the backoff func
+		wantErr     error
+		wantNilErr  bool
+		wantRecon   [2]int64 // reconnect attempts recorded, successful ones
+	}{
+		{
+			name: "gives up after max consecutive failures", enabled: true, maxAttempts: 3,
+			steps:       []int{stepFail, stepFail, stepFail, stepFail},
+			wantCalls:   4,
+			wantBackoff: []int{1, 2, 3},
+			wantErr:     errDial,
+			wantRecon:   [2]int64{3, 0},
+		},
+		{
+			name: "established connection resets the backoff", enabled: true, maxAttempts: 3,
+			steps:       []int{stepFail, stepFail, stepDrop, stepFail, stepFail, stepFail},
+			wantCalls:   6,
+			wantBackoff: []int{1, 2, 1, 2, 3},
+			wantErr:     errDial,
+			wantRecon:   [2]int64{5, 1},
+		},
+		{
+			name: "clean shutdown after a reconnect returns nil", enabled: true, maxAttempts: 3,
+			steps:       []int{stepDrop, stepFail, stepClean},
+			wantCalls:   3,
+			wantBackoff: []int{1, 2},
+			wantNilErr:  true,
+			wantRecon:   [2]int64{2, 1},
+		},
+		{
+			name: "disabled returns the first error", enabled: false, maxAttempts: 3,
+			steps:     []int{stepDrop},
+			wantCalls: 1,
+			wantErr:   errDrop,
+		},
+		{
+			name: "cancelled context wins over the connect error", enabled: true, maxAttempts: 3,
+			steps:       []int{stepFail, stepCancel},
+			wantCalls:   2,
+			wantBackoff: []int{1},
+			wantErr:     context.Canceled,
+			wantRecon:   [2]int64{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var backoffs []int
+			r := NewReconnectionManager(tt.enabled, tt.maxAttempts, 0, func(attempt int) time.Duration {
+				backoffs = append(backoffs, attempt)
+				return time.Millisecond
+			})
+			hm := NewHealthMonitor(time.Hour)
+			r.SetHealthMonitor(hm)
+
+			calls := 0
+			err := r.RunWithReconnect(ctx, func(ctx context.Context) error {
+				if calls >= len(tt.steps) {
+					t.Fatalf("connect called %d times, script has %d steps", calls+1, len(tt.steps))
+				}
+				step := tt.steps[calls]
+				calls++
+				switch step {
+				case stepFail:
+					return errDial
+				case stepDrop:
+					ConnectionEstablished(ctx)
+					return errDrop
+				case stepClean:
+					ConnectionEstablished(ctx)
+					return nil
+				default: // stepCancel
+					ConnectionEstablished(ctx)
+					cancel()
+					return errDrop
+				}
+			})
+
+			if tt.wantNilErr {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+			} else if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("connect called %d times, want %d", calls, tt.wantCalls)
+			}
+			if !slices.Equal(backoffs, tt.wantBackoff) {
+				t.Errorf("backoff attempts = %v, want %v", backoffs, tt.wantBackoff)
+			}
+			m := hm.GetMetrics()
+			if got := [2]int64{m.ReconnectAttempts, m.SuccessfulReconnects}; got != tt.wantRecon {
+				t.Errorf("reconnects (total, ok) = %v, want %v", got, tt.wantRecon)
+			}
+		})
+	}
+}
+
+func TestRunWithReconnectCancelDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewReconnectionManager(true, 5, time.Hour, nil)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- r.RunWithReconnect(ctx, func(context.Context) error { return errors.New("dial refused") })
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunWithReconnect kept sleeping after the context was cancelled")
+	}
+}
@@ -37,6 +37,41 @@ func (s HealthStatus) String() string {
 	}
 }
 
+// Severity ranks the status from best (0) to worst, for comparisons and
+// roll-ups. Unlike the enum values, Unknown ranks between Healthy and
+// Degraded: not known to be broken, but not known to be fine either
+func (s HealthStatus) Severity() int {
+	switch s {
+	case HealthHealthy:
+		return 0
+	case HealthDegraded:
+		return 2
+	case HealthUnhealthy:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// AggregateStatus rolls several component statuses up into one: the worst
+// by Severity. Unknown outranks Healthy, so an overall Healthy means every
+// component reported healthy. No statuses at all is Unknown
+func AggregateStatus(statuses ...HealthStatus) HealthStatus {
+	if len(statuses) == 0 {
+		return HealthUnknown
+	}
+	worst := HealthHealthy
+	for _, s := range statuses {
+		if s.Severity() > worst.Severity() {
+			worst = s
+		}
+	}
+	if worst.Severity() == HealthUnknown.Severity() {
+		return HealthUnknown // normalize out-of-range values
+	}
+	return worst
+}
+
 // ConnectionMetrics tracks connection health metrics
 type ConnectionMetrics struct {
 	// Counters
@@ -44,6 +79,7 @@ type ConnectionMetrics struct {
 	SentMessages       int64
 	ReceivedMessages   int64
 	FailedMessages     int64
+	TimedOutRequests   int64
 	ReconnectAttempts  int64
 	SuccessfulReconnects int64
 	
@@ -58,6 +94,7 @@ type ConnectionMetrics struct {
 	IsAuthenticated    bool
 	CurrentLatency     time.Duration
 	AverageLatency     time.Duration
+	EMALatency         time.Duration // exponential moving average, weights recent samples more heavily
 	
 	// Errors
 	ConsecutiveErrors  int
@@ -88,6 +125,13 @@ type HealthMonitor struct {
 	latencyWindow   []time.Duration
 	latencyWindowMu sync.Mutex
 	maxLatencySamples int
+	emaAlpha          float64
+	emaLatency        time.Duration
+	
+	// Request/response correlation
+	pendingRequests map[string]time.Time
+	pendingMu       sync.Mutex
+	requestTimeout  time.Duration
 }
 
 // NewHealthMonitor creates a new health monitor
@@ -103,7 +147,10 @@ func NewHealthMonitor(checkInterval time.Duration) *HealthMonitor {
 		unhealthyThreshold: 5,  // 5 consecutive errors = unhealthy
 		degradedThreshold:  3,  // 3 consecutive errors = degraded
 		maxLatencySamples: 100,
+		emaAlpha:          0.2,
 		latencyWindow:     make([]time.Duration, 0, 100),
+		pendingRequests:   make(map[string]time.Time),
+		requestTimeout:    30 * time.Second,
 	}
 }
 
@@ -131,6 +178,28 @@ func (hm *HealthMonitor) SetStatusChangeHandler(handler func(old, new HealthStat
 	hm.onStatusChange = handler
 }
 
+// SetRequestTimeout sets how long a request may wait for its response before
+// it is counted as a failure. Pending requests are only checked every check
+// interval, so a request is expired between timeout and timeout+checkInterval
+// after it was sent
+func (hm *HealthMonitor) SetRequestTimeout(timeout time.Duration) {
+	hm.pendingMu.Lock()
+	defer hm.pendingMu.Unlock()
+	hm.requestTimeout = timeout
+}
+
+// SetLatencyEMAAlpha sets the smoothing factor for the EMA latency, in (0, 1].
+// Higher values react faster to recent samples.
+func (hm *HealthMonitor) SetLatencyEMAAlpha(alpha float64) {
+	if alpha <= 0 || alpha > 1 {
+		return
+	}
+	
+	hm.latencyWindowMu.Lock()
+	defer hm.latencyWindowMu.Unlock()
+	hm.emaAlpha = alpha
+}
+
 // monitorHealth continuously monitors connection health
 func (hm *HealthMonitor) monitorHealth() {
 	defer hm.wg.Done()
@@ -144,6 +213,7 @@ func (hm *HealthMonitor) monitorHealth() {
 			return
 			
 		case <-ticker.C:
+			hm.expirePendingRequests()
 			hm.performHealthCheck()
 		}
 	}
@@ -288,10 +358,74 @@ func (hm *HealthMonitor) RecordLatency(latency time.Duration) {
 	}
 	avgLatency := total / time.Duration(len(hm.latencyWindow))
 	
+	// Update the EMA, seeding it with the first sample
+	if hm.emaLatency == 0 {
+		hm.emaLatency = latency
+	} else {
+		hm.emaLatency = time.Duration(hm.emaAlpha*float64(latency) + (1-hm.emaAlpha)*float64(hm.emaLatency))
+	}
+	emaLatency := hm.emaLatency
+	
 	// Update metrics
 	hm.metrics.mu.Lock()
 	hm.metrics.CurrentLatency = latency
 	hm.metrics.AverageLatency = avgLatency
+	hm.metrics.EMALatency = emaLatency
+	hm.metrics.mu.Unlock()
+}
+
+// RecordRequestSent starts tracking a request awaiting a response
+func (hm *HealthMonitor) RecordRequestSent(id string) {
+	hm.pendingMu.Lock()
+	defer hm.pendingMu.Unlock()
+	
+	hm.pendingRequests[id] = time.Now()
+}
+
+// RecordResponseReceived completes a tracked request and records its round-trip latency.
+// Responses for unknown or already expired requests are ignored.
+func (hm *HealthMonitor) RecordResponseReceived(id string) {
+	hm.pendingMu.Lock()
+	sentAt, exists := hm.pendingRequests[id]
+	delete(hm.pendingRequests, id)
+	hm.pendingMu.Unlock()
+	
+	if !exists {
+		return
+	}
+	
+	hm.RecordLatency(time.Since(sentAt))
+}
+
+// PendingRequests returns the number of requests still awaiting a response
+func (hm *HealthMonitor) PendingRequests() int {
+	hm.pendingMu.Lock()
+	defer hm.pendingMu.Unlock()
+	return len(hm.pendingRequests)
+}
+
+// expirePendingRequests drops requests that never got a response and records them as failures
+func (hm *HealthMonitor) expirePendingRequests() {
+	hm.pendingMu.Lock()
+	expired := 0
+	for id, sentAt := range hm.pendingRequests {
+		if time.Since(sentAt) > hm.requestTimeout {
+			delete(hm.pendingRequests, id)
+			expired++
+		}
+	}
+	hm.pendingMu.Unlock()
+	
+	if expired == 0 {
+		return
+	}
+	
+	log.Printf("⏱️ %d request(s) timed out without a response", expired)
+	
+	hm.metrics.mu.Lock()
+	hm.metrics.TimedOutRequests += int64(expired)
+	hm.metrics.FailedMessages += int64(expired)
+	hm.metrics.ConsecutiveErrors += expired
 	hm.metrics.mu.Unlock()
 }
 
@@ -310,6 +444,7 @@ func (hm *HealthMonitor) GetMetrics() ConnectionMetrics {
 		SentMessages:         hm.metrics.SentMessages,
 		ReceivedMessages:     hm.metrics.ReceivedMessages,
 		FailedMessages:       hm.metrics.FailedMessages,
+		TimedOutRequests:     hm.metrics.TimedOutRequests,
 		ReconnectAttempts:    hm.metrics.ReconnectAttempts,
 		SuccessfulReconnects: hm.metrics.SuccessfulReconnects,
 		LastMessageSent:      hm.metrics.LastMessageSent,
@@ -320,6 +455,7 @@ func (hm *HealthMonitor) GetMetrics() ConnectionMetrics {
 		IsAuthenticated:      hm.metrics.IsAuthenticated,
 		CurrentLatency:       hm.metrics.CurrentLatency,
 		AverageLatency:       hm.metrics.AverageLatency,
+		EMALatency:           hm.metrics.EMALatency,
 		ConsecutiveErrors:    hm.metrics.ConsecutiveErrors,
 		LastError:            hm.metrics.LastError,
 		LastErrorTime:        hm.metrics.LastErrorTime,
@@ -354,6 +490,7 @@ Messages:
   Sent: %d
   Received: %d
   Failed: %d
+  Timed Out Requests: %d
 
 Reconnections:
   Attempts: %d
@@ -364,6 +501,7 @@ Reconnections:
 Latency:
   Current: %v
   Average: %v
+  EMA: %v
 
 Errors:
   Consecutive: %d
@@ -378,12 +516,14 @@ Errors:
 		metrics.SentMessages,
 		metrics.ReceivedMessages,
 		metrics.FailedMessages,
+		metrics.TimedOutRequests,
 		metrics.ReconnectAttempts,
 		metrics.SuccessfulReconnects,
 		successRate,
 		metrics.LastReconnect,
 		metrics.CurrentLatency,
 		metrics.AverageLatency,
+		metrics.EMALatency,
 		metrics.ConsecutiveErrors,
 		metrics.LastError,
 		metrics.LastErrorTime,
@@ -0,0 +1,164 @@
+package network
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthStatusSeverity(t *testing.T) {
+	if !(HealthUnhealthy.Severity() > HealthDegraded.Severity() &&
+		HealthDegraded.Severity() > HealthUnknown.Severity() &&
+		HealthUnknown.Severity() > HealthHealthy.Severity()) {
+		t.Errorf("severity order wrong: unhealthy=%d degraded=%d unknown=%d healthy=%d",
+			HealthUnhealthy.Severity(), HealthDegraded.Severity(), HealthUnknown.Severity(), HealthHealthy.Severity())
+	}
+	if got := HealthStatus(42).Severity(); got != HealthUnknown.Severity() {
+		t.Errorf("out-of-range status severity = %d, want unknown's %d", got, HealthUnknown.Severity())
+	}
+}
+
+func TestAggregateStatus(t *testing.T) {
+	tests := []struct {
+		in   []HealthStatus
+		want HealthStatus
+	}{
+		{nil, HealthUnknown},
+		{[]HealthStatus{HealthHealthy, HealthHealthy}, HealthHealthy},
+		{[]HealthStatus{HealthHealthy, HealthUnknown}, HealthUnknown},
+		{[]HealthStatus{HealthUnknown, HealthDegraded, HealthHealthy}, HealthDegraded},
+		{[]HealthStatus{HealthDegraded, HealthUnhealthy, HealthHealthy}, HealthUnhealthy},
+		{[]HealthStatus{HealthHealthy, HealthStatus(42)}, HealthUnknown},
+	}
+	for _, tt := range tests {
+		if got := AggregateStatus(tt.in...); got != tt.want {
+			t.Errorf("AggregateStatus(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHealthMonitorRequestTracking(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+	tests := []struct {
+		name        string
+		run         func(hm *HealthMonitor)
+		wantPending int
+		wantTimeout int64
+		wantLatency bool
+	}{
+		{
+			name: "response completes the request",
+			run: func(hm *HealthMonitor) {
+				hm.RecordRequestSent("a")
+				hm.RecordResponseReceived("a")
+				hm.expirePendingRequests()
+			},
+			wantLatency: true,
+		},
+		{
+			name: "unknown response is ignored",
+			run:  func(hm *HealthMonitor) { hm.RecordResponseReceived("nope") },
+		},
+		{
+			name: "request inside the timeout stays pending",
+			run: func(hm *HealthMonitor) {
+				hm.RecordRequestSent("a")
+				hm.expirePendingRequests()
+			},
+			wantPending: 1,
+		},
+		{
+			name: "request past the timeout expires",
+			run: func(hm *HealthMonitor) {
+				hm.RecordRequestSent("a")
+				hm.RecordRequestSent("b")
+				time.Sleep(2 * timeout)
+				hm.RecordRequestSent("c")
+				hm.expirePendingRequests()
+			},
+			wantPending: 1,
+			wantTimeout: 2,
+		},
+		{
+			name: "late response after expiry is ignored",
+			run: func(hm *HealthMonitor) {
+				hm.RecordRequestSent("a")
+				time.Sleep(2 * timeout)
+				hm.expirePendingRequests()
+				hm.RecordResponseReceived("a")
+			},
+			wantTimeout: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hm := NewHealthMonitor(time.Hour)
+			hm.SetRequestTimeout(timeout)
+			tt.run(hm)
+
+			if got := hm.PendingRequests(); got != tt.wantPending {
+				t.Errorf("pending = %d, want %d", got, tt.wantPending)
+			}
+			m := hm.GetMetrics()
+			if m.TimedOutRequests != tt.wantTimeout || m.FailedMessages != tt.wantTimeout ||
+				int64(m.ConsecutiveErrors) != tt.wantTimeout {
+				t.Errorf("timed out %d, failed %d, consecutive errors %d; want %d each",
+					m.TimedOutRequests, m.FailedMessages, m.ConsecutiveErrors, tt.wantTimeout)
+			}
+			if (m.CurrentLatency > 0) != tt.wantLatency {
+				t.Errorf("latency = %v, want recorded = %v", m.CurrentLatency, tt.wantLatency)
+			}
+		})
+	}
+}
+
+func TestHealthMonitorExpiresOnItsInterval(t *testing.T) {
+	hm := NewHealthMonitor(10 * time.Millisecond)
+	hm.SetRequestTimeout(20 * time.Millisecond)
+	hm.Start()
+	defer hm.Stop()
+
+	hm.RecordRequestSent("a")
+	// expired no later than timeout + interval, with slack for the scheduler
+	deadline := time.Now().Add(time.Second)
+	for hm.PendingRequests() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("request never expired while the monitor was running")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := hm.GetMetrics().TimedOutRequests; got != 1 {
+		t.Errorf("TimedOutRequests = %d, want 1", got)
+	}
+}
+
+func TestLatencyEMA(t *testing.T) {
+	ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+	tests := []struct {
+		name    string
+		alpha   float64 // passed to SetLatencyEMAAlpha; 0 keeps the default
+		samples []time.Duration
+		want    time.Duration
+	}{
+		{"first sample seeds the EMA", 0, []time.Duration{ms(100)}, ms(100)},
+		{"default alpha 0.2", 0, []time.Duration{ms(100), ms(200)}, ms(120)},
+		{"custom alpha", 0.5, []time.Duration{ms(100), ms(200)}, ms(150)},
+		{"alpha 1 tracks the last sample", 1, []time.Duration{ms(100), ms(200), ms(50)}, ms(50)},
+		{"negative alpha is ignored", -0.5, []time.Duration{ms(100), ms(200)}, ms(120)},
+		{"alpha above 1 is ignored", 1.5, []time.Duration{ms(100), ms(200)}, ms(120)},
+		{"zero-latency samples keep reseeding", 0, []time.Duration{0, ms(100)}, ms(100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hm := NewHealthMonitor(time.Hour)
+			if tt.alpha != 0 {
+				hm.SetLatencyEMAAlpha(tt.alpha)
+			}
+			for _, s := range tt.samples {
+				hm.RecordLatency(s)
+			}
+			if got := hm.GetMetrics().EMALatency; got != tt.want {
+				t.Errorf("EMALatency = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
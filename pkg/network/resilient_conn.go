@@ -0,0 +1,264 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// resilientPongWait is how long a connection may go without a message or pong
+// before it is considered dead
+const resilientPongWait = 60 * time.Second
+
+// ResilientConn wraps a WebSocket connection with the package's resilience
+// components: sends go through a circuit breaker, traffic is recorded on a
+// health monitor, dropped connections are re-dialed by a reconnection manager
+// and the read loop runs under a goroutine supervisor.
+type ResilientConn struct {
+	url          string
+	dialer       *websocket.Dialer
+	onMessage    func([]byte)
+	pingInterval time.Duration
+	pongWait     time.Duration // read deadline, extended by every message and pong
+
+	conn    *websocket.Conn
+	mu      sync.RWMutex
+	writeMu sync.Mutex // gorilla/websocket allows a single concurrent writer
+
+	circuitBreaker *CircuitBreaker
+	healthMonitor  *HealthMonitor
+	reconnector    *ReconnectionManager
+	supervisor     *GoroutineSupervisor
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewResilientConn creates a resilient connection for config.WebSocketURL.
+// onMessage is called from the read loop for every message received. The
+// connection is pinged every config.PingInterval (default 25s) so a quiet
+// server doesn't trip the 60s read deadline.
+func NewResilientConn(ctx context.Context, config *Config, onMessage func([]byte)) *ResilientConn {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	connCtx, cancel := context.WithCancel(ctx)
+
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = config.HandshakeTimeout
+
+	pingInterval := config.PingInterval
+	if pingInterval <= 0 || pingInterval >= resilientPongWait {
+		pingInterval = 25 * time.Second
+	}
+
+	rc := &ResilientConn{
+		url:            config.WebSocketURL,
+		dialer:         &dialer,
+		onMessage:      onMessage,
+		pingInterval:   pingInterval,
+		pongWait:       resilientPongWait,
+		circuitBreaker: NewCircuitBreaker(3, 30*time.Second),
+		healthMonitor:  NewHealthMonitor(10 * time.Second),
+		reconnector:    NewReconnectionManager(config.ReconnectEnabled, config.MaxReconnects, config.ReconnectDelay, exponentialBackoff),
+		supervisor:     NewGoroutineSupervisor(connCtx),
+		ctx:            connCtx,
+		cancel:         cancel,
+	}
+
+	rc.reconnector.SetHealthMonitor(rc.healthMonitor)
+	rc.healthMonitor.SetHealthCheckFunc(rc.healthCheck)
+	rc.circuitBreaker.SetStateChangeHandler(func(from, to CircuitState) {
+		log.Printf("🔌 Circuit breaker state changed: %s → %s", from, to)
+	})
+	rc.circuitBreaker.SetProbeResultHandler(func(success bool) {
+		if success {
+			log.Printf("🔌 Circuit breaker half-open probe succeeded")
+		} else {
+			log.Printf("⚠️ Circuit breaker half-open probe failed")
+		}
+	})
+
+	return rc
+}
+
+// Start starts the health monitor and the supervised read loop
+func (rc *ResilientConn) Start() error {
+	if err := rc.supervisor.Register("resilient-read-loop", "Resilient Read Loop", rc.run, DefaultRestartPolicy()); err != nil {
+		return fmt.Errorf("failed to register read loop: %w", err)
+	}
+
+	rc.healthMonitor.Start()
+	if err := rc.supervisor.Start(); err != nil {
+		rc.healthMonitor.Stop()
+		return fmt.Errorf("failed to start supervisor: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops the read loop and closes the underlying connection
+func (rc *ResilientConn) Close() error {
+	rc.cancel()
+	rc.supervisor.Stop()
+	rc.healthMonitor.Stop()
+
+	rc.mu.Lock()
+	conn := rc.conn
+	rc.conn = nil
+	rc.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	rc.writeMu.Lock()
+	conn.WriteMessage(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	rc.writeMu.Unlock()
+
+	return conn.Close()
+}
+
+// Send writes a text message through the circuit breaker
+func (rc *ResilientConn) Send(data []byte) error {
+	return rc.circuitBreaker.Call(func() error {
+		conn := rc.getConn()
+		if conn == nil {
+			rc.healthMonitor.RecordMessageFailed()
+			return fmt.Errorf("not connected")
+		}
+
+		rc.writeMu.Lock()
+		err := conn.WriteMessage(websocket.TextMessage, data)
+		rc.writeMu.Unlock()
+
+		if err != nil {
+			rc.healthMonitor.RecordMessageFailed()
+			return fmt.Errorf("failed to write message: %w", err)
+		}
+
+		rc.healthMonitor.RecordMessageSent()
+		return nil
+	})
+}
+
+// IsConnected returns whether the underlying connection is currently up
+func (rc *ResilientConn) IsConnected() bool {
+	return rc.getConn() != nil
+}
+
+// run is the supervised goroutine: it keeps the connection alive until ctx is done
+func (rc *ResilientConn) run(ctx context.Context) error {
+	return rc.reconnector.RunWithReconnect(ctx, rc.connectAndRead)
+}
+
+// connectAndRead dials the server and reads messages until the connection drops
+func (rc *ResilientConn) connectAndRead(ctx context.Context) error {
+	conn, _, err := rc.dialer.DialContext(ctx, rc.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to WebSocket: %w", err)
+	}
+
+	rc.mu.Lock()
+	rc.conn = conn
+	rc.mu.Unlock()
+
+	ConnectionEstablished(ctx)
+	log.Printf("🔗 Connected to WebSocket server: %s", rc.url)
+
+	// Unblock ReadMessage when the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	// A quiet server still answers pings, so pongs keep the read deadline alive
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(rc.pongWait))
+	})
+	conn.SetReadDeadline(time.Now().Add(rc.pongWait))
+	go rc.pingLoop(conn, done)
+
+	defer func() {
+		rc.mu.Lock()
+		if rc.conn == conn {
+			rc.conn = nil
+		}
+		rc.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read error: %w", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(rc.pongWait))
+
+		rc.healthMonitor.RecordMessageReceived()
+		if rc.onMessage != nil {
+			rc.onMessage(data)
+		}
+	}
+}
+
+// pingLoop pings conn every pingInterval until done is closed. A failed ping
+// closes conn so the read loop returns and the connection is redialed.
+func (rc *ResilientConn) pingLoop(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(rc.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			// WriteControl may run concurrently with the other writers
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+				log.Printf("⚠️ Ping failed: %v", err)
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// getConn returns the connection safely
+func (rc *ResilientConn) getConn() *websocket.Conn {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return rc.conn
+}
+
+// healthCheck performs a health check for the health monitor
+func (rc *ResilientConn) healthCheck() error {
+	if rc.getConn() == nil {
+		return fmt.Errorf("not connected")
+	}
+	return nil
+}
+
+// GetHealthReport returns a health report for the connection
+func (rc *ResilientConn) GetHealthReport() string {
+	return rc.healthMonitor.GetHealthReport()
+}
+
+// GetCircuitBreakerStats returns circuit breaker statistics
+func (rc *ResilientConn) GetCircuitBreakerStats() CircuitBreakerStats {
+	return rc.circuitBreaker.GetStats()
+}
+
+// GetSupervisorStatus returns the status of the supervised read loop
+func (rc *ResilientConn) GetSupervisorStatus() map[string]GoroutineStatus {
+	return rc.supervisor.GetStatus()
+}
@@ -0,0 +1,215 @@
+package network
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newWSServer starts a WebSocket server that runs handle for every
+// connection, numbering them from 1.
+func newWSServer(t *testing.T, handle func(n int32, c *websocket.Conn)) (url string, conns *atomic.Int32) {
+	t.Helper()
+	conns = new(atomic.Int32)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		handle(conns.Add(1), c)
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http"), conns
+}
+
+// newTestResilientConn returns a ResilientConn for url that redials after
+// 10ms instead of the production backoff, closed when the test ends.
+func newTestResilientConn(t *testing.T, url string, onMessage func([]byte)) *ResilientConn {
+	t.Helper()
+	rc := NewResilientConn(context.Background(), &Config{
+		WebSocketURL:     url,
+		ReconnectEnabled: true,
+		MaxReconnects:    5,
+		HandshakeTimeout: time.Second,
+	}, onMessage)
+	rc.reconnector = NewReconnectionManager(true, 5, 10*time.Millisecond, nil)
+	rc.reconnector.SetHealthMonitor(rc.healthMonitor)
+	t.Cleanup(func() { rc.Close() })
+	return rc
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestResilientConnReconnectsUnderSupervisor(t *testing.T) {
+	received := make(chan string, 4)
+	url, conns := newWSServer(t, func(n int32, c *websocket.Conn) {
+		if n == 1 {
+			// first connection drops right after one message
+			c.WriteMessage(websocket.TextMessage, []byte("one"))
+			return
+		}
+		c.WriteMessage(websocket.TextMessage, []byte("two"))
+		for {
+			_, data, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			received <- string(data)
+		}
+	})
+
+	got := make(chan string, 4)
+	rc := newTestResilientConn(t, url, func(b []byte) { got <- string(b) })
+	if err := rc.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"one", "two"} {
+		select {
+		case msg := <-got:
+			if msg != want {
+				t.Fatalf("message = %q, want %q", msg, want)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("no %q message; %d server connections", want, conns.Load())
+		}
+	}
+	waitFor(t, "reconnected conn", rc.IsConnected)
+
+	if err := rc.Send([]byte("hello")); err != nil {
+		t.Fatalf("Send after reconnect: %v", err)
+	}
+	select {
+	case msg := <-received:
+		if msg != "hello" {
+			t.Errorf("server received %q, want hello", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("server never received the message sent after reconnecting")
+	}
+
+	if err := rc.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if rc.IsConnected() {
+		t.Error("IsConnected after Close")
+	}
+	if n := conns.Load(); n != 2 {
+		t.Errorf("server saw %d connections, want 2", n)
+	}
+	// the reconnector handled the drop; the supervisor never had to restart the loop
+	status := rc.GetSupervisorStatus()["resilient-read-loop"]
+	if status.RestartCount != 0 || status.Running {
+		t.Errorf("supervisor status = %+v, want stopped with no restarts", status)
+	}
+	m := rc.healthMonitor.GetMetrics()
+	if m.SuccessfulReconnects != 1 || m.ReceivedMessages != 2 || m.SentMessages != 1 {
+		t.Errorf("metrics: reconnects=%d received=%d sent=%d, want 1/2/1",
+			m.SuccessfulReconnects, m.ReceivedMessages, m.SentMessages)
+	}
+}
+
+func TestResilientConnSendCircuitBreaker(t *testing.T) {
+	received := make(chan string, 1)
+	url, _ := newWSServer(t, func(_ int32, c *websocket.Conn) {
+		for {
+			_, data, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			received <- string(data)
+		}
+	})
+	rc := newTestResilientConn(t, url, nil)
+	rc.circuitBreaker = NewCircuitBreaker(3, 50*time.Millisecond)
+
+	// not started: every send fails until the breaker opens
+	for i := range 3 {
+		if err := rc.Send([]byte("x")); err == nil || !strings.Contains(err.Error(), "not connected") {
+			t.Fatalf("send %d: err = %v, want not connected", i+1, err)
+		}
+	}
+	if st := rc.circuitBreaker.GetState(); st != CircuitOpen {
+		t.Fatalf("breaker state = %s after 3 failures, want open", st)
+	}
+	if err := rc.Send([]byte("x")); err == nil || !strings.Contains(err.Error(), "circuit breaker is open") {
+		t.Fatalf("send while open: err = %v, want circuit breaker is open", err)
+	}
+	if failed := rc.healthMonitor.GetMetrics().FailedMessages; failed != 3 {
+		t.Errorf("FailedMessages = %d, want 3 (a rejected send never reaches the connection)", failed)
+	}
+
+	if err := rc.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "connection", rc.IsConnected)
+	time.Sleep(60 * time.Millisecond) // past the reset timeout
+
+	// the half-open probe goes through and closes the breaker
+	if err := rc.Send([]byte("probe")); err != nil {
+		t.Fatalf("half-open probe: %v", err)
+	}
+	if st := rc.circuitBreaker.GetState(); st != CircuitClosed {
+		t.Errorf("breaker state = %s after a successful probe, want closed", st)
+	}
+	select {
+	case msg := <-received:
+		if msg != "probe" {
+			t.Errorf("server received %q, want probe", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("server never received the probe")
+	}
+}
+
+func TestResilientConnIdleServer(t *testing.T) {
+	tests := []struct {
+		name         string
+		pingInterval time.Duration
+		wantConns    func(n int32) bool
+	}{
+		{"pongs keep an idle connection alive", 40 * time.Millisecond, func(n int32) bool { return n == 1 }},
+		{"without pings the read deadline expires", time.Hour, func(n int32) bool { return n >= 2 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the server never writes; reading lets it answer pings with pongs
+			url, conns := newWSServer(t, func(_ int32, c *websocket.Conn) {
+				for {
+					if _, _, err := c.ReadMessage(); err != nil {
+						return
+					}
+				}
+			})
+			rc := newTestResilientConn(t, url, nil)
+			rc.pongWait = 150 * time.Millisecond
+			rc.pingInterval = tt.pingInterval
+			if err := rc.Start(); err != nil {
+				t.Fatal(err)
+			}
+			waitFor(t, "connection", rc.IsConnected)
+
+			time.Sleep(5 * rc.pongWait)
+			if n := conns.Load(); !tt.wantConns(n) {
+				t.Errorf("server saw %d connections after idling past the read deadline", n)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package network
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -50,11 +53,16 @@ func DefaultRestartPolicy() RestartPolicy {
 // GoroutineSupervisor manages and supervises goroutines
 type GoroutineSupervisor struct {
 	goroutines map[string]*SupervisedGoroutine
+	order      []string // registration order, used by Start
 	mu         sync.RWMutex
 	ctx        context.Context
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
 	running    int32 // atomic
+
+	// startup pacing, see SetStartupLimit
+	startBatchSize int
+	startInterval  time.Duration
 }
 
 // NewGoroutineSupervisor creates a new goroutine supervisor
@@ -89,26 +97,52 @@ func (gs *GoroutineSupervisor) Register(id, name string, fn GoroutineFunc, polic
 	}
 	
 	gs.goroutines[id] = sg
+	gs.order = append(gs.order, id)
 	
 	log.Printf("👁️ Registered goroutine: %s (%s)", name, id)
 	return nil
 }
 
-// Start starts the supervisor and all registered goroutines
+// SetStartupLimit makes Start launch goroutines batchSize at a time, waiting
+// interval between batches, so a large set doesn't hit shared dependencies all
+// at once. batchSize <= 0 (the default) starts everything immediately.
+// Must be called before Start.
+func (gs *GoroutineSupervisor) SetStartupLimit(batchSize int, interval time.Duration) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.startBatchSize = batchSize
+	gs.startInterval = interval
+}
+
+// Start starts the supervisor and all registered goroutines, in registration
+// order and paced by SetStartupLimit if set
 func (gs *GoroutineSupervisor) Start() error {
 	if !atomic.CompareAndSwapInt32(&gs.running, 0, 1) {
 		return fmt.Errorf("supervisor already running")
 	}
 	
 	gs.mu.RLock()
-	goroutines := make([]*SupervisedGoroutine, 0, len(gs.goroutines))
-	for _, sg := range gs.goroutines {
-		goroutines = append(goroutines, sg)
+	goroutines := make([]*SupervisedGoroutine, 0, len(gs.order))
+	for _, id := range gs.order {
+		goroutines = append(goroutines, gs.goroutines[id])
 	}
+	batchSize, interval := gs.startBatchSize, gs.startInterval
 	gs.mu.RUnlock()
 	
-	// Start all goroutines
-	for _, sg := range goroutines {
+	if batchSize <= 0 {
+		batchSize = len(goroutines)
+	}
+	
+	// Start goroutines batch by batch
+	for i, sg := range goroutines {
+		if i > 0 && i%batchSize == 0 && interval > 0 {
+			log.Printf("👁️ Started %d/%d goroutines, next batch in %v", i, len(goroutines), interval)
+			select {
+			case <-time.After(interval):
+			case <-gs.ctx.Done():
+				return gs.ctx.Err()
+			}
+		}
 		gs.startGoroutine(sg)
 	}
 	
@@ -153,9 +187,14 @@ func (gs *GoroutineSupervisor) Stop() {
 	log.Println("👁️ Supervisor stopped")
 }
 
-// startGoroutine starts a supervised goroutine
+// startGoroutine starts a supervised goroutine. It holds mu so Stop, which
+// cancels under mu, either sees the new context or finds the supervisor
+// stopped here first (a paced Start can still be running when Stop is called)
 func (gs *GoroutineSupervisor) startGoroutine(sg *SupervisedGoroutine) {
-	if atomic.LoadInt32(&sg.running) == 1 {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	
+	if atomic.LoadInt32(&gs.running) == 0 || atomic.LoadInt32(&sg.running) == 1 {
 		return
 	}
 	
@@ -258,8 +297,11 @@ func (gs *GoroutineSupervisor) RestartGoroutine(id string) error {
 	}
 	
 	// Stop the goroutine
-	if sg.cancel != nil {
-		sg.cancel()
+	gs.mu.RLock()
+	cancel := sg.cancel
+	gs.mu.RUnlock()
+	if cancel != nil {
+		cancel()
 	}
 	
 	// Wait a moment for it to stop
@@ -295,7 +337,8 @@ func (gs *GoroutineSupervisor) GetStatus() map[string]GoroutineStatus {
 	return status
 }
 
-// GoroutineStatus represents the status of a supervised goroutine
+// GoroutineStatus represents the status of a supervised goroutine; its JSON
+// shape is defined by MarshalJSON
 type GoroutineStatus struct {
 	ID           string
 	Name         string
@@ -305,6 +348,63 @@ type GoroutineStatus struct {
 	LastRestart  time.Time
 }
 
+// MarshalJSON renders LastError as its message and LastRestart as RFC3339,
+// omitting either when unset
+func (s GoroutineStatus) MarshalJSON() ([]byte, error) {
+	out := struct {
+		ID           string `json:"id"`
+		Name         string `json:"name"`
+		Running      bool   `json:"running"`
+		RestartCount int    `json:"restart_count"`
+		LastError    string `json:"last_error,omitempty"`
+		LastRestart  string `json:"last_restart,omitempty"`
+	}{
+		ID:           s.ID,
+		Name:         s.Name,
+		Running:      s.Running,
+		RestartCount: s.RestartCount,
+	}
+	if s.LastError != nil {
+		out.LastError = s.LastError.Error()
+	}
+	if !s.LastRestart.IsZero() {
+		out.LastRestart = s.LastRestart.Format(time.RFC3339)
+	}
+	return json.Marshal(out)
+}
+
+// StatusReport returns a human-readable summary of all supervised goroutines,
+// one line each, sorted by ID
+func (gs *GoroutineSupervisor) StatusReport() string {
+	status := gs.GetStatus()
+	ids := make([]string, 0, len(status))
+	for id := range status {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	metrics := gs.GetMetrics()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Supervisor: %d goroutines (%d running, %d stopped), %d restarts\n",
+		metrics.TotalGoroutines, metrics.RunningGoroutines, metrics.StoppedGoroutines, metrics.TotalRestarts)
+	for _, id := range ids {
+		st := status[id]
+		state := "stopped"
+		if st.Running {
+			state = "running"
+		}
+		fmt.Fprintf(&b, "- %s (%s): %s, %d restarts", st.Name, st.ID, state, st.RestartCount)
+		if st.LastError != nil {
+			fmt.Fprintf(&b, ", last error: %v", st.LastError)
+		}
+		if !st.LastRestart.IsZero() {
+			fmt.Fprintf(&b, ", last restart %s", st.LastRestart.Format(time.RFC3339))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 // IsHealthy checks if all goroutines are healthy
 func (gs *GoroutineSupervisor) IsHealthy() bool {
 	gs.mu.RLock()
@@ -345,8 +445,8 @@ func (gs *GoroutineSupervisor) GetMetrics() SupervisorMetrics {
 
 // SupervisorMetrics contains supervisor metrics
 type SupervisorMetrics struct {
-	TotalGoroutines   int
-	RunningGoroutines int
-	StoppedGoroutines int
-	TotalRestarts     int
+	TotalGoroutines   int `json:"total_goroutines"`
+	RunningGoroutines int `json:"running_goroutines"`
+	StoppedGoroutines int `json:"stopped_goroutines"`
+	TotalRestarts     int `json:"total_restarts"`
 }
\ No newline at end of file
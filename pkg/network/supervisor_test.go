@@ -0,0 +1,107 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervisorStopDuringPacedStart(t *testing.T) {
+	gs := NewGoroutineSupervisor(context.Background())
+	var started atomic.Int32
+	for i := range 6 {
+		id := fmt.Sprintf("g%d", i)
+		gs.Register(id, id, func(ctx context.Context) error {
+			started.Add(1)
+			<-ctx.Done()
+			return nil
+		}, DefaultRestartPolicy())
+	}
+	gs.SetStartupLimit(2, 50*time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() { done <- gs.Start() }()
+	waitFor(t, "first batch", func() bool { return started.Load() == 2 })
+	gs.Stop()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Start = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start kept pacing after Stop")
+	}
+	time.Sleep(100 * time.Millisecond) // past the next batch
+	if n := started.Load(); n != 2 {
+		t.Errorf("%d goroutines started, want only the first batch of 2", n)
+	}
+	if m := gs.GetMetrics(); m.RunningGoroutines != 0 {
+		t.Errorf("%d goroutines still running after Stop", m.RunningGoroutines)
+	}
+}
+
+func TestSupervisorPacedStart(t *testing.T) {
+	gs := NewGoroutineSupervisor(context.Background())
+	var started atomic.Int32
+	for i := range 5 {
+		id := fmt.Sprintf("g%d", i)
+		gs.Register(id, id, func(ctx context.Context) error {
+			started.Add(1)
+			<-ctx.Done()
+			return nil
+		}, DefaultRestartPolicy())
+	}
+	gs.SetStartupLimit(2, 20*time.Millisecond)
+	defer gs.Stop()
+
+	begin := time.Now()
+	if err := gs.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// three batches, two waits between them
+	if d := time.Since(begin); d < 40*time.Millisecond {
+		t.Errorf("Start returned after %v, want at least 40ms of pacing", d)
+	}
+	waitFor(t, "all goroutines", func() bool { return started.Load() == 5 })
+	if !gs.IsHealthy() {
+		t.Errorf("supervisor unhealthy after start:\n%s", gs.StatusReport())
+	}
+}
+
+func TestGoroutineStatusJSON(t *testing.T) {
+	restarted := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name   string
+		status GoroutineStatus
+		want   string
+	}{
+		{
+			name:   "unset error and restart are omitted",
+			status: GoroutineStatus{ID: "read", Name: "read loop", Running: true},
+			want:   `{"id":"read","name":"read loop","running":true,"restart_count":0}`,
+		},
+		{
+			name: "error as its message, restart as RFC3339",
+			status: GoroutineStatus{ID: "read", Name: "read loop", RestartCount: 2,
+				LastError: errors.New("connection reset"), LastRestart: restarted},
+			want: `{"id":"read","name":"read loop","running":false,"restart_count":2,` +
+				`"last_error":"connection reset","last_restart":"2026-03-01T12:00:00+01:00"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json = %s\nwant   %s", got, tt.want)
+			}
+		})
+	}
+}
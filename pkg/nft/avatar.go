@@ -0,0 +1,107 @@
+package nft
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// IPFSPinner uploads a file to IPFS and returns its URI (e.g. "ipfs://<cid>")
+type IPFSPinner interface {
+	PinFile(ctx context.Context, name, contentType string, data []byte) (string, error)
+}
+
+const (
+	avatarGrid = 5  // identicon cells per side
+	avatarCell = 48 // pixels per cell
+	avatarPad  = 24 // border around the grid
+)
+
+// GenerateAvatarSVG renders a deterministic identicon-style avatar for an
+// agent: a 5x5 horizontally mirrored grid whose pattern and colour are derived
+// from the name and owner address, so the same agent always gets the same image
+func GenerateAvatarSVG(name string, owner common.Address) []byte {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(name)) + "|" + owner.Hex()))
+
+	hue := int(sum[0])<<8 | int(sum[1])
+	fg := fmt.Sprintf("hsl(%d,65%%,50%%)", hue%360)
+	bg := fmt.Sprintf("hsl(%d,40%%,94%%)", hue%360)
+
+	size := avatarGrid*avatarCell + 2*avatarPad
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, size, size, size, size)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`, size, size, bg)
+
+	// fill the left half plus the middle column from the hash bits, then mirror
+	half := (avatarGrid + 1) / 2
+	for row := 0; row < avatarGrid; row++ {
+		for col := 0; col < half; col++ {
+			bit := row*half + col
+			if sum[2+bit/8]>>(bit%8)&1 == 0 {
+				continue
+			}
+			for _, c := range []int{col, avatarGrid - 1 - col} {
+				fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`,
+					avatarPad+c*avatarCell, avatarPad+row*avatarCell, avatarCell, avatarCell, fg)
+				if c == avatarGrid-1-c {
+					break
+				}
+			}
+		}
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+// EnableAutoAvatar makes MintAgent/MintAgents fill in a generated avatar when
+// AgentMetadata.Image is empty. With a pinner the SVG is uploaded to IPFS;
+// with nil it is embedded as a data: URI, which marketplaces also render.
+func (m *NFTMinter) EnableAutoAvatar(pinner IPFSPinner) {
+	m.autoAvatar = true
+	m.avatarPinner = pinner
+}
+
+// ensureImage sets metadata.Image to a generated avatar if auto-avatar is
+// enabled and no image was supplied
+func (m *NFTMinter) ensureImage(metadata *AgentMetadata) error {
+	if !m.autoAvatar || strings.TrimSpace(metadata.Image) != "" {
+		return nil
+	}
+	svg := GenerateAvatarSVG(metadata.Name, m.address)
+	if m.avatarPinner == nil {
+		metadata.Image = "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(svg)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	uri, err := m.avatarPinner.PinFile(ctx, avatarFileName(metadata.Name), "image/svg+xml", svg)
+	if err != nil {
+		return fmt.Errorf("failed to pin generated avatar: %w", err)
+	}
+	metadata.Image = uri
+	return nil
+}
+
+// avatarFileName builds a filesystem-safe file name for an agent's avatar
+func avatarFileName(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, strings.TrimSpace(name))
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		slug = "agent"
+	}
+	return slug + "-avatar.svg"
+}
@@ -0,0 +1,52 @@
+package nft
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGenerateAvatarSVG(t *testing.T) {
+	owner := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	base := GenerateAvatarSVG("Signal Shield", owner)
+
+	tests := []struct {
+		name  string
+		agent string
+		owner common.Address
+		same  bool
+	}{
+		{"same inputs", "Signal Shield", owner, true},
+		{"name case and spacing are ignored", "  signal shield ", owner, true},
+		{"different name", "Signal Sword", owner, false},
+		{"different owner", "Signal Shield", other, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateAvatarSVG(tt.agent, tt.owner)
+			if bytes.Equal(got, base) != tt.same {
+				t.Errorf("avatar equal to the base = %v, want %v", !tt.same, tt.same)
+			}
+			if !bytes.HasPrefix(got, []byte("<svg ")) || !bytes.HasSuffix(got, []byte("</svg>")) {
+				t.Errorf("not an SVG document: %.40s...", got)
+			}
+		})
+	}
+}
+
+func TestAvatarFileName(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"Signal Shield", "signal-shield-avatar.svg"},
+		{"  KOL_Bot v2 ", "kol-bot-v2-avatar.svg"},
+		{"ünïcode", "n-code-avatar.svg"},
+		{"!!!", "agent-avatar.svg"},
+		{"", "agent-avatar.svg"},
+	}
+	for _, tt := range tests {
+		if got := avatarFileName(tt.name); got != tt.want {
+			t.Errorf("avatarFileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
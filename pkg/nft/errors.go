@@ -0,0 +1,40 @@
+package nft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrBackendUnavailable is returned when the minting backend can't be reached or returns an unusable response
+	ErrBackendUnavailable = errors.New("backend unavailable")
+
+	// ErrInsufficientFunds is returned when the signer can't cover the mint price plus gas
+	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	// ErrTransactionReverted is returned when the mint transaction reverts, on-chain or in simulation
+	ErrTransactionReverted = errors.New("transaction reverted")
+
+	// ErrSignatureInvalid is returned when the mint signature is missing, malformed or rejected by the contract
+	ErrSignatureInvalid = errors.New("invalid mint signature")
+
+	// ErrTransactionTimeout is returned when waiting for a transaction exceeds its deadline
+	ErrTransactionTimeout = errors.New("transaction timeout")
+)
+
+// wrapTxError wraps an error from the Ethereum node with the sentinel matching
+// its failure mode, so callers can use errors.Is on it
+func wrapTxError(msg string, err error) error {
+	lower := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(lower, "insufficient funds"):
+		return fmt.Errorf("%s: %w: %w", msg, ErrInsufficientFunds, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%s: %w: %w", msg, ErrTransactionTimeout, err)
+	case strings.Contains(lower, "execution reverted"):
+		return fmt.Errorf("%s: %w: %w", msg, ErrTransactionReverted, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
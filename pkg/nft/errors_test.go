@@ -0,0 +1,74 @@
+package nft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestWrapTxError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error // nil: no sentinel
+	}{
+		{"insufficient funds", errors.New("Insufficient funds for gas * price + value"), ErrInsufficientFunds},
+		{"deadline", fmt.Errorf("waiting: %w", context.DeadlineExceeded), ErrTransactionTimeout},
+		{"reverted", errors.New("execution reverted: sold out"), ErrTransactionReverted},
+		{"other", errors.New("connection refused"), nil},
+	}
+	sentinels := []error{ErrInsufficientFunds, ErrTransactionTimeout, ErrTransactionReverted}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapTxError("failed to send", tt.err)
+			if !errors.Is(got, tt.err) {
+				t.Errorf("%v does not wrap the original error", got)
+			}
+			for _, s := range sentinels {
+				if errors.Is(got, s) != (s == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", got, s, !(s == tt.want))
+				}
+			}
+		})
+	}
+}
+
+// rpcDataError mimics the JSON-RPC error go-ethereum returns for a revert
+type rpcDataError struct{ data interface{} }
+
+func (e rpcDataError) Error() string          { return "execution reverted" }
+func (e rpcDataError) ErrorData() interface{} { return e.data }
+
+func TestDecodeRevertReason(t *testing.T) {
+	stringType, _ := abi.NewType("string", "", nil)
+	packed, err := abi.Arguments{{Type: stringType}}.Pack("Max supply reached")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revert := hexutil.Encode(append(append([]byte{}, revertSelector...), packed...))
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Error(string) payload", rpcDataError{revert}, "Max supply reached"},
+		{"wrapped", fmt.Errorf("estimate gas: %w", rpcDataError{revert}), "Max supply reached"},
+		{"no revert data", errors.New("execution reverted"), ""},
+		{"non-string data", rpcDataError{42}, ""},
+		{"bad hex", rpcDataError{"0xzz"}, ""},
+		{"custom error selector", rpcDataError{"0xdeadbeef" + revert[10:]}, ""},
+		{"truncated payload", rpcDataError{revert[:20]}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeRevertReason(tt.err); got != tt.want {
+				t.Errorf("decodeRevertReason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
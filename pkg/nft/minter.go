@@ -7,19 +7,24 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum"
 )
 
 // AgentMetadata represents the metadata for an agent NFT
@@ -60,15 +65,30 @@ type ContractConfigResponse struct {
 	NetworkName     string `json:"network_name"`
 }
 
+// chainClient is the subset of *ethclient.Client the minter uses
+type chainClient interface {
+	bind.DeployBackend
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
 // NFTMinter handles NFT minting operations
 type NFTMinter struct {
-	client          *ethclient.Client
+	client          chainClient // nil without an RPC endpoint
 	contractAddress common.Address
 	backendURL      string
 	chainID         *big.Int
 	privateKey      *ecdsa.PrivateKey
 	address         common.Address
 	httpClient      *http.Client
+	nonces          *nonceManager // optional local nonce tracking, nil uses the chain's pending nonce
+	confirmations   int           // blocks to wait before treating a mint as final
+	confirmTimeout  time.Duration // how long executeMint waits for those confirmations
+	autoAvatar      bool          // generate an image when metadata has none, see EnableAutoAvatar
+	avatarPinner    IPFSPinner    // where generated avatars are uploaded, nil embeds them as data URIs
 }
 
 // NewNFTMinter creates a new NFT minter instance
@@ -87,50 +107,135 @@ func NewNFTMinter(backendURL, rpcEndpoint, privateKeyHex string) (*NFTMinter, er
 	}
 	address := crypto.PubkeyToAddress(*publicKeyECDSA)
 
-	// Create HTTP client with timeout
+	// Create HTTP client with timeout (proxy-aware: PROXY_URL or HTTP(S)_PROXY)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFromEnv
 	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
+		Timeout:   30 * time.Second,
+		Transport: transport,
+	}
+
+	m := &NFTMinter{
+		backendURL:     backendURL,
+		privateKey:     privateKey,
+		address:        address,
+		httpClient:     httpClient,
+		confirmations:  1,
+		confirmTimeout: defaultConfirmTimeout,
 	}
 
 	// Create Ethereum client if RPC endpoint provided
-	var ethClient *ethclient.Client
 	if rpcEndpoint != "" {
-		ethClient, err = ethclient.Dial(rpcEndpoint)
+		ethClient, err := ethclient.Dial(rpcEndpoint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to Ethereum node: %w", err)
 		}
+		m.client = ethClient
 	}
 
-	return &NFTMinter{
-		client:     ethClient,
-		backendURL: backendURL,
-		privateKey: privateKey,
-		address:    address,
-		httpClient: httpClient,
-	}, nil
+	return m, nil
+}
+
+// proxyFromEnv routes backend requests through PROXY_URL when set, otherwise
+// honors the standard HTTP_PROXY / HTTPS_PROXY / NO_PROXY variables
+func proxyFromEnv(req *http.Request) (*url.URL, error) {
+	if p := strings.TrimSpace(os.Getenv("PROXY_URL")); p != "" {
+		return url.Parse(p)
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// SetConfirmations sets how many blocks deep a mint transaction must be before
+// MintAgent treats it as final. Values below 1 are treated as 1.
+func (m *NFTMinter) SetConfirmations(n int) {
+	if n < 1 {
+		n = 1
+	}
+	m.confirmations = n
+}
+
+// SetConfirmationTimeout sets how long a mint waits for its confirmations
+// before failing with ErrTransactionTimeout. Values <= 0 restore the default.
+func (m *NFTMinter) SetConfirmationTimeout(d time.Duration) {
+	if d <= 0 {
+		d = defaultConfirmTimeout
+	}
+	m.confirmTimeout = d
 }
 
 // MintAgent mints a new agent NFT
 func (m *NFTMinter) MintAgent(metadata AgentMetadata) (uint64, error) {
 	fmt.Println("   [Step 1/5] 🔍 Getting contract configuration...")
 	// 1. Get contract configuration from backend
+	if err := m.loadContractConfig(); err != nil {
+		return 0, err
+	}
+
+	return m.mintWithLoadedConfig(metadata)
+}
+
+// MintAgents mints several agent NFTs sequentially, fetching the contract
+// configuration once and tracking transaction nonces locally. Results are
+// returned per item so a single failure doesn't abort the rest of the batch.
+func (m *NFTMinter) MintAgents(metadatas []AgentMetadata) ([]uint64, []error) {
+	tokenIDs := make([]uint64, len(metadatas))
+	errs := make([]error, len(metadatas))
+
+	fmt.Printf("   [Batch] 🔍 Getting contract configuration for %d agents...\n", len(metadatas))
+	if err := m.loadContractConfig(); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return tokenIDs, errs
+	}
+
+	// Sequential sends would otherwise race the mempool's pending nonce. A
+	// minter that wasn't tracking nonces goes back to the chain's afterwards.
+	if m.nonces == nil {
+		m.EnableNonceManager()
+		defer func() { m.nonces = nil }()
+	}
+
+	for i, metadata := range metadatas {
+		fmt.Printf("\n   [Batch %d/%d] 🎨 Minting agent: %s\n", i+1, len(metadatas), metadata.Name)
+		tokenIDs[i], errs[i] = m.mintWithLoadedConfig(metadata)
+		if errs[i] != nil {
+			fmt.Printf("   ❌ Mint failed for %s: %v\n", metadata.Name, errs[i])
+		}
+	}
+
+	return tokenIDs, errs
+}
+
+// loadContractConfig fetches the contract address and chain ID from the backend
+func (m *NFTMinter) loadContractConfig() error {
 	config, err := m.getContractConfig()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get contract config: %w", err)
+		return fmt.Errorf("failed to get contract config: %w", err)
 	}
 
 	// Set contract address
 	m.contractAddress = common.HexToAddress(config.ContractAddress)
 	fmt.Printf("   ✅ Contract address: %s\n", config.ContractAddress)
-	
+
 	// Set chain ID
 	chainID, ok := new(big.Int).SetString(config.ChainID, 10)
 	if !ok {
-		return 0, fmt.Errorf("invalid chain ID: %s", config.ChainID)
+		return fmt.Errorf("invalid chain ID: %s", config.ChainID)
 	}
 	m.chainID = chainID
 	fmt.Printf("   ✅ Chain ID: %s\n", config.ChainID)
 
+	return nil
+}
+
+// mintWithLoadedConfig runs steps 2-5 of the mint flow against the contract
+// configuration already loaded by loadContractConfig
+func (m *NFTMinter) mintWithLoadedConfig(metadata AgentMetadata) (uint64, error) {
+	if err := m.ensureImage(&metadata); err != nil {
+		return 0, err
+	}
+
 	fmt.Println("\n   [Step 2/5] 📤 Uploading metadata to IPFS...")
 	// 2. Send metadata to backend (backend handles IPFS upload via Pinata)
 	ipfsHash, err := m.uploadMetadataToIPFS(metadata)
@@ -156,7 +261,13 @@ func (m *NFTMinter) MintAgent(metadata AgentMetadata) (uint64, error) {
 	}
 
 	fmt.Println("\n   [Step 5/5] ⛓️  Executing blockchain transaction...")
-	// 5. Execute mint transaction on-chain with the signature
+	// 5a. Simulate the mint with eth_call so reverts surface with their reason before spending gas
+	fmt.Println("   🧪 Simulating mint transaction...")
+	if err := m.simulateMint(signature); err != nil {
+		return 0, fmt.Errorf("pre-flight check failed: %w", err)
+	}
+
+	// 5b. Execute mint transaction on-chain with the signature
 	tokenID, err := m.executeMint(signature)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute mint: %w", err)
@@ -188,7 +299,7 @@ func (m *NFTMinter) uploadMetadataToIPFS(metadata AgentMetadata) (string, error)
 	// Send metadata to backend
 	resp, err := m.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request to backend: %w", err)
+		return "", fmt.Errorf("failed to send request to backend: %w: %w", ErrBackendUnavailable, err)
 	}
 	defer resp.Body.Close()
 
@@ -205,7 +316,7 @@ func (m *NFTMinter) uploadMetadataToIPFS(metadata AgentMetadata) (string, error)
 	}
 
 	if !uploadResp.Success {
-		return "", fmt.Errorf("backend upload failed: %s", uploadResp.Error)
+		return "", fmt.Errorf("%w: backend upload failed: %s", ErrBackendUnavailable, uploadResp.Error)
 	}
 
 	// Return IPFS URI that backend created
@@ -229,14 +340,14 @@ func (m *NFTMinter) getContractConfig() (*ContractConfigResponse, error) {
 	// Send request
 	resp, err := m.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w: %w", ErrBackendUnavailable, err)
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("backend returned status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%w: backend returned status %d: %s", ErrBackendUnavailable, resp.StatusCode, string(body))
 	}
 
 	// Read response
@@ -251,7 +362,7 @@ func (m *NFTMinter) getContractConfig() (*ContractConfigResponse, error) {
 		if len(preview) > 100 {
 			preview = preview[:100]
 		}
-		return nil, fmt.Errorf("backend returned HTML instead of JSON. Response starts with: %s", preview)
+		return nil, fmt.Errorf("%w: backend returned HTML instead of JSON. Response starts with: %s", ErrBackendUnavailable, preview)
 	}
 
 	// Parse response
@@ -341,7 +452,7 @@ func (m *NFTMinter) requestMintSignature(to string, tokenURI string, nonce uint6
 	// Send request
 	resp, err := m.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return "", fmt.Errorf("failed to send request: %w: %w", ErrBackendUnavailable, err)
 	}
 	defer resp.Body.Close()
 
@@ -353,7 +464,7 @@ func (m *NFTMinter) requestMintSignature(to string, tokenURI string, nonce uint6
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("backend returned status %d: %s", resp.StatusCode, string(respBody))
+		return "", fmt.Errorf("%w: backend returned status %d: %s", ErrBackendUnavailable, resp.StatusCode, string(respBody))
 	}
 
 	// Log the response status
@@ -378,7 +489,7 @@ func (m *NFTMinter) requestMintSignature(to string, tokenURI string, nonce uint6
 		fmt.Printf("   ❌ Error: %s\n", errorMsg)
 		fmt.Printf("   📄 HTML Response preview:\n%s\n", preview)
 		
-		return "", fmt.Errorf("%sPlease check the backend URL configuration", errorMsg)
+		return "", fmt.Errorf("%w: %sPlease check the backend URL configuration", ErrBackendUnavailable, errorMsg)
 	}
 	
 	// Don't log raw response as it contains sensitive signature data
@@ -404,7 +515,7 @@ func (m *NFTMinter) requestMintSignature(to string, tokenURI string, nonce uint6
 
 	// Validate signature response
 	if sigResp.Signature == "" {
-		return "", fmt.Errorf("backend returned empty signature")
+		return "", fmt.Errorf("%w: backend returned empty signature", ErrSignatureInvalid)
 	}
 	
 	fmt.Printf("   ✅ Received signature successfully\n")
@@ -418,22 +529,10 @@ func (m *NFTMinter) executeMint(signature string) (uint64, error) {
 		return 0, fmt.Errorf("ethereum client not initialized")
 	}
 
-	// Parse the contract ABI
-	contractABI, err := ParseABI()
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse ABI: %w", err)
-	}
-
-	// Decode signature from hex
-	sigBytes, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
-	if err != nil {
-		return 0, fmt.Errorf("failed to decode signature: %w", err)
-	}
-
-	// Pack the mint method call
-	data, err := contractABI.Pack("mint", m.address, sigBytes)
+	// Build the mint calldata
+	data, err := m.packMintCall(signature)
 	if err != nil {
-		return 0, fmt.Errorf("failed to pack mint call: %w", err)
+		return 0, err
 	}
 
 	// Get the current gas price
@@ -443,10 +542,18 @@ func (m *NFTMinter) executeMint(signature string) (uint64, error) {
 	}
 
 	// Get the nonce for the transaction
-	nonce, err := m.client.PendingNonceAt(context.Background(), m.address)
+	nonce, err := m.nextNonce(context.Background())
 	if err != nil {
-		return 0, fmt.Errorf("failed to get account nonce: %w", err)
-	}
+		return 0, err
+	}
+	// Hand the nonce back unless the transaction reaches the node, otherwise
+	// every later send would queue behind the gap
+	sent := false
+	defer func() {
+		if !sent {
+			m.releaseNonce(nonce)
+		}
+	}()
 
 	// Create the transaction
 	tx := types.NewTransaction(
@@ -467,13 +574,19 @@ func (m *NFTMinter) executeMint(signature string) (uint64, error) {
 	// Send the transaction
 	err = m.client.SendTransaction(context.Background(), signedTx)
 	if err != nil {
-		return 0, fmt.Errorf("failed to send transaction: %w", err)
+		// The local nonce may be out of sync with the chain, resync on the next send
+		m.ResetNonce()
+		return 0, wrapTxError("failed to send transaction", err)
 	}
+	sent = true
 
 	fmt.Printf("Mint transaction sent: %s\n", signedTx.Hash().Hex())
 
-	// Wait for transaction receipt
-	receipt, err := m.WaitForTransaction(context.Background(), signedTx)
+	// Wait for the configured number of confirmations, giving up after the
+	// confirmation timeout so a stuck transaction surfaces as ErrTransactionTimeout
+	ctx, cancel := context.WithTimeout(context.Background(), m.confirmTimeout)
+	defer cancel()
+	receipt, err := m.WaitForConfirmations(ctx, signedTx, m.confirmations)
 	if err != nil {
 		return 0, fmt.Errorf("failed to wait for transaction: %w", err)
 	}
@@ -496,6 +609,96 @@ func (m *NFTMinter) executeMint(signature string) (uint64, error) {
 	return 0, fmt.Errorf("could not extract token ID from transaction logs")
 }
 
+// packMintCall builds the calldata for mint(address,bytes) using the backend signature
+func (m *NFTMinter) packMintCall(signature string) ([]byte, error) {
+	// Parse the contract ABI
+	contractABI, err := ParseABI()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %w", err)
+	}
+
+	// Decode signature from hex
+	sigBytes, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w: %w", ErrSignatureInvalid, err)
+	}
+
+	// Pack the mint method call
+	data, err := contractABI.Pack("mint", m.address, sigBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack mint call: %w", err)
+	}
+
+	return data, nil
+}
+
+// simulateMint runs the mint call through eth_call from the signer's address,
+// returning the decoded revert reason if the transaction would fail on-chain
+func (m *NFTMinter) simulateMint(signature string) error {
+	if m.client == nil {
+		return fmt.Errorf("ethereum client not initialized")
+	}
+
+	data, err := m.packMintCall(signature)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.client.CallContract(context.Background(), ethereum.CallMsg{
+		From:  m.address,
+		To:    &m.contractAddress,
+		Value: DefaultMintPrice(),
+		Data:  data,
+	}, nil)
+	if err != nil {
+		if reason := decodeRevertReason(err); reason != "" {
+			if strings.Contains(strings.ToLower(reason), "signature") {
+				return fmt.Errorf("%w: mint would revert: %w: %s", ErrTransactionReverted, ErrSignatureInvalid, reason)
+			}
+			return fmt.Errorf("%w: mint would revert: %s", ErrTransactionReverted, reason)
+		}
+		return wrapTxError("mint simulation failed", err)
+	}
+
+	return nil
+}
+
+// revertSelector is the 4-byte selector of the standard Error(string) revert payload
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// decodeRevertReason extracts the Error(string) reason from an eth_call error.
+// Returns an empty string if the error carries no decodable revert data.
+func decodeRevertReason(err error) string {
+	// RPC errors expose the raw revert data through ErrorData()
+	var dataErr interface{ ErrorData() interface{} }
+	if !errors.As(err, &dataErr) {
+		return ""
+	}
+
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return ""
+	}
+
+	data, err := hexutil.Decode(hexData)
+	if err != nil || len(data) < 4 || !bytes.Equal(data[:4], revertSelector) {
+		return ""
+	}
+
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		return ""
+	}
+
+	values, err := abi.Arguments{{Type: stringType}}.Unpack(data[4:])
+	if err != nil || len(values) == 0 {
+		return ""
+	}
+
+	reason, _ := values[0].(string)
+	return reason
+}
+
 // GenerateMetadataHash generates a SHA256 hash of agent metadata
 func GenerateMetadataHash(metadata AgentMetadata) string {
 	// Create deterministic string representation
@@ -543,13 +746,73 @@ func (m *NFTMinter) WaitForTransaction(ctx context.Context, tx *types.Transactio
 	// Wait for transaction receipt
 	receipt, err := bind.WaitMined(ctx, m.client, tx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
+		return nil, wrapTxError("failed to wait for transaction", err)
 	}
 
 	// Check if transaction was successful
 	if receipt.Status == 0 {
-		return nil, fmt.Errorf("transaction failed")
+		return nil, fmt.Errorf("%w: transaction failed", ErrTransactionReverted)
 	}
 
 	return receipt, nil
-}
\ No newline at end of file
+}
+
+const (
+	// confirmationPollInterval is how often WaitForConfirmations checks the chain head
+	confirmationPollInterval = time.Second
+
+	// defaultConfirmTimeout bounds how long a mint waits for its confirmations
+	defaultConfirmTimeout = 5 * time.Minute
+)
+
+// WaitForConfirmations waits until a transaction is n blocks deep. The receipt is
+// re-checked once the depth is reached so a reorg that drops or moves the
+// transaction restarts the wait instead of returning a stale receipt.
+func (m *NFTMinter) WaitForConfirmations(ctx context.Context, tx *types.Transaction, n int) (*types.Receipt, error) {
+	receipt, err := m.WaitForTransaction(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 1 {
+		return receipt, nil
+	}
+
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := m.client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block number: %w", err)
+		}
+
+		if head >= receipt.BlockNumber.Uint64()+uint64(n-1) {
+			current, err := m.client.TransactionReceipt(ctx, tx.Hash())
+			switch {
+			case errors.Is(err, ethereum.NotFound):
+				// Dropped by a reorg, wait for it to be mined again
+				fmt.Printf("   ⚠️  Transaction %s dropped by reorg, waiting for it to be re-mined\n", tx.Hash().Hex())
+				receipt, err = m.WaitForTransaction(ctx, tx)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			case err != nil:
+				return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+			case current.Status == 0:
+				return nil, fmt.Errorf("%w: transaction failed", ErrTransactionReverted)
+			case current.BlockHash != receipt.BlockHash:
+				// Re-mined in a different block, count confirmations from there
+				receipt = current
+				continue
+			}
+			return current, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, wrapTxError("failed to wait for confirmations", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
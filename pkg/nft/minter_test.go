@@ -0,0 +1,119 @@
+package nft
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestWaitForConfirmations(t *testing.T) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000})
+	mined := func(status uint64) *types.Receipt {
+		return &types.Receipt{Status: status, BlockNumber: big.NewInt(100), BlockHash: common.HexToHash("0x01")}
+	}
+
+	tests := []struct {
+		name    string
+		receipt *types.Receipt // nil: never mined
+		head    uint64
+		n       int
+		wantErr error
+	}{
+		{"one confirmation is the receipt", mined(1), 100, 1, nil},
+		{"deep enough", mined(1), 102, 3, nil},
+		{"reverted", mined(0), 102, 3, ErrTransactionReverted},
+		{"not deep enough before the deadline", mined(1), 101, 3, ErrTransactionTimeout},
+		{"never mined before the deadline", nil, 101, 3, ErrTransactionTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &stubClient{head: tt.head, receipts: map[common.Hash]*types.Receipt{}}
+			if tt.receipt != nil {
+				client.receipts[tx.Hash()] = tt.receipt
+			}
+			m := &NFTMinter{client: client}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			got, err := m.WaitForConfirmations(ctx, tx, tt.n)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.receipt {
+				t.Fatalf("WaitForConfirmations = %v, %v; want the mined receipt", got, err)
+			}
+		})
+	}
+}
+
+func TestExecuteMintConfirmationTimeout(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the transaction is accepted but never mined
+	m := &NFTMinter{
+		client:     &stubClient{pending: 3},
+		chainID:    big.NewInt(8453),
+		privateKey: key,
+		address:    crypto.PubkeyToAddress(key.PublicKey),
+	}
+	m.SetConfirmations(1)
+	m.SetConfirmationTimeout(50 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.executeMint("0x" + strings.Repeat("ab", 65))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrTransactionTimeout) {
+			t.Errorf("err = %v, want ErrTransactionTimeout", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("executeMint kept waiting past the confirmation timeout")
+	}
+}
+
+func TestMintAgentsRestoresNonceManager(t *testing.T) {
+	// the backend serves the contract config and rejects every upload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/contract/config" {
+			w.Write([]byte(`{"contract_address":"0x0000000000000000000000000000000000000001","chain_id":"8453"}`))
+			return
+		}
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	for _, managed := range []bool{false, true} {
+		m := &NFTMinter{backendURL: srv.URL, httpClient: srv.Client(), client: &stubClient{}}
+		var before *nonceManager
+		if managed {
+			m.EnableNonceManager()
+			before = m.nonces
+		}
+		_, errs := m.MintAgents([]AgentMetadata{{Name: "a"}, {Name: "b"}})
+		for i, err := range errs {
+			if err == nil {
+				t.Errorf("managed=%v: mint %d succeeded against a failing backend", managed, i)
+			}
+		}
+		if m.nonces != before {
+			t.Errorf("managed=%v: nonce manager after the batch = %p, want %p", managed, m.nonces, before)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package nft
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// nonceManager tracks the next transaction nonce locally so back-to-back
+// sends don't race the mempool and hit "nonce too low" errors
+type nonceManager struct {
+	mu     sync.Mutex
+	next   uint64
+	synced bool
+}
+
+// EnableNonceManager turns on local nonce tracking for this minter.
+// Useful for provisioning scripts that mint several agents in quick succession.
+func (m *NFTMinter) EnableNonceManager() {
+	if m.nonces == nil {
+		m.nonces = &nonceManager{}
+	}
+}
+
+// ResetNonce forces the next transaction to resync its nonce with the chain
+func (m *NFTMinter) ResetNonce() {
+	if m.nonces == nil {
+		return
+	}
+
+	m.nonces.mu.Lock()
+	m.nonces.synced = false
+	m.nonces.mu.Unlock()
+}
+
+// nextNonce returns the nonce to use for the next transaction. Without a nonce
+// manager it always asks the chain for the pending nonce.
+func (m *NFTMinter) nextNonce(ctx context.Context) (uint64, error) {
+	if m.nonces == nil {
+		nonce, err := m.client.PendingNonceAt(ctx, m.address)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get account nonce: %w", err)
+		}
+		return nonce, nil
+	}
+
+	m.nonces.mu.Lock()
+	defer m.nonces.mu.Unlock()
+
+	// Resync with the chain on first use or after a reset
+	if !m.nonces.synced {
+		nonce, err := m.client.PendingNonceAt(ctx, m.address)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get account nonce: %w", err)
+		}
+		m.nonces.next = nonce
+		m.nonces.synced = true
+	}
+
+	nonce := m.nonces.next
+	m.nonces.next++
+	return nonce, nil
+}
+
+// releaseNonce returns a nonce from nextNonce that was never sent. If it is
+// the latest one handed out it is reused next; otherwise the next transaction
+// resyncs with the chain, whose pending nonce doesn't include it either.
+func (m *NFTMinter) releaseNonce(nonce uint64) {
+	if m.nonces == nil {
+		return
+	}
+
+	m.nonces.mu.Lock()
+	defer m.nonces.mu.Unlock()
+	if m.nonces.synced && m.nonces.next == nonce+1 {
+		m.nonces.next = nonce
+		return
+	}
+	m.nonces.synced = false
+}
@@ -0,0 +1,145 @@
+package nft
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// stubClient is a chainClient whose answers are set by the test
+type stubClient struct {
+	pending      uint64
+	pendingErr   error
+	pendingCalls int
+
+	head     uint64
+	receipts map[common.Hash]*types.Receipt
+	callErr  error
+}
+
+func (c *stubClient) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	c.pendingCalls++
+	return c.pending, c.pendingErr
+}
+
+func (c *stubClient) SuggestGasPrice(context.Context) (*big.Int, error) { return big.NewInt(1), nil }
+
+func (c *stubClient) SendTransaction(context.Context, *types.Transaction) error { return nil }
+
+func (c *stubClient) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return nil, c.callErr
+}
+
+func (c *stubClient) BlockNumber(context.Context) (uint64, error) { return c.head, nil }
+
+func (c *stubClient) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	if r, ok := c.receipts[hash]; ok {
+		return r, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *stubClient) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func TestNonceManager(t *testing.T) {
+	// each step reserves (next), releases, or resets; want is the nonce a
+	// reservation should get
+	const (
+		next = iota
+		release
+		reset
+	)
+	type step struct {
+		op   int
+		arg  uint64 // nonce to release
+		want uint64
+	}
+	tests := []struct {
+		name      string
+		managed   bool
+		steps     []step
+		wantSyncs int // PendingNonceAt calls
+	}{
+		{
+			name:      "unmanaged asks the chain every time",
+			steps:     []step{{op: next, want: 7}, {op: next, want: 7}},
+			wantSyncs: 2,
+		},
+		{
+			name:      "managed syncs once then increments",
+			managed:   true,
+			steps:     []step{{op: next, want: 7}, {op: next, want: 8}, {op: next, want: 9}},
+			wantSyncs: 1,
+		},
+		{
+			name:      "reset resyncs with the chain",
+			managed:   true,
+			steps:     []step{{op: next, want: 7}, {op: next, want: 8}, {op: reset}, {op: next, want: 7}},
+			wantSyncs: 2,
+		},
+		{
+			name:      "releasing the latest nonce reuses it",
+			managed:   true,
+			steps:     []step{{op: next, want: 7}, {op: next, want: 8}, {op: release, arg: 8}, {op: next, want: 8}},
+			wantSyncs: 1,
+		},
+		{
+			name:      "releasing an older nonce resyncs",
+			managed:   true,
+			steps:     []step{{op: next, want: 7}, {op: next, want: 8}, {op: release, arg: 7}, {op: next, want: 7}},
+			wantSyncs: 2,
+		},
+		{
+			name:      "release without a manager is a no-op",
+			steps:     []step{{op: release, arg: 3}, {op: next, want: 7}},
+			wantSyncs: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the chain's pending nonce never moves: nothing is actually sent
+			client := &stubClient{pending: 7}
+			m := &NFTMinter{client: client}
+			if tt.managed {
+				m.EnableNonceManager()
+			}
+			for i, s := range tt.steps {
+				switch s.op {
+				case next:
+					got, err := m.nextNonce(context.Background())
+					if err != nil || got != s.want {
+						t.Fatalf("step %d: nextNonce = %d, %v; want %d", i, got, err, s.want)
+					}
+				case release:
+					m.releaseNonce(s.arg)
+				case reset:
+					m.ResetNonce()
+				}
+			}
+			if client.pendingCalls != tt.wantSyncs {
+				t.Errorf("PendingNonceAt called %d times, want %d", client.pendingCalls, tt.wantSyncs)
+			}
+		})
+	}
+}
+
+func TestNextNonceSyncError(t *testing.T) {
+	client := &stubClient{pendingErr: errors.New("rpc down")}
+	m := &NFTMinter{client: client}
+	m.EnableNonceManager()
+	if _, err := m.nextNonce(context.Background()); err == nil {
+		t.Fatal("nextNonce succeeded without a chain nonce")
+	}
+	// a failed sync must not leave the manager believing it is in sync
+	client.pending, client.pendingErr = 4, nil
+	if got, err := m.nextNonce(context.Background()); err != nil || got != 4 {
+		t.Errorf("nextNonce after recovery = %d, %v; want 4", got, err)
+	}
+}
@@ -0,0 +1,93 @@
+// reload.go
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"signalshield/modules"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultKOLs is used when KOL_LIST is unset.
+var defaultKOLs = []string{"Ansem", "GCR", "TheMoonCarl"}
+
+// loadScannerConfig reads X_POLL_INTERVAL and KOL_LIST.
+func loadScannerConfig() modules.ScannerConfig {
+	cfg := modules.ScannerConfig{IntervalSec: 30, KOLs: defaultKOLs}
+	if s := os.Getenv("X_POLL_INTERVAL"); s != "" {
+		if v, err := strconv.Atoi(s); err == nil {
+			cfg.IntervalSec = v
+		}
+	}
+	if s := os.Getenv("KOL_LIST"); s != "" {
+		parts := strings.Split(s, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		if len(parts) > 0 {
+			cfg.KOLs = parts
+		}
+	}
+	return cfg
+}
+
+// applyCacheTTL applies CACHE_TTL (e.g. "30s") to the market-data cache.
+func applyCacheTTL() {
+	if s := os.Getenv("CACHE_TTL"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil && d > 0 {
+			modules.SetCacheTTL(d)
+		} else {
+			log.Printf("Warning: invalid CACHE_TTL %q", s)
+		}
+	}
+}
+
+// watchReload re-reads .env on SIGHUP and applies the hot-reloadable settings:
+// KOL_LIST and X_POLL_INTERVAL go to the scanner, CACHE_TTL to the market cache,
+// and a fresh modules.Config (RISK_AVERSION, MAX_STALE, SYSTEM_PROMPT,
+// FALLBACK_MOCK_ON_ERROR, REPLY_TZ, ...) replaces the old one.
+func watchReload(ctx context.Context, scanner chan<- modules.ScannerConfig) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Println("SIGHUP received, reloading configuration")
+			if err := godotenv.Overload(); err != nil && !os.IsNotExist(err) {
+				log.Println("Warning: reloading .env failed:", err)
+			}
+			modules.SetConfig(modules.LoadConfig())
+			applyCacheTTL()
+			go logAIKeyCheck(ctx)
+			select {
+			case scanner <- loadScannerConfig():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// logAIKeyCheck test-calls each configured AI provider after a reload, so a
+// rotated key that doesn't work shows up before the old one is revoked.
+func logAIKeyCheck(ctx context.Context) {
+	for provider, err := range modules.ValidateAIKeysContext(ctx) {
+		if err != nil {
+			log.Printf("Warning: %s API key check failed after reload: %v", provider, err)
+		} else {
+			log.Printf("%s API key OK", provider)
+		}
+	}
+}
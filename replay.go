@@ -0,0 +1,27 @@
+// replay.go
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	"signalshield/modules"
+)
+
+// runReplay implements "signalshield replay [detections.jsonl]": re-scores the
+// detection log with the current pipeline and prints JSON lines to stdout.
+func runReplay(args []string) int {
+	filename := modules.DetectionLogPath()
+	if len(args) > 0 {
+		filename = args[0]
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := modules.ReplayDetections(ctx, filename, modules.NewJSONLSink(os.Stdout)); err != nil {
+		log.Printf("replay %s: %v", filename, err)
+		return 1
+	}
+	return 0
+}
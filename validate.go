@@ -0,0 +1,174 @@
+// validate.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"signalshield/modules"
+	"signalshield/pkg/agent"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var privateKeyRe = regexp.MustCompile(`^(0x)?[0-9a-fA-F]{64}$`)
+
+// ValidateConfig checks the agent config and the env-backed settings at startup
+// so a misconfigured agent fails fast instead of erroring hours later.
+// It returns one actionable error per problem.
+func ValidateConfig(cfg *agent.Config) []error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch {
+	case cfg.PrivateKey == "":
+		add("PRIVATE_KEY is required")
+	case !privateKeyRe.MatchString(cfg.PrivateKey):
+		add("PRIVATE_KEY must be 64 hex characters (optionally 0x-prefixed)")
+	}
+
+	if addr := cfg.OwnerAddress; addr != "" {
+		if !common.IsHexAddress(addr) || !strings.HasPrefix(addr, "0x") {
+			add("OWNER_ADDRESS %q is not a 0x-prefixed 20-byte hex address", addr)
+		} else if hexPart := addr[2:]; hexPart != strings.ToLower(hexPart) && hexPart != strings.ToUpper(hexPart) &&
+			common.HexToAddress(addr).Hex() != addr {
+			// mixed case means EIP-55 checksum, which must match
+			add("OWNER_ADDRESS %q has an invalid checksum (expected %s)", addr, common.HexToAddress(addr).Hex())
+		}
+	}
+
+	if id := cfg.NFTTokenID; id != "" {
+		if n, err := strconv.ParseUint(id, 10, 64); err != nil || n == 0 {
+			add("NFT_TOKEN_ID %q must be a positive integer", id)
+		}
+	}
+
+	intEnv := func(name string, min int) {
+		s := os.Getenv(name)
+		if s == "" {
+			return
+		}
+		if v, err := strconv.Atoi(s); err != nil || v < min {
+			add("%s %q must be an integer >= %d", name, s, min)
+		}
+	}
+	intEnv("RATE_LIMIT_PER_MINUTE", 0)
+	intEnv("X_POLL_INTERVAL", 1)
+	intEnv("REPLY_MAX_CHARS", 0)
+	intEnv("HEALTH_PORT", 1)
+	intEnv("HEALTH_PORT_FALLBACK", 0)
+	intEnv("SUMMARY_MAX_TOKENS", 1)
+	intEnv("AI_MAX_TOKENS", 1)
+	intEnv("DETECTION_LOG_MAX_MB", 0)
+	intEnv("DETECTION_LOG_KEEP", 0)
+	intEnv("COINGECKO_RATE_PER_MIN", 0)
+	intEnv("MOCK_MAX_PER_TICK", 1)
+	intEnv("SCANNER_MAX_RESULTS", 1)
+	intEnv("AI_CONTEXT_TOKENS", 0)
+	intEnv("LOG_BUFFER_SIZE", 0)
+
+	durationEnv := func(name string) {
+		s := os.Getenv(name)
+		if s == "" {
+			return
+		}
+		if d, err := time.ParseDuration(s); err != nil || d < 0 {
+			add("%s %q must be a duration like 30s or 10m", name, s)
+		}
+	}
+	durationEnv("CACHE_TTL")
+	durationEnv("MAX_STALE")
+	durationEnv("COMMAND_TIMEOUT")
+	durationEnv("AI_CACHE_TTL")
+	durationEnv("AI_TIMEOUT_BASE")
+	durationEnv("AI_TIMEOUT_PER_TOKEN")
+	durationEnv("DETECTION_LOG_MAX_AGE")
+	durationEnv("COINGECKO_MAX_RETRY_WAIT")
+	durationEnv("SCANNER_LOOKBACK")
+
+	if s := os.Getenv("DIGEST_INTERVAL"); s != "" {
+		if _, err := modules.ParsePeriod(s); err != nil {
+			add("DIGEST_INTERVAL %q must be a duration like 24h or a day count like 7d", s)
+		}
+	}
+
+	if s := os.Getenv("DIGEST_WEBHOOK_FORMAT"); s != "" {
+		if _, err := modules.NotificationFormatterFor(s); err != nil {
+			add("DIGEST_WEBHOOK_FORMAT %q must be plain, slack, discord or telegram", s)
+		}
+	}
+
+	if s := os.Getenv("MOCK_BURSTS"); s != "" {
+		if _, err := modules.ParseMockBursts(s); err != nil {
+			add("MOCK_BURSTS %q: %v (format: count:weight,... e.g. 0:35,1:40,2:15,5:10)", s, err)
+		}
+	}
+	if s := os.Getenv("DUMP_BANDS"); s != "" {
+		if _, err := modules.ParseDumpBands(s); err != nil {
+			add("DUMP_BANDS %q: %v (format: mincap:drop%%,... e.g. 1e9:8,1e8:12,0:25)", s, err)
+		}
+	}
+
+	if s := os.Getenv("SCANNER_MIN_MARKETCAP"); s != "" {
+		if v, err := strconv.ParseFloat(s, 64); err != nil || v < 0 {
+			add("SCANNER_MIN_MARKETCAP %q must be a non-negative number (USD)", s)
+		}
+	}
+
+	if s := os.Getenv("TOKEN_PATTERN"); s != "" {
+		if _, err := regexp.Compile(s); err != nil {
+			add("TOKEN_PATTERN %q is not a valid regular expression: %v", s, err)
+		}
+	}
+
+	if p := os.Getenv("AUDIT_LOG"); p != "" {
+		if fi, err := os.Stat(filepath.Dir(p)); err != nil || !fi.IsDir() {
+			add("AUDIT_LOG %q: directory %s does not exist", p, filepath.Dir(p))
+		}
+	}
+
+	switch s := strings.ToLower(strings.TrimSpace(os.Getenv("CASHTAG_GATE"))); s {
+	case "", modules.CashtagGateStrict, modules.CashtagGateLenient, modules.CashtagGateOff:
+	default:
+		add("CASHTAG_GATE %q must be strict, lenient or off", s)
+	}
+
+	switch s := strings.ToLower(strings.TrimSpace(os.Getenv("X_SCAN_MODE"))); s {
+	case "", modules.XScanSearch, modules.XScanTimeline:
+	default:
+		add("X_SCAN_MODE %q must be search or timeline", s)
+	}
+
+	for _, name := range []string{"SUMMARY_TEMPERATURE", "AI_TEMPERATURE"} {
+		if s := os.Getenv(name); s != "" {
+			if v, err := strconv.ParseFloat(s, 64); err != nil || v < 0 || v > 2 {
+				add("%s %q must be a number between 0 and 2", name, s)
+			}
+		}
+	}
+
+	for _, name := range []string{"RISK_AVERSION", "SCANNER_MIN_CONFIDENCE"} {
+		if s := os.Getenv(name); s != "" {
+			if v, err := strconv.ParseFloat(s, 64); err != nil || v < 0 || v > 1 {
+				add("%s %q must be a number between 0 and 1", name, s)
+			}
+		}
+	}
+
+	if s, ok := os.LookupEnv("KOL_LIST"); ok {
+		for i, kol := range strings.Split(s, ",") {
+			if strings.TrimSpace(kol) == "" {
+				add("KOL_LIST has an empty entry at position %d", i+1)
+			}
+		}
+	}
+
+	return errs
+}